@@ -0,0 +1,63 @@
+package config
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"fork-and-shaker/internal/application"
+)
+
+// LoadAuthConfig reads token settings from the environment:
+//
+//   - JWT_SECRET signs tokens. Without it a random secret is generated, so
+//     tokens stop working when the server restarts.
+//   - ACCESS_TOKEN_TTL and REFRESH_TOKEN_TTL are Go durations such as "15m"
+//     or "720h".
+//   - ADMIN_EMAILS is a comma-separated list of emails that become admins
+//     when they register.
+func LoadAuthConfig() (application.AuthConfig, error) {
+	var cfg application.AuthConfig
+
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		cfg.Secret = []byte(secret)
+	} else {
+		cfg.Secret = make([]byte, 32)
+		if _, err := rand.Read(cfg.Secret); err != nil {
+			return cfg, fmt.Errorf("failed to generate JWT secret: %v", err)
+		}
+		log.Println("JWT_SECRET not set, using a random secret; tokens will not survive a restart")
+	}
+
+	var err error
+	if cfg.AccessTokenTTL, err = durationEnv("ACCESS_TOKEN_TTL"); err != nil {
+		return cfg, err
+	}
+	if cfg.RefreshTokenTTL, err = durationEnv("REFRESH_TOKEN_TTL"); err != nil {
+		return cfg, err
+	}
+
+	for _, email := range strings.Split(os.Getenv("ADMIN_EMAILS"), ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			cfg.AdminEmails = append(cfg.AdminEmails, email)
+		}
+	}
+	return cfg, nil
+}
+
+// durationEnv parses an optional duration environment variable, returning
+// zero when it is unset
+func durationEnv(name string) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%s must be a positive duration such as 15m", name)
+	}
+	return d, nil
+}
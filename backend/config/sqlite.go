@@ -0,0 +1,41 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+
+	"fork-and-shaker/internal/infrastructure/sqlite"
+)
+
+// SQLiteDB is the global SQLite database handle
+var SQLiteDB *sql.DB
+
+// ConnectSQLite opens the embedded SQLite database and migrates its schema
+func ConnectSQLite() error {
+	// Get the database file path from environment variable or use default
+	path := os.Getenv("SQLITE_PATH")
+	if path == "" {
+		path = "fafa.db"
+	}
+
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %v", err)
+	}
+
+	SQLiteDB = db
+
+	log.Printf("Opened SQLite database: %s\n", path)
+	return nil
+}
+
+// DisconnectSQLite closes the SQLite database
+func DisconnectSQLite() {
+	if SQLiteDB != nil {
+		if err := SQLiteDB.Close(); err != nil {
+			log.Printf("Error closing SQLite database: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrTooManyAPIKeys = errors.New("too many API keys; revoke one first")
+)
+
+const (
+	// MaxAPIKeysPerUser caps how many keys one user may hold at once
+	MaxAPIKeysPerUser = 25
+
+	// apiKeyBytes is how much randomness each key carries
+	apiKeyBytes = 32
+	// apiKeyPrefixLength is how much of a key is kept in the clear so its
+	// owner can tell keys apart
+	apiKeyPrefixLength = len(entity.APIKeyPrefix) + 8
+	// lastUsedResolution limits how often a busy key's last-used time is
+	// written back
+	lastUsedResolution = time.Minute
+)
+
+// CreateAPIKey issues a new API key for user. The returned secret is the
+// only time the full key is available; only its hash is stored.
+func (s *AuthService) CreateAPIKey(ctx context.Context, user *entity.User, name string,
+	scope entity.APIKeyScope, expiresAt *time.Time) (*entity.APIKey, string, error) {
+	if user == nil {
+		return nil, "", ErrUnauthorized
+	}
+
+	existing, err := s.apiKeyRepo.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= MaxAPIKeysPerUser {
+		return nil, "", ErrTooManyAPIKeys
+	}
+
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	secret := entity.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	key := entity.NewAPIKey(user.ID, name, scope, expiresAt, secret[:apiKeyPrefixLength], hashAPIKey(secret))
+	if err := key.Validate(); err != nil {
+		return nil, "", err
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// ListAPIKeys lists user's API keys, newest first
+func (s *AuthService) ListAPIKeys(ctx context.Context, user *entity.User) ([]*entity.APIKey, error) {
+	if user == nil {
+		return nil, ErrUnauthorized
+	}
+	return s.apiKeyRepo.FindByUser(ctx, user.ID)
+}
+
+// RevokeAPIKey deletes one of user's API keys. Other users' keys are
+// reported as not found.
+func (s *AuthService) RevokeAPIKey(ctx context.Context, user *entity.User, id primitive.ObjectID) error {
+	if user == nil {
+		return ErrUnauthorized
+	}
+	key, err := s.apiKeyRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key == nil || key.UserID != user.ID {
+		return ErrAPIKeyNotFound
+	}
+	return s.apiKeyRepo.Delete(ctx, id)
+}
+
+// AuthenticateAPIKey returns the user an API key belongs to along with the
+// key, recording when the key was last used
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, secret string) (*entity.User, *entity.APIKey, error) {
+	key, err := s.apiKeyRepo.FindByHash(ctx, hashAPIKey(secret))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if key == nil || key.Expired(now) {
+		return nil, nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, key.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, ErrInvalidToken
+	}
+	if user.Disabled {
+		return nil, nil, ErrAccountDisabled
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			return nil, nil, err
+		}
+		key.LastUsedAt = &now
+	}
+	return user, key, nil
+}
+
+// hashAPIKey hashes a key for storage. Keys are long and random, so a fast
+// hash is enough and lets keys be looked up by their hash.
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
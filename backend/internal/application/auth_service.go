@@ -0,0 +1,231 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrEmailTaken         = errors.New("email already registered")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrAccountDisabled    = errors.New("account is disabled")
+)
+
+const (
+	// DefaultAccessTokenTTL is how long an access token is accepted
+	DefaultAccessTokenTTL = 15 * time.Minute
+	// DefaultRefreshTokenTTL is how long a refresh token can be exchanged
+	// for new tokens
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	tokenIssuer = "fork-and-shaker"
+
+	accessToken  = "access"
+	refreshToken = "refresh"
+)
+
+// AuthConfig configures how tokens are signed and who is an administrator
+type AuthConfig struct {
+	// Secret signs tokens with HMAC-SHA256
+	Secret          []byte
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// AdminEmails are given the admin role when they register
+	AdminEmails []string
+}
+
+// TokenPair is a short-lived access token and the refresh token that
+// replaces it once it expires
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// tokenClaims are the claims of both kinds of token. TokenType keeps a
+// refresh token from being used as an access token and vice versa.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	TokenType string `json:"token_type"`
+}
+
+// AuthService handles user registration, sign-in and the tokens and API
+// keys that authenticate later requests
+type AuthService struct {
+	userRepo   repository.UserRepository
+	apiKeyRepo repository.APIKeyRepository
+	config     AuthConfig
+	admins     map[string]bool
+	// dummyHash is compared against when an email is unknown so failed
+	// logins take as long whether or not the account exists
+	dummyHash []byte
+}
+
+// NewAuthService creates a new AuthService. Zero TTLs use the defaults.
+func NewAuthService(userRepo repository.UserRepository, apiKeyRepo repository.APIKeyRepository,
+	config AuthConfig) *AuthService {
+	if config.AccessTokenTTL <= 0 {
+		config.AccessTokenTTL = DefaultAccessTokenTTL
+	}
+	if config.RefreshTokenTTL <= 0 {
+		config.RefreshTokenTTL = DefaultRefreshTokenTTL
+	}
+	admins := make(map[string]bool, len(config.AdminEmails))
+	for _, email := range config.AdminEmails {
+		if email = entity.NormalizeEmail(email); email != "" {
+			admins[email] = true
+		}
+	}
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+	return &AuthService{
+		userRepo:   userRepo,
+		apiKeyRepo: apiKeyRepo,
+		config:     config,
+		admins:     admins,
+		dummyHash:  dummyHash,
+	}
+}
+
+// Register creates an account and signs it in
+func (s *AuthService) Register(ctx context.Context, email, password, displayName string) (*entity.User, *TokenPair, error) {
+	if err := entity.ValidateCredentials(email, password, displayName); err != nil {
+		return nil, nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	role := entity.RoleUser
+	if s.admins[entity.NormalizeEmail(email)] {
+		role = entity.RoleAdmin
+	}
+	user := entity.NewUser(email, displayName, string(hash), role)
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if err == repository.ErrDuplicateEmail {
+			return nil, nil, ErrEmailTaken
+		}
+		return nil, nil, err
+	}
+
+	tokens, err := s.issueTokens(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// Login checks an email and password and issues new tokens
+func (s *AuthService) Login(ctx context.Context, email, password string) (*entity.User, *TokenPair, error) {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+		return nil, nil, ErrInvalidCredentials
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, nil, ErrInvalidCredentials
+	}
+	if user.Disabled {
+		return nil, nil, ErrAccountDisabled
+	}
+
+	tokens, err := s.issueTokens(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair
+func (s *AuthService) Refresh(ctx context.Context, token string) (*entity.User, *TokenPair, error) {
+	user, err := s.userForToken(ctx, token, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens, err := s.issueTokens(user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// Authenticate returns the user an access token was issued to. The user is
+// loaded fresh so role changes and disabled accounts take effect without
+// waiting for the token to expire.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (*entity.User, error) {
+	return s.userForToken(ctx, token, accessToken)
+}
+
+func (s *AuthService) userForToken(ctx context.Context, token, tokenType string) (*entity.User, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.config.Secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired())
+	if err != nil || claims.TokenType != tokenType {
+		return nil, ErrInvalidToken
+	}
+
+	id, err := primitive.ObjectIDFromHex(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidToken
+	}
+	if user.Disabled {
+		return nil, ErrAccountDisabled
+	}
+	return user, nil
+}
+
+func (s *AuthService) issueTokens(user *entity.User) (*TokenPair, error) {
+	now := time.Now()
+	access, err := s.signToken(user, accessToken, now, s.config.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := s.signToken(user, refreshToken, now, s.config.RefreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresAt:    now.Add(s.config.AccessTokenTTL),
+	}, nil
+}
+
+func (s *AuthService) signToken(user *entity.User, tokenType string, now time.Time, ttl time.Duration) (string, error) {
+	claims := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   user.ID.Hex(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			ID:        primitive.NewObjectID().Hex(),
+		},
+		TokenType: tokenType,
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.config.Secret)
+}
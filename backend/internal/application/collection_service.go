@@ -0,0 +1,343 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrCollectionNotFound  = errors.New("collection not found")
+	ErrTooManyCollections  = errors.New("too many collections; delete one first")
+	ErrAlreadyInCollection = errors.New("recipe is already in the collection")
+	ErrNotInCollection     = errors.New("recipe is not in the collection")
+	ErrInvalidOrder        = errors.New("order must list every recipe in the collection exactly once")
+	ErrCollectionConflict  = errors.New("collection has been modified since it was read; try again")
+)
+
+const (
+	// MaxCollectionsPerUser caps how many collections one user may keep
+	MaxCollectionsPerUser = 100
+
+	// shareTokenBytes is how much randomness a share link carries, enough
+	// that links cannot be guessed
+	shareTokenBytes = 16
+)
+
+// CollectionItem is a collection entry together with its recipe
+type CollectionItem struct {
+	entity.CollectionEntry
+	Recipe *entity.Recipe
+}
+
+// CollectionView is a collection with its recipes loaded, in order. Entries
+// whose recipe has since been deleted, or is hidden from the viewer, are
+// left out.
+type CollectionView struct {
+	Collection *entity.Collection
+	Items      []CollectionItem
+}
+
+// CollectionService handles the business logic for favorites and recipe
+// collections
+type CollectionService struct {
+	collectionRepo repository.CollectionRepository
+	favoriteRepo   repository.FavoriteRepository
+	recipeRepo     repository.RecipeRepository
+}
+
+// NewCollectionService creates a new CollectionService
+func NewCollectionService(collectionRepo repository.CollectionRepository,
+	favoriteRepo repository.FavoriteRepository,
+	recipeRepo repository.RecipeRepository) *CollectionService {
+	return &CollectionService{
+		collectionRepo: collectionRepo,
+		favoriteRepo:   favoriteRepo,
+		recipeRepo:     recipeRepo,
+	}
+}
+
+// CreateCollection creates an empty, private collection owned by user
+func (s *CollectionService) CreateCollection(ctx context.Context, name, description string,
+	user *entity.User) (*entity.Collection, error) {
+	if user == nil {
+		return nil, ErrUnauthorized
+	}
+
+	count, err := s.collectionRepo.CountByOwner(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxCollectionsPerUser {
+		return nil, ErrTooManyCollections
+	}
+
+	collection := entity.NewCollection(user.ID, name, description)
+	if err := collection.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.collectionRepo.Create(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// ListCollections lists user's collections, most recently changed first
+func (s *CollectionService) ListCollections(ctx context.Context, user *entity.User) ([]*entity.Collection, error) {
+	if user == nil {
+		return nil, ErrUnauthorized
+	}
+	return s.collectionRepo.FindByOwner(ctx, user.ID)
+}
+
+// GetCollection retrieves one of user's collections with its recipes
+func (s *CollectionService) GetCollection(ctx context.Context, id primitive.ObjectID,
+	user *entity.User) (*CollectionView, error) {
+	collection, err := s.ownCollection(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, collection, user)
+}
+
+// GetSharedCollection retrieves the collection shared under token on behalf
+// of viewer, who may be nil. Collections that have been made private again
+// are not found.
+func (s *CollectionService) GetSharedCollection(ctx context.Context, token string,
+	viewer *entity.User) (*CollectionView, error) {
+	collection, err := s.collectionRepo.FindByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil || !collection.Public {
+		return nil, ErrCollectionNotFound
+	}
+	return s.view(ctx, collection, viewer)
+}
+
+// UpdateCollection renames one of user's collections and replaces its
+// description
+func (s *CollectionService) UpdateCollection(ctx context.Context, id primitive.ObjectID, name, description string,
+	user *entity.User) (*CollectionView, error) {
+	return s.change(ctx, id, user, func(collection *entity.Collection) error {
+		collection.Update(name, description)
+		return nil
+	})
+}
+
+// DeleteCollection deletes one of user's collections. The recipes in it are
+// not affected.
+func (s *CollectionService) DeleteCollection(ctx context.Context, id primitive.ObjectID, user *entity.User) error {
+	if _, err := s.ownCollection(ctx, id, user); err != nil {
+		return err
+	}
+	return s.collectionRepo.Delete(ctx, id)
+}
+
+// AddToCollection adds a recipe user can see to one of their collections
+// with an optional note. A nil position appends it; otherwise it is
+// inserted at that zero-based position.
+func (s *CollectionService) AddToCollection(ctx context.Context, id, recipeID primitive.ObjectID, note string,
+	position *int, user *entity.User) (*CollectionView, error) {
+	collection, err := s.ownCollection(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.visibleRecipe(ctx, recipeID, user); err != nil {
+		return nil, err
+	}
+
+	readVersion := collection.Version
+	at := -1
+	if position != nil {
+		at = *position
+	}
+	if !collection.AddEntry(recipeID, note, at) {
+		return nil, ErrAlreadyInCollection
+	}
+	return s.save(ctx, collection, readVersion, user)
+}
+
+// UpdateCollectionEntry changes the note on a recipe in one of user's
+// collections and moves it to position. Nil arguments are left as they are.
+func (s *CollectionService) UpdateCollectionEntry(ctx context.Context, id, recipeID primitive.ObjectID,
+	note *string, position *int, user *entity.User) (*CollectionView, error) {
+	return s.change(ctx, id, user, func(collection *entity.Collection) error {
+		if collection.IndexOf(recipeID) < 0 {
+			return ErrNotInCollection
+		}
+		if note != nil {
+			collection.SetNote(recipeID, *note)
+		}
+		if position != nil {
+			collection.MoveEntry(recipeID, *position)
+		}
+		return nil
+	})
+}
+
+// RemoveFromCollection takes a recipe out of one of user's collections
+func (s *CollectionService) RemoveFromCollection(ctx context.Context, id, recipeID primitive.ObjectID,
+	user *entity.User) (*CollectionView, error) {
+	return s.change(ctx, id, user, func(collection *entity.Collection) error {
+		if !collection.RemoveEntry(recipeID) {
+			return ErrNotInCollection
+		}
+		return nil
+	})
+}
+
+// ReorderCollection puts the recipes in one of user's collections in the
+// order given, which must list each of them exactly once
+func (s *CollectionService) ReorderCollection(ctx context.Context, id primitive.ObjectID,
+	recipeIDs []primitive.ObjectID, user *entity.User) (*CollectionView, error) {
+	return s.change(ctx, id, user, func(collection *entity.Collection) error {
+		if !collection.Reorder(recipeIDs) {
+			return ErrInvalidOrder
+		}
+		return nil
+	})
+}
+
+// SetCollectionSharing shares one of user's collections by link or makes it
+// private again. Sharing an already shared collection keeps its link;
+// sharing it again after making it private issues a new one.
+func (s *CollectionService) SetCollectionSharing(ctx context.Context, id primitive.ObjectID, public bool,
+	user *entity.User) (*CollectionView, error) {
+	return s.change(ctx, id, user, func(collection *entity.Collection) error {
+		switch {
+		case public && !collection.Public:
+			token, err := newShareToken()
+			if err != nil {
+				return err
+			}
+			collection.Share(token)
+		case !public && collection.Public:
+			collection.Unshare()
+		}
+		return nil
+	})
+}
+
+// change applies fn to one of user's collections and saves the result
+func (s *CollectionService) change(ctx context.Context, id primitive.ObjectID, user *entity.User,
+	fn func(*entity.Collection) error) (*CollectionView, error) {
+	collection, err := s.ownCollection(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	readVersion := collection.Version
+	if err := fn(collection); err != nil {
+		return nil, err
+	}
+	return s.save(ctx, collection, readVersion, user)
+}
+
+// save writes back a collection that was read at readVersion. It fails with
+// ErrCollectionConflict if someone else saved it in the meantime and with
+// ErrCollectionNotFound if it has since been deleted.
+func (s *CollectionService) save(ctx context.Context, collection *entity.Collection, readVersion int64,
+	user *entity.User) (*CollectionView, error) {
+	if err := collection.Validate(); err != nil {
+		return nil, err
+	}
+	err := s.collectionRepo.Update(ctx, collection, readVersion)
+	if err == repository.ErrCollectionConflict {
+		stored, err := s.collectionRepo.FindByID(ctx, collection.ID)
+		if err != nil {
+			return nil, err
+		}
+		if stored == nil {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, ErrCollectionConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.view(ctx, collection, user)
+}
+
+// ownCollection loads a collection belonging to user. Other users'
+// collections are reported as not found so their existence is not
+// revealed.
+func (s *CollectionService) ownCollection(ctx context.Context, id primitive.ObjectID,
+	user *entity.User) (*entity.Collection, error) {
+	if user == nil {
+		return nil, ErrUnauthorized
+	}
+	collection, err := s.collectionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil || collection.OwnerID != user.ID {
+		return nil, ErrCollectionNotFound
+	}
+	return collection, nil
+}
+
+// view loads the recipes of a collection that viewer may see, in the
+// collection's order
+func (s *CollectionService) view(ctx context.Context, collection *entity.Collection,
+	viewer *entity.User) (*CollectionView, error) {
+	ids := make([]primitive.ObjectID, len(collection.Entries))
+	for i, entry := range collection.Entries {
+		ids[i] = entry.RecipeID
+	}
+	recipes, err := s.visibleRecipes(ctx, ids, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []CollectionItem{}
+	for _, entry := range collection.Entries {
+		if recipe, ok := recipes[entry.RecipeID]; ok {
+			items = append(items, CollectionItem{CollectionEntry: entry, Recipe: recipe})
+		}
+	}
+	return &CollectionView{Collection: collection, Items: items}, nil
+}
+
+// visibleRecipe loads a recipe viewer may see, reporting hidden ones as not
+// found
+func (s *CollectionService) visibleRecipe(ctx context.Context, id primitive.ObjectID,
+	viewer *entity.User) (*entity.Recipe, error) {
+	recipe, err := s.recipeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if recipe == nil || !Can(viewer, ActionViewRecipe, recipe) {
+		return nil, ErrRecipeNotFound
+	}
+	return recipe, nil
+}
+
+// visibleRecipes loads the recipes with the given IDs that viewer may see,
+// keyed by ID
+func (s *CollectionService) visibleRecipes(ctx context.Context, ids []primitive.ObjectID,
+	viewer *entity.User) (map[primitive.ObjectID]*entity.Recipe, error) {
+	recipes, err := s.recipeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*entity.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		if Can(viewer, ActionViewRecipe, recipe) {
+			byID[recipe.ID] = recipe
+		}
+	}
+	return byID, nil
+}
+
+func newShareToken() (string, error) {
+	raw := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
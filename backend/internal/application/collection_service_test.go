@@ -0,0 +1,155 @@
+package application
+
+import (
+	"fmt"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCollections(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	gimlet := f.createRecipe(t, "Gimlet", user)
+	daiquiri := f.createRecipe(t, "Daiquiri", newUser(entity.RoleUser))
+	collection, err := f.collections.CreateCollection(f.ctx, " Friday ", "", user)
+	if err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	if collection.Name != "Friday" {
+		t.Errorf("Name = %q, want it trimmed", collection.Name)
+	}
+
+	if _, err := f.collections.AddToCollection(f.ctx, collection.ID, gimlet.ID, "", nil, user); err != nil {
+		t.Fatalf("AddToCollection: %v", err)
+	}
+	first := 0
+	if _, err := f.collections.AddToCollection(f.ctx, collection.ID, daiquiri.ID, "Double", &first, user); err != nil {
+		t.Fatalf("AddToCollection at the front: %v", err)
+	}
+	assertCollection(t, f, collection.ID, user, "Daiquiri", "Gimlet")
+	if _, err := f.collections.AddToCollection(f.ctx, collection.ID, gimlet.ID, "", nil, user); err != ErrAlreadyInCollection {
+		t.Errorf("second AddToCollection error = %v, want ErrAlreadyInCollection", err)
+	}
+
+	order := []primitive.ObjectID{gimlet.ID, daiquiri.ID}
+	if _, err := f.collections.ReorderCollection(f.ctx, collection.ID, order, user); err != nil {
+		t.Fatalf("ReorderCollection: %v", err)
+	}
+	assertCollection(t, f, collection.ID, user, "Gimlet", "Daiquiri")
+	if _, err := f.collections.ReorderCollection(f.ctx, collection.ID, order[:1], user); err != ErrInvalidOrder {
+		t.Errorf("ReorderCollection leaving a recipe out error = %v, want ErrInvalidOrder", err)
+	}
+
+	// A recipe hidden from the owner drops out of the view but not the
+	// collection
+	if _, err := f.recipes.SetHidden(f.ctx, daiquiri.ID, true, newUser(entity.RoleModerator), nil); err != nil {
+		t.Fatalf("SetHidden: %v", err)
+	}
+	assertCollection(t, f, collection.ID, user, "Gimlet")
+
+	if _, err := f.collections.GetCollection(f.ctx, collection.ID, newUser(entity.RoleUser)); err != ErrCollectionNotFound {
+		t.Errorf("GetCollection by another user error = %v, want ErrCollectionNotFound", err)
+	}
+	if _, err := f.collections.RemoveFromCollection(f.ctx, collection.ID, primitive.NewObjectID(), user); err != ErrNotInCollection {
+		t.Errorf("RemoveFromCollection of a recipe not in it error = %v, want ErrNotInCollection", err)
+	}
+}
+
+func TestSharedCollection(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	collection, err := f.collections.CreateCollection(f.ctx, "Friday", "", user)
+	if err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+
+	view, err := f.collections.SetCollectionSharing(f.ctx, collection.ID, true, user)
+	if err != nil {
+		t.Fatalf("SetCollectionSharing: %v", err)
+	}
+	token := view.Collection.ShareToken
+	if _, err := f.collections.GetSharedCollection(f.ctx, token, nil); err != nil {
+		t.Errorf("GetSharedCollection: %v", err)
+	}
+
+	if _, err := f.collections.SetCollectionSharing(f.ctx, collection.ID, false, user); err != nil {
+		t.Fatalf("SetCollectionSharing: %v", err)
+	}
+	view, err = f.collections.SetCollectionSharing(f.ctx, collection.ID, true, user)
+	if err != nil {
+		t.Fatalf("SetCollectionSharing: %v", err)
+	}
+	if _, err := f.collections.GetSharedCollection(f.ctx, token, nil); err != ErrCollectionNotFound {
+		t.Errorf("GetSharedCollection with the old link error = %v, want ErrCollectionNotFound", err)
+	}
+	if view.Collection.ShareToken == token {
+		t.Error("sharing again reused the old link")
+	}
+}
+
+func TestCollectionStaleSave(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	gimlet := f.createRecipe(t, "Gimlet", user)
+	daiquiri := f.createRecipe(t, "Daiquiri", user)
+	collection, err := f.collections.CreateCollection(f.ctx, "Friday", "", user)
+	if err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	for _, recipe := range []*entity.Recipe{gimlet, daiquiri} {
+		if _, err := f.collections.AddToCollection(f.ctx, collection.ID, recipe.ID, "", nil, user); err != nil {
+			t.Fatalf("AddToCollection: %v", err)
+		}
+	}
+
+	// Deleting a recipe takes it out of the collection; a rename that read
+	// the collection before must not put it back
+	f.interleave.next = func() {
+		if err := f.recipes.DeleteRecipe(f.ctx, daiquiri.ID, user, nil); err != nil {
+			t.Fatalf("DeleteRecipe: %v", err)
+		}
+	}
+	if _, err := f.collections.UpdateCollection(f.ctx, collection.ID, "Saturday", "", user); err != ErrCollectionConflict {
+		t.Errorf("stale UpdateCollection error = %v, want ErrCollectionConflict", err)
+	}
+	stored, err := f.interleave.FindByID(f.ctx, collection.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	if stored.Name != "Friday" || len(stored.Entries) != 1 || stored.Entries[0].RecipeID != gimlet.ID {
+		t.Errorf("stored collection = %q with %d entries, want Friday with only the Gimlet",
+			stored.Name, len(stored.Entries))
+	}
+
+	// Nor may a save that read the collection before it was deleted
+	f.interleave.next = func() {
+		if err := f.collections.DeleteCollection(f.ctx, collection.ID, user); err != nil {
+			t.Fatalf("DeleteCollection: %v", err)
+		}
+	}
+	if _, err := f.collections.RemoveFromCollection(f.ctx, collection.ID, gimlet.ID, user); err != ErrCollectionNotFound {
+		t.Errorf("RemoveFromCollection of a deleted collection error = %v, want ErrCollectionNotFound", err)
+	}
+	if stored, _ := f.interleave.FindByID(f.ctx, collection.ID); stored != nil {
+		t.Error("the deleted collection was saved again")
+	}
+}
+
+// assertCollection checks the names of the recipes in one of user's
+// collections, in order
+func assertCollection(t *testing.T, f *fixture, id primitive.ObjectID, user *entity.User, names ...string) {
+	t.Helper()
+	view, err := f.collections.GetCollection(f.ctx, id, user)
+	if err != nil {
+		t.Fatalf("GetCollection: %v", err)
+	}
+	var got []string
+	for _, item := range view.Items {
+		got = append(got, item.Recipe.Name)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(names) {
+		t.Errorf("collection = %v, want %v", got, names)
+	}
+}
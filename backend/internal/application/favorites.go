@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrTooManyFavorites is returned when a user has bookmarked as many
+// recipes as allowed
+var ErrTooManyFavorites = errors.New("too many favorites; remove one first")
+
+// MaxFavoritesPerUser caps how many recipes one user may bookmark
+const MaxFavoritesPerUser = 1000
+
+// ListFavorites retrieves the recipes user has bookmarked, most recently
+// added first. Recipes that have since been hidden from them are left out.
+func (s *CollectionService) ListFavorites(ctx context.Context, user *entity.User) ([]*entity.Recipe, error) {
+	if user == nil {
+		return nil, ErrUnauthorized
+	}
+	favorites, err := s.favoriteRepo.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(favorites))
+	for i, favorite := range favorites {
+		ids[i] = favorite.RecipeID
+	}
+	byID, err := s.visibleRecipes(ctx, ids, user)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := []*entity.Recipe{}
+	for _, favorite := range favorites {
+		if recipe, ok := byID[favorite.RecipeID]; ok {
+			recipes = append(recipes, recipe)
+		}
+	}
+	return recipes, nil
+}
+
+// AddFavorite bookmarks a recipe for user. Bookmarking a recipe twice is
+// not an error.
+func (s *CollectionService) AddFavorite(ctx context.Context, recipeID primitive.ObjectID, user *entity.User) error {
+	if user == nil {
+		return ErrUnauthorized
+	}
+	if _, err := s.visibleRecipe(ctx, recipeID, user); err != nil {
+		return err
+	}
+
+	// Bookmarking again must succeed even for a user at the cap
+	exists, err := s.favoriteRepo.Exists(ctx, user.ID, recipeID)
+	if err != nil || exists {
+		return err
+	}
+	count, err := s.favoriteRepo.CountByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if count >= MaxFavoritesPerUser {
+		return ErrTooManyFavorites
+	}
+	return s.favoriteRepo.Add(ctx, entity.NewFavorite(user.ID, recipeID))
+}
+
+// RemoveFavorite removes a recipe from user's favorites. Removing a recipe
+// that is not a favorite is not an error.
+func (s *CollectionService) RemoveFavorite(ctx context.Context, recipeID primitive.ObjectID, user *entity.User) error {
+	if user == nil {
+		return ErrUnauthorized
+	}
+	return s.favoriteRepo.Remove(ctx, user.ID, recipeID)
+}
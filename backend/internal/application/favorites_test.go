@@ -0,0 +1,83 @@
+package application
+
+import (
+	"fmt"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFavorites(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	gimlet := f.createRecipe(t, "Gimlet", user)
+	daiquiri := f.createRecipe(t, "Daiquiri", newUser(entity.RoleUser))
+
+	for _, recipe := range []*entity.Recipe{gimlet, daiquiri, gimlet} {
+		if err := f.collections.AddFavorite(f.ctx, recipe.ID, user); err != nil {
+			t.Fatalf("AddFavorite(%q): %v", recipe.Name, err)
+		}
+	}
+	assertFavorites(t, f, user, "Daiquiri", "Gimlet")
+
+	// A recipe hidden from the user drops out of their favorites
+	if _, err := f.recipes.SetHidden(f.ctx, daiquiri.ID, true, newUser(entity.RoleModerator), nil); err != nil {
+		t.Fatalf("SetHidden: %v", err)
+	}
+	assertFavorites(t, f, user, "Gimlet")
+	if err := f.collections.AddFavorite(f.ctx, daiquiri.ID, user); err != ErrRecipeNotFound {
+		t.Errorf("AddFavorite of a hidden recipe error = %v, want ErrRecipeNotFound", err)
+	}
+
+	if err := f.collections.RemoveFavorite(f.ctx, gimlet.ID, user); err != nil {
+		t.Fatalf("RemoveFavorite: %v", err)
+	}
+	if err := f.collections.RemoveFavorite(f.ctx, gimlet.ID, user); err != nil {
+		t.Errorf("RemoveFavorite of a recipe that is not a favorite: %v", err)
+	}
+	assertFavorites(t, f, user)
+
+	if err := f.collections.AddFavorite(f.ctx, gimlet.ID, nil); err != ErrUnauthorized {
+		t.Errorf("anonymous AddFavorite error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestFavoritesCap(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	gimlet := f.createRecipe(t, "Gimlet", user)
+	if err := f.collections.AddFavorite(f.ctx, gimlet.ID, user); err != nil {
+		t.Fatalf("AddFavorite: %v", err)
+	}
+	// Fill the rest of the cap without creating a recipe for each
+	for i := 1; i < MaxFavoritesPerUser; i++ {
+		if err := f.favorites.Add(f.ctx, entity.NewFavorite(user.ID, primitive.NewObjectID())); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	daiquiri := f.createRecipe(t, "Daiquiri", user)
+	if err := f.collections.AddFavorite(f.ctx, daiquiri.ID, user); err != ErrTooManyFavorites {
+		t.Errorf("AddFavorite past the cap error = %v, want ErrTooManyFavorites", err)
+	}
+	if err := f.collections.AddFavorite(f.ctx, gimlet.ID, user); err != nil {
+		t.Errorf("AddFavorite of an existing favorite at the cap: %v", err)
+	}
+}
+
+// assertFavorites checks the names of user's favorites, newest first
+func assertFavorites(t *testing.T, f *fixture, user *entity.User, names ...string) {
+	t.Helper()
+	favorites, err := f.collections.ListFavorites(f.ctx, user)
+	if err != nil {
+		t.Fatalf("ListFavorites: %v", err)
+	}
+	var got []string
+	for _, recipe := range favorites {
+		got = append(got, recipe.Name)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(names) {
+		t.Errorf("favorites = %v, want %v", got, names)
+	}
+}
@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/infrastructure/memory"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fixture wires the services to in-memory repositories, the way main does
+// for STORAGE_DRIVER=memory
+type fixture struct {
+	ctx         context.Context
+	recipes     *RecipeService
+	ingredients *IngredientService
+	collections *CollectionService
+	favorites   *memory.FavoriteRepository
+	interleave  *interleavedCollections
+}
+
+func newFixture() *fixture {
+	recipeRepo := memory.NewRecipeRepository()
+	ingredientRepo := memory.NewIngredientRepository()
+	favoriteRepo := memory.NewFavoriteRepository()
+	collectionRepo := &interleavedCollections{CollectionRepository: memory.NewCollectionRepository()}
+
+	recipes := NewRecipeService(recipeRepo, memory.NewRevisionRepository(), ingredientRepo,
+		memory.NewReviewRepository(), favoriteRepo, collectionRepo, NewRecommender())
+	return &fixture{
+		ctx:         context.Background(),
+		recipes:     recipes,
+		ingredients: NewIngredientService(ingredientRepo, recipes),
+		collections: NewCollectionService(collectionRepo, favoriteRepo, recipeRepo),
+		favorites:   favoriteRepo,
+		interleave:  collectionRepo,
+	}
+}
+
+// interleavedCollections runs next, once, right after the next collection
+// is read, as if another request changed things before the reader saved
+type interleavedCollections struct {
+	*memory.CollectionRepository
+	next func()
+}
+
+func (r *interleavedCollections) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Collection, error) {
+	collection, err := r.CollectionRepository.FindByID(ctx, id)
+	if next := r.next; next != nil {
+		r.next = nil
+		next()
+	}
+	return collection, err
+}
+
+// newUser returns a signed-in account with the given role
+func newUser(role entity.Role) *entity.User {
+	user := entity.NewUser(primitive.NewObjectID().Hex()+"@example.com", "", "", role)
+	user.ID = primitive.NewObjectID()
+	return user
+}
+
+// cocktail returns the details of a valid cocktail
+func cocktail(name string) entity.RecipeDetails {
+	return entity.RecipeDetails{
+		Name: name,
+		Ingredients: []entity.Ingredient{
+			{Name: "Gin", Amount: 2, Unit: "oz"},
+			{Name: "Lime juice", Amount: 0.75, Unit: "oz"},
+		},
+		Instructions: []string{"Shake with ice", "Strain"},
+	}
+}
+
+// createRecipe saves a cocktail owned by creator, failing the test if it
+// cannot
+func (f *fixture) createRecipe(t *testing.T, name string, creator *entity.User) *entity.Recipe {
+	t.Helper()
+	recipe, err := f.recipes.CreateRecipe(f.ctx, cocktail(name), creator)
+	if err != nil {
+		t.Fatalf("CreateRecipe(%q): %v", name, err)
+	}
+	return recipe
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrIngredientNotFound  = errors.New("ingredient not found")
+	ErrInvalidIngredient   = errors.New("invalid ingredient data")
+	ErrDuplicateIngredient = errors.New("ingredient name or alias already in catalog")
+)
+
+// IngredientService handles the business logic for the ingredient catalog
+type IngredientService struct {
+	ingredientRepo repository.IngredientRepository
+	recipeService  *RecipeService
+}
+
+// NewIngredientService creates a new IngredientService. Recipes linking to
+// an entry are refreshed through recipeService when the entry changes.
+func NewIngredientService(ingredientRepo repository.IngredientRepository,
+	recipeService *RecipeService) *IngredientService {
+	return &IngredientService{
+		ingredientRepo: ingredientRepo,
+		recipeService:  recipeService,
+	}
+}
+
+// CreateIngredient adds an ingredient to the catalog on behalf of actor
+func (s *IngredientService) CreateIngredient(ctx context.Context, name string, aliases []string,
+	category entity.IngredientCategory, abv *float64, baseSpirit string,
+	actor *entity.User) (*entity.CatalogIngredient, error) {
+	if err := authorizeCatalogManagement(actor); err != nil {
+		return nil, err
+	}
+	ingredient := entity.NewCatalogIngredient(name, aliases, category, abv, baseSpirit)
+	if err := ingredient.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.ingredientRepo.Create(ctx, ingredient); err != nil {
+		return nil, mapIngredientError(err)
+	}
+	return ingredient, nil
+}
+
+// GetIngredientByID retrieves a catalog ingredient by ID
+func (s *IngredientService) GetIngredientByID(ctx context.Context, id primitive.ObjectID) (*entity.CatalogIngredient, error) {
+	ingredient, err := s.ingredientRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ingredient == nil {
+		return nil, ErrIngredientNotFound
+	}
+	return ingredient, nil
+}
+
+// ListIngredients lists the catalog by name, optionally restricted to one
+// category
+func (s *IngredientService) ListIngredients(ctx context.Context, category entity.IngredientCategory) ([]*entity.CatalogIngredient, error) {
+	if category != "" && !category.Valid() {
+		return nil, ErrInvalidIngredient
+	}
+	return s.ingredientRepo.List(ctx, category)
+}
+
+// UpdateIngredient updates a catalog ingredient on behalf of actor. When its
+// ABV changes the estimated ABV of every recipe linking to it is updated.
+func (s *IngredientService) UpdateIngredient(ctx context.Context, id primitive.ObjectID, name string,
+	aliases []string, category entity.IngredientCategory, abv *float64,
+	baseSpirit string, actor *entity.User) (*entity.CatalogIngredient, error) {
+	if err := authorizeCatalogManagement(actor); err != nil {
+		return nil, err
+	}
+	ingredient, err := s.GetIngredientByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	oldABV := ingredient.ABV
+	ingredient.Update(name, aliases, category, abv, baseSpirit)
+	if err := ingredient.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.ingredientRepo.Update(ctx, ingredient); err != nil {
+		return nil, mapIngredientError(err)
+	}
+	if !sameABV(oldABV, ingredient.ABV) {
+		if err := s.recipeService.RefreshCatalogLinks(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return ingredient, nil
+}
+
+// DeleteIngredient removes an ingredient from the catalog on behalf of
+// actor. Recipes keep their free-text ingredient names; their links to the
+// entry are removed and their ABV estimated again without it.
+func (s *IngredientService) DeleteIngredient(ctx context.Context, id primitive.ObjectID, actor *entity.User) error {
+	if err := authorizeCatalogManagement(actor); err != nil {
+		return err
+	}
+	if _, err := s.GetIngredientByID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.ingredientRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.recipeService.RefreshCatalogLinks(ctx, id)
+}
+
+func sameABV(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// authorizeCatalogManagement returns ErrUnauthorized for anonymous callers
+// and ErrForbidden for anyone who is not an admin
+func authorizeCatalogManagement(actor *entity.User) error {
+	if actor == nil {
+		return ErrUnauthorized
+	}
+	if !CanManageCatalog(actor) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func mapIngredientError(err error) error {
+	if err == repository.ErrDuplicateIngredient {
+		return ErrDuplicateIngredient
+	}
+	return err
+}
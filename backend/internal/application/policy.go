@@ -0,0 +1,91 @@
+package application
+
+import (
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Action is something a user may or may not be allowed to do to a recipe
+type Action string
+
+const (
+	ActionViewRecipe    Action = "view"
+	ActionEditRecipe    Action = "edit"
+	ActionDeleteRecipe  Action = "delete"
+	ActionFeatureRecipe Action = "feature"
+	ActionHideRecipe    Action = "hide"
+)
+
+// Can reports whether user may perform action on recipe. A nil user is an
+// anonymous caller. The rules are:
+//
+//   - anyone may view a recipe that is not hidden; its creator and
+//     moderators may also view it once hidden
+//   - a recipe's creator and admins may edit it
+//   - its creator and moderators may delete it
+//   - moderators may feature and hide recipes
+//
+// Admins count as moderators. Disabled accounts are treated as anonymous.
+// Recipes created before accounts existed have no creator, so only staff
+// may change them.
+func Can(user *entity.User, action Action, recipe *entity.Recipe) bool {
+	if user != nil && user.Disabled {
+		user = nil
+	}
+	owner := user != nil && user.Owns(recipe)
+	moderator := user != nil && user.IsModerator()
+
+	switch action {
+	case ActionViewRecipe:
+		return !recipe.Hidden || owner || moderator
+	case ActionEditRecipe:
+		return owner || (user != nil && user.IsAdmin())
+	case ActionDeleteRecipe:
+		return owner || moderator
+	case ActionFeatureRecipe, ActionHideRecipe:
+		return moderator
+	}
+	return false
+}
+
+// canListHidden reports whether viewer may see the hidden recipes among
+// those created by creatorID
+func canListHidden(viewer *entity.User, creatorID primitive.ObjectID) bool {
+	if viewer == nil || viewer.Disabled {
+		return false
+	}
+	return viewer.ID == creatorID || viewer.IsModerator()
+}
+
+// CanDeleteReview reports whether user may delete a review: its author
+// and moderators may
+func CanDeleteReview(user *entity.User, review *entity.Review) bool {
+	if user == nil || user.Disabled {
+		return false
+	}
+	return user.ID == review.UserID || user.IsModerator()
+}
+
+// CanManageUsers reports whether user may change other users' roles and
+// disable their accounts
+func CanManageUsers(user *entity.User) bool {
+	return user != nil && !user.Disabled && user.IsAdmin()
+}
+
+// CanManageCatalog reports whether user may add, change and delete catalog
+// ingredients. Every recipe may link to the catalog, so only admins may.
+func CanManageCatalog(user *entity.User) bool {
+	return user != nil && !user.Disabled && user.IsAdmin()
+}
+
+// authorize returns ErrUnauthorized when an anonymous caller is refused and
+// ErrForbidden when a signed-in user is
+func authorize(user *entity.User, action Action, recipe *entity.Recipe) error {
+	if Can(user, action, recipe) {
+		return nil
+	}
+	if user == nil {
+		return ErrUnauthorized
+	}
+	return ErrForbidden
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"fork-and-shaker/internal/domain/repository"
+)
+
+var (
+	ErrInvalidAutocompleteField = errors.New("field must be ingredient, glass, garnish, unit or name")
+	ErrInvalidSuggestionLimit   = errors.New("limit must be a positive integer")
+)
+
+const (
+	// DefaultSuggestions is how many suggestions autocomplete returns when
+	// the caller does not say
+	DefaultSuggestions = 10
+	// MaxSuggestions caps how many suggestions one request may return
+	MaxSuggestions = 50
+	// MaxPrefixLength caps the text autocomplete looks up, which is typed
+	// into a single form input
+	MaxPrefixLength = 100
+)
+
+// Autocomplete suggests values already used for a free-text recipe field
+// that begin with prefix, ignoring case, most used first. Spellings that
+// differ only in case count as one value. An empty prefix suggests the most
+// used values overall.
+func (s *RecipeService) Autocomplete(ctx context.Context, field repository.AutocompleteField,
+	prefix string, limit int) ([]repository.Suggestion, error) {
+	if !field.Valid() {
+		return nil, ErrInvalidAutocompleteField
+	}
+	if limit < 0 {
+		return nil, ErrInvalidSuggestionLimit
+	}
+	if limit == 0 {
+		limit = DefaultSuggestions
+	}
+	limit = min(limit, MaxSuggestions)
+
+	prefix = strings.TrimLeft(prefix, " \t")
+	if len(prefix) > MaxPrefixLength {
+		return []repository.Suggestion{}, nil
+	}
+
+	counts, err := s.recipeRepo.CountValues(ctx, field, prefix)
+	if err != nil {
+		return nil, err
+	}
+	return repository.NewSuggestions(counts, limit), nil
+}
@@ -0,0 +1,182 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// linkIngredients resolves a recipe's ingredients against the catalog before
+// it is saved. Ingredients without a link are linked to the catalog entry
+// whose name or alias they use. When strict is set links to unknown entries
+// fail with a validation error; otherwise, as when forking or restoring
+// a recipe whose entry has since been deleted, the link is dropped. The
+// recipe's stored ABV is then recomputed with catalog strengths.
+func (s *RecipeService) linkIngredients(ctx context.Context, recipe *entity.Recipe, strict bool) error {
+	entries, err := s.catalogEntries(ctx, recipe)
+	if err != nil {
+		return err
+	}
+
+	var unlinked []string
+	verr := &entity.ValidationError{}
+	for i := range recipe.Ingredients {
+		ing := &recipe.Ingredients[i]
+		if ing.CatalogID != nil && entries[*ing.CatalogID] == nil {
+			if strict {
+				verr.Add(fmt.Sprintf("ingredients[%d].catalog_id", i), "unknown catalog ingredient")
+				continue
+			}
+			ing.CatalogID = nil
+		}
+		if ing.CatalogID == nil {
+			unlinked = append(unlinked, ing.Name)
+		}
+	}
+
+	if err := verr.Err(); err != nil {
+		return err
+	}
+
+	if len(unlinked) > 0 {
+		found, err := s.ingredientRepo.FindByNames(ctx, unlinked)
+		if err != nil {
+			return err
+		}
+		byKey := make(map[string]*entity.CatalogIngredient)
+		for _, entry := range found {
+			entries[entry.ID] = entry
+			for _, key := range entry.Keys() {
+				byKey[key] = entry
+			}
+		}
+		for i := range recipe.Ingredients {
+			ing := &recipe.Ingredients[i]
+			if entry := byKey[entity.NormalizeIngredientName(ing.Name)]; ing.CatalogID == nil && entry != nil {
+				id := entry.ID
+				ing.CatalogID = &id
+			}
+		}
+	}
+
+	recipe.RefreshABV(entity.CatalogABV(entries))
+	return nil
+}
+
+// catalogRefreshAttempts is how many times RefreshCatalogLinks rereads a
+// recipe someone else saved while it was being refreshed
+const catalogRefreshAttempts = 3
+
+// RefreshCatalogLinks brings the recipes linking to a catalog ingredient up
+// to date after the entry was changed or deleted: links to a deleted entry
+// are dropped and every ABV is estimated again with current catalog
+// strengths. As with moderation, no revision is recorded.
+func (s *RecipeService) RefreshCatalogLinks(ctx context.Context, catalogID primitive.ObjectID) error {
+	match := repository.IngredientMatch{CatalogIDs: []primitive.ObjectID{catalogID}}
+	opts := repository.ListOptions{
+		Limit:         MaxPageSize,
+		Sort:          repository.SortByCreatedAt,
+		IncludeHidden: true,
+	}
+	var linked []*entity.Recipe
+	for {
+		page, err := s.recipeRepo.FindByIngredient(ctx, match, opts)
+		if err != nil {
+			return err
+		}
+		linked = append(linked, page.Recipes...)
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	for _, recipe := range linked {
+		if err := s.refreshCatalogLinks(ctx, recipe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshCatalogLinks relinks and saves one recipe, rereading it when a
+// concurrent save wins
+func (s *RecipeService) refreshCatalogLinks(ctx context.Context, recipe *entity.Recipe) error {
+	for attempt := 1; ; attempt++ {
+		readVersion := recipe.Version
+		if err := s.linkIngredients(ctx, recipe, false); err != nil {
+			return err
+		}
+		recipe.CatalogChanged()
+		err := s.saveRecipe(ctx, recipe, readVersion)
+		if err != ErrVersionConflict || attempt == catalogRefreshAttempts {
+			return err
+		}
+		if recipe, err = s.recipeRepo.FindByID(ctx, recipe.ID); err != nil || recipe == nil {
+			return err
+		}
+	}
+}
+
+// catalogEntries loads the catalog entries the recipe's ingredients link to
+func (s *RecipeService) catalogEntries(ctx context.Context, recipe *entity.Recipe) (map[primitive.ObjectID]*entity.CatalogIngredient, error) {
+	entries := make(map[primitive.ObjectID]*entity.CatalogIngredient)
+	for _, ing := range recipe.Ingredients {
+		if ing.CatalogID == nil {
+			continue
+		}
+		if _, seen := entries[*ing.CatalogID]; seen {
+			continue
+		}
+		entry, err := s.ingredientRepo.FindByID(ctx, *ing.CatalogID)
+		if err != nil {
+			return nil, err
+		}
+		entries[*ing.CatalogID] = entry
+	}
+	return entries, nil
+}
+
+// expandIngredientNames resolves names through the catalog, returning every
+// normalized name and alias of the entries they match along with the names
+// themselves, and the IDs of the matched entries
+func (s *RecipeService) expandIngredientNames(ctx context.Context, names []string) ([]string, []primitive.ObjectID, error) {
+	entries, err := s.ingredientRepo.FindByNames(ctx, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seen := make(map[string]bool, len(names))
+	expanded := make([]string, 0, len(names))
+	add := func(name string) {
+		if key := entity.NormalizeIngredientName(name); key != "" && !seen[key] {
+			seen[key] = true
+			expanded = append(expanded, key)
+		}
+	}
+	for _, name := range names {
+		add(name)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+		for _, key := range entry.Keys() {
+			add(key)
+		}
+	}
+	return expanded, ids, nil
+}
+
+// ingredientMatch builds the repository query for recipes using an
+// ingredient under any of its catalog names
+func (s *RecipeService) ingredientMatch(ctx context.Context, ingredient string) (repository.IngredientMatch, error) {
+	names, ids, err := s.expandIngredientNames(ctx, []string{ingredient})
+	if err != nil {
+		return repository.IngredientMatch{}, err
+	}
+	return repository.IngredientMatch{Names: names, CatalogIDs: ids}, nil
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/recipeimport"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxImportRecipes caps how many recipes one import may hold
+const MaxImportRecipes = 500
+
+var ErrTooManyImports = errors.New("an import may hold at most 500 recipes")
+
+// ImportStatus is what became of one recipe of an import
+type ImportStatus string
+
+const (
+	// ImportCreated recipes were saved
+	ImportCreated ImportStatus = "created"
+	// ImportValid recipes passed validation in a dry run and would be saved
+	ImportValid ImportStatus = "valid"
+	// ImportInvalid recipes could not be read or failed validation and were
+	// skipped
+	ImportInvalid ImportStatus = "invalid"
+	// ImportFailed is the recipe a storage error stopped the import at. It
+	// has a RecipeID if it was saved before the error.
+	ImportFailed ImportStatus = "failed"
+	// ImportNotAttempted recipes come after the one the import stopped at
+	ImportNotAttempted ImportStatus = "not_attempted"
+)
+
+// ImportResult reports on one recipe of an import
+type ImportResult struct {
+	// Row is the recipe's position in the file, counting from 1
+	Row int
+	// Line is the line a CSV recipe starts on, and 0 for other formats
+	Line     int
+	Name     string
+	Status   ImportStatus
+	RecipeID *primitive.ObjectID
+	Errors   []entity.FieldError
+}
+
+// ImportReport is the outcome of importing a file, recipe by recipe
+type ImportReport struct {
+	DryRun  bool
+	Results []ImportResult
+	Created int
+	Valid   int
+	Invalid int
+	// Failed counts the recipes an import stopped at or never reached
+	Failed int
+}
+
+// ImportRecipes reads the recipes in a file and creates those that are
+// valid on behalf of creator, as CreateRecipe would, skipping the rest. A
+// dry run validates every recipe without saving any. The report lists
+// every recipe in file order with the problems found in it. A file that
+// cannot be read at all fails with an error wrapping
+// recipeimport.ErrMalformedFile, and nothing is imported. A storage error
+// stops the import, keeping the recipes created before it: the report is
+// returned with the error, the recipe being saved marked failed and those
+// after it not attempted, so the client knows which ones to send again.
+func (s *RecipeService) ImportRecipes(ctx context.Context, format recipeimport.Format, data []byte,
+	creator *entity.User, dryRun bool) (*ImportReport, error) {
+	if creator == nil {
+		return nil, ErrUnauthorized
+	}
+	rows, err := recipeimport.Decode(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > MaxImportRecipes {
+		return nil, ErrTooManyImports
+	}
+
+	report := &ImportReport{DryRun: dryRun, Results: make([]ImportResult, 0, len(rows))}
+	for i, row := range rows {
+		result := ImportResult{
+			Row:    i + 1,
+			Line:   row.Line,
+			Name:   row.Details.Name,
+			Errors: row.Problems,
+		}
+
+		recipe, err := s.newRecipe(ctx, row.Details, creator)
+		var verr *entity.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			result.Errors = append(result.Errors, verr.Errors...)
+		case err != nil:
+			report.stop(rows[i:], i, nil)
+			return report, err
+		}
+
+		switch {
+		case len(result.Errors) > 0:
+			result.Status = ImportInvalid
+			report.Invalid++
+		case dryRun:
+			result.Status = ImportValid
+			report.Valid++
+		default:
+			if err := s.insertRecipe(ctx, recipe, creator); err != nil {
+				var saved *primitive.ObjectID
+				if stored, findErr := s.recipeRepo.FindByID(ctx, recipe.ID); findErr == nil && stored != nil {
+					saved = &recipe.ID
+				}
+				report.stop(rows[i:], i, saved)
+				return report, err
+			}
+			result.Status = ImportCreated
+			result.RecipeID = &recipe.ID
+			report.Created++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// stop records the rows an import did not finish: the first, the one being
+// imported when it stopped, as failed, and the rest as not attempted. start
+// is the index of the first of rows in the file.
+func (r *ImportReport) stop(rows []recipeimport.Row, start int, saved *primitive.ObjectID) {
+	for i, row := range rows {
+		result := ImportResult{
+			Row:    start + i + 1,
+			Line:   row.Line,
+			Name:   row.Details.Name,
+			Status: ImportNotAttempted,
+		}
+		if i == 0 {
+			result.Status = ImportFailed
+			result.RecipeID = saved
+		}
+		r.Results = append(r.Results, result)
+		r.Failed++
+	}
+}
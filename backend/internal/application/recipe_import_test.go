@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/recipeimport"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/infrastructure/memory"
+)
+
+const importFile = `[
+	{"name": "Gimlet", "ingredients": [{"name": "Gin", "amount": 2, "unit": "oz"}], "instructions": ["Shake"]},
+	{"name": "", "ingredients": [{"name": "Rum", "amount": 2, "unit": "oz"}], "instructions": ["Shake"]},
+	{"name": "Daiquiri", "ingredients": [{"name": "Rum", "amount": 2, "unit": "oz"}], "instructions": ["Shake"]},
+	{"name": "Negroni", "ingredients": [{"name": "Gin", "amount": 1, "unit": "oz"}], "instructions": ["Stir"]}
+]`
+
+func TestImportRecipes(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+
+	report, err := f.recipes.ImportRecipes(f.ctx, recipeimport.FormatJSON, []byte(importFile), user, true)
+	if err != nil {
+		t.Fatalf("dry run ImportRecipes: %v", err)
+	}
+	assertImport(t, report, ImportValid, ImportInvalid, ImportValid, ImportValid)
+	if report.Valid != 3 || report.Invalid != 1 || report.Created != 0 {
+		t.Errorf("dry run counts = %d valid, %d invalid, %d created, want 3, 1, 0",
+			report.Valid, report.Invalid, report.Created)
+	}
+	if len(report.Results[1].Errors) == 0 || report.Results[1].Errors[0].Field != "name" {
+		t.Errorf("invalid row errors = %+v, want the missing name", report.Results[1].Errors)
+	}
+	page, _, err := f.recipes.ListRecipes(f.ctx, repository.ClassificationFilter{}, repository.ListOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListRecipes: %v", err)
+	}
+	if page.Total != 0 {
+		t.Errorf("dry run saved %d recipes, want none", page.Total)
+	}
+
+	report, err = f.recipes.ImportRecipes(f.ctx, recipeimport.FormatJSON, []byte(importFile), user, false)
+	if err != nil {
+		t.Fatalf("ImportRecipes: %v", err)
+	}
+	assertImport(t, report, ImportCreated, ImportInvalid, ImportCreated, ImportCreated)
+	created, err := f.recipes.GetRecipe(f.ctx, *report.Results[0].RecipeID, user)
+	if err != nil {
+		t.Fatalf("GetRecipe: %v", err)
+	}
+	if created.Name != "Gimlet" || created.CreatorID == nil || *created.CreatorID != user.ID {
+		t.Errorf("imported recipe = %q by %v, want Gimlet by the importer", created.Name, created.CreatorID)
+	}
+
+	if _, err := f.recipes.ImportRecipes(f.ctx, recipeimport.FormatJSON, []byte("[{"), user, false); !errors.Is(err, recipeimport.ErrMalformedFile) {
+		t.Errorf("ImportRecipes of a malformed file error = %v, want ErrMalformedFile", err)
+	}
+	if _, err := f.recipes.ImportRecipes(f.ctx, recipeimport.FormatJSON, []byte(importFile), nil, false); err != ErrUnauthorized {
+		t.Errorf("anonymous ImportRecipes error = %v, want ErrUnauthorized", err)
+	}
+}
+
+// errStorage stands in for a database failure
+var errStorage = errors.New("storage unavailable")
+
+// failingRecipes fails to create the recipe named failOn
+type failingRecipes struct {
+	*memory.RecipeRepository
+	failOn string
+}
+
+func (r *failingRecipes) Create(ctx context.Context, recipe *entity.Recipe) error {
+	if recipe.Name == r.failOn {
+		return errStorage
+	}
+	return r.RecipeRepository.Create(ctx, recipe)
+}
+
+// failingRevisions fails to record revisions of the recipe named failOn
+type failingRevisions struct {
+	*memory.RevisionRepository
+	failOn string
+}
+
+func (r *failingRevisions) Create(ctx context.Context, revision *entity.Revision) error {
+	if revision.Recipe.Name == r.failOn {
+		return errStorage
+	}
+	return r.RevisionRepository.Create(ctx, revision)
+}
+
+func TestImportRecipesStorageError(t *testing.T) {
+	user := newUser(entity.RoleUser)
+	tests := []struct {
+		name      string
+		recipes   *failingRecipes
+		revisions *failingRevisions
+		saved     bool
+	}{
+		{
+			name:      "recipe not saved",
+			recipes:   &failingRecipes{RecipeRepository: memory.NewRecipeRepository(), failOn: "Daiquiri"},
+			revisions: &failingRevisions{RevisionRepository: memory.NewRevisionRepository()},
+		},
+		{
+			name:      "recipe saved without its revision",
+			recipes:   &failingRecipes{RecipeRepository: memory.NewRecipeRepository()},
+			revisions: &failingRevisions{RevisionRepository: memory.NewRevisionRepository(), failOn: "Daiquiri"},
+			saved:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRecipeService(tt.recipes, tt.revisions, memory.NewIngredientRepository(),
+				memory.NewReviewRepository(), memory.NewFavoriteRepository(), memory.NewCollectionRepository(),
+				NewRecommender())
+
+			report, err := service.ImportRecipes(context.Background(), recipeimport.FormatJSON, []byte(importFile), user, false)
+			if err != errStorage {
+				t.Fatalf("ImportRecipes error = %v, want the storage error", err)
+			}
+			// The report still tells the client which recipes to send again
+			assertImport(t, report, ImportCreated, ImportInvalid, ImportFailed, ImportNotAttempted)
+			if report.Created != 1 || report.Invalid != 1 || report.Failed != 2 {
+				t.Errorf("counts = %d created, %d invalid, %d failed, want 1, 1, 2",
+					report.Created, report.Invalid, report.Failed)
+			}
+			if saved := report.Results[2].RecipeID != nil; saved != tt.saved {
+				t.Errorf("failed row has a recipe ID: %v, want %v", saved, tt.saved)
+			}
+		})
+	}
+}
+
+// assertImport checks the status of each recipe in an import report
+func assertImport(t *testing.T, report *ImportReport, statuses ...ImportStatus) {
+	t.Helper()
+	if len(report.Results) != len(statuses) {
+		t.Fatalf("report has %d results, want %d", len(report.Results), len(statuses))
+	}
+	for i, result := range report.Results {
+		if result.Row != i+1 || result.Status != statuses[i] {
+			t.Errorf("result %d = row %d %s, want row %d %s", i, result.Row, result.Status, i+1, statuses[i])
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/domain/units"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrNotAFork       = errors.New("recipe is not a fork")
+	ErrParentNotFound = errors.New("parent recipe not found")
+)
+
+// maxAncestryDepth bounds how far GetAncestry walks, guarding against
+// corrupted data forming a cycle
+const maxAncestryDepth = 100
+
+// ForkDiff is the set of changes a fork made to the recipe it was forked from
+type ForkDiff struct {
+	Parent  *entity.Recipe
+	Changes []entity.FieldChange
+}
+
+// ForkRecipe creates a copy of a recipe, owned by creator, that remembers
+// where it came from. An empty name keeps the original name. Only recipes
+// creator may view can be forked.
+func (s *RecipeService) ForkRecipe(ctx context.Context, id primitive.ObjectID, name string,
+	creator *entity.User) (*entity.Recipe, error) {
+	if creator == nil {
+		return nil, ErrUnauthorized
+	}
+	parent, err := s.GetRecipe(ctx, id, creator)
+	if err != nil {
+		return nil, err
+	}
+
+	fork := parent.Fork(name)
+	creatorID := creator.ID
+	fork.CreatorID = &creatorID
+	if err := s.linkIngredients(ctx, fork, false); err != nil {
+		return nil, err
+	}
+	if err := fork.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.recipeRepo.Create(ctx, fork); err != nil {
+		return nil, err
+	}
+	s.recommender.Refresh(fork)
+	if err := s.recordRevision(ctx, fork, creator.ID.Hex(), 0); err != nil {
+		return nil, err
+	}
+	return fork, nil
+}
+
+// GetForks retrieves a page of the direct forks of a recipe viewer may see
+func (s *RecipeService) GetForks(ctx context.Context, id primitive.ObjectID, viewer *entity.User,
+	opts repository.ListOptions) (*repository.RecipePage, error) {
+	if _, err := s.GetRecipe(ctx, id, viewer); err != nil {
+		return nil, err
+	}
+	opts, err := normalizeListOptions(opts, repository.SortByCreatedAt, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.recipeRepo.FindForks(ctx, id, opts)
+}
+
+// GetAncestry walks a recipe's lineage from its parent up to the original
+// recipe on behalf of viewer. The walk stops early if an ancestor has since
+// been deleted; ancestors viewer may not see are left out.
+func (s *RecipeService) GetAncestry(ctx context.Context, id primitive.ObjectID, viewer *entity.User) ([]*entity.Recipe, error) {
+	recipe, err := s.GetRecipe(ctx, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors := []*entity.Recipe{}
+	seen := map[primitive.ObjectID]bool{recipe.ID: true}
+	for recipe.ForkedFrom != nil && len(ancestors) < maxAncestryDepth {
+		if seen[*recipe.ForkedFrom] {
+			break
+		}
+		parent, err := s.recipeRepo.FindByID(ctx, *recipe.ForkedFrom)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			break
+		}
+		seen[parent.ID] = true
+		if Can(viewer, ActionViewRecipe, parent) {
+			ancestors = append(ancestors, parent)
+		}
+		recipe = parent
+	}
+	return ancestors, nil
+}
+
+// DiffWithParent compares a fork against the recipe it was forked from on
+// behalf of viewer. A parent viewer may not see is reported as not found.
+// When system is set both recipes are presented in it before they are
+// compared, so the parent and the changes use the same units.
+func (s *RecipeService) DiffWithParent(ctx context.Context, id primitive.ObjectID, viewer *entity.User,
+	system units.System) (*ForkDiff, error) {
+	recipe, err := s.GetRecipe(ctx, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.ForkedFrom == nil {
+		return nil, ErrNotAFork
+	}
+
+	parent, err := s.recipeRepo.FindByID(ctx, *recipe.ForkedFrom)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil || !Can(viewer, ActionViewRecipe, parent) {
+		return nil, ErrParentNotFound
+	}
+
+	if system != "" {
+		parent.ConvertUnits(system)
+		recipe.ConvertUnits(system)
+	}
+	return &ForkDiff{
+		Parent:  parent,
+		Changes: entity.DiffRecipes(parent, recipe),
+	}, nil
+}
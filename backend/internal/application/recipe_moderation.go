@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetRecipe retrieves a recipe on behalf of viewer, who may be nil. A hidden
+// recipe is reported as not found to anyone the policy does not let see it.
+func (s *RecipeService) GetRecipe(ctx context.Context, id primitive.ObjectID, viewer *entity.User) (*entity.Recipe, error) {
+	recipe, err := s.GetRecipeByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !Can(viewer, ActionViewRecipe, recipe) {
+		return nil, ErrRecipeNotFound
+	}
+	return recipe, nil
+}
+
+// SetFeatured features a recipe or stops featuring it on behalf of actor.
+// If expectedVersions is set the change only succeeds while the recipe is
+// still at one of them.
+func (s *RecipeService) SetFeatured(ctx context.Context, id primitive.ObjectID, featured bool,
+	actor *entity.User, expectedVersions []int64) (*entity.Recipe, error) {
+	return s.moderate(ctx, id, ActionFeatureRecipe, actor, expectedVersions, func(recipe *entity.Recipe) {
+		recipe.SetFeatured(featured)
+	})
+}
+
+// SetHidden hides a recipe or makes it visible again on behalf of actor,
+// with the same version check as SetFeatured
+func (s *RecipeService) SetHidden(ctx context.Context, id primitive.ObjectID, hidden bool,
+	actor *entity.User, expectedVersions []int64) (*entity.Recipe, error) {
+	return s.moderate(ctx, id, ActionHideRecipe, actor, expectedVersions, func(recipe *entity.Recipe) {
+		recipe.SetHidden(hidden)
+	})
+}
+
+// moderate applies change to a recipe once actor is allowed to perform
+// action. Moderation is not an edit by the author, so no revision is
+// recorded.
+func (s *RecipeService) moderate(ctx context.Context, id primitive.ObjectID, action Action,
+	actor *entity.User, expectedVersions []int64, change func(*entity.Recipe)) (*entity.Recipe, error) {
+	recipe, err := s.GetRecipe(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(actor, action, recipe); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(recipe, expectedVersions); err != nil {
+		return nil, err
+	}
+
+	readVersion := recipe.Version
+	change(recipe)
+	if err := s.saveRecipe(ctx, recipe, readVersion); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
@@ -0,0 +1,90 @@
+package application
+
+import (
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+func TestHiddenRecipeIsNotFound(t *testing.T) {
+	f := newFixture()
+	owner := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", owner)
+	if _, err := f.recipes.SetHidden(f.ctx, created.ID, true, newUser(entity.RoleModerator), nil); err != nil {
+		t.Fatalf("SetHidden: %v", err)
+	}
+
+	// Someone who cannot see the recipe must not learn that it exists
+	for _, stranger := range []*entity.User{nil, newUser(entity.RoleUser)} {
+		if _, err := f.recipes.GetRecipe(f.ctx, created.ID, stranger); err != ErrRecipeNotFound {
+			t.Errorf("GetRecipe error = %v, want ErrRecipeNotFound", err)
+		}
+		if _, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gin Gimlet"), stranger, nil); err != ErrRecipeNotFound {
+			t.Errorf("UpdateRecipe error = %v, want ErrRecipeNotFound", err)
+		}
+		if _, err := f.recipes.RestoreRevision(f.ctx, created.ID, int(created.Version), stranger, nil); err != ErrRecipeNotFound {
+			t.Errorf("RestoreRevision error = %v, want ErrRecipeNotFound", err)
+		}
+		if _, err := f.recipes.SetFeatured(f.ctx, created.ID, true, stranger, nil); err != ErrRecipeNotFound {
+			t.Errorf("SetFeatured error = %v, want ErrRecipeNotFound", err)
+		}
+		if err := f.recipes.DeleteRecipe(f.ctx, created.ID, stranger, nil); err != ErrRecipeNotFound {
+			t.Errorf("DeleteRecipe error = %v, want ErrRecipeNotFound", err)
+		}
+	}
+
+	if _, err := f.recipes.GetRecipe(f.ctx, created.ID, owner); err != nil {
+		t.Errorf("GetRecipe by the owner: %v", err)
+	}
+	if _, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gin Gimlet"), owner, nil); err != nil {
+		t.Errorf("UpdateRecipe by the owner: %v", err)
+	}
+	if _, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gimlet"), newUser(entity.RoleAdmin), nil); err != nil {
+		t.Errorf("UpdateRecipe by an admin: %v", err)
+	}
+	if err := f.recipes.DeleteRecipe(f.ctx, created.ID, newUser(entity.RoleModerator), nil); err != nil {
+		t.Errorf("DeleteRecipe by a moderator: %v", err)
+	}
+}
+
+func TestRecipePermissions(t *testing.T) {
+	f := newFixture()
+	owner := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", owner)
+	disabled := newUser(entity.RoleAdmin)
+	disabled.Disabled = true
+
+	update := func(actor *entity.User) error {
+		_, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gin Gimlet"), actor, nil)
+		return err
+	}
+	feature := func(actor *entity.User) error {
+		_, err := f.recipes.SetFeatured(f.ctx, created.ID, true, actor, nil)
+		return err
+	}
+	remove := func(actor *entity.User) error {
+		return f.recipes.DeleteRecipe(f.ctx, created.ID, actor, nil)
+	}
+
+	tests := []struct {
+		name  string
+		do    func(*entity.User) error
+		actor *entity.User
+		want  error
+	}{
+		{"anonymous update", update, nil, ErrUnauthorized},
+		{"update by another user", update, newUser(entity.RoleUser), ErrForbidden},
+		{"update by a moderator", update, newUser(entity.RoleModerator), ErrForbidden},
+		{"update by a disabled admin", update, disabled, ErrForbidden},
+		{"delete by another user", remove, newUser(entity.RoleUser), ErrForbidden},
+		{"feature by the owner", feature, owner, ErrForbidden},
+		{"feature by a moderator", feature, newUser(entity.RoleModerator), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.do(tt.actor); err != tt.want {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
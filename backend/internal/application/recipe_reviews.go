@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrReviewNotFound  = errors.New("review not found")
+	ErrReviewOwnRecipe = errors.New("you cannot review your own recipe")
+	ErrDuplicateReview = errors.New("recipe already reviewed; edit the existing review instead")
+)
+
+// ListReviews retrieves every review of a recipe visible to viewer, newest
+// first
+func (s *RecipeService) ListReviews(ctx context.Context, id primitive.ObjectID, viewer *entity.User) ([]*entity.Review, error) {
+	if _, err := s.GetRecipe(ctx, id, viewer); err != nil {
+		return nil, err
+	}
+	return s.reviewRepo.FindByRecipe(ctx, id)
+}
+
+// SaveReview creates actor's review of a recipe or replaces the rating and
+// text of their existing one, then refreshes the recipe's aggregate rating.
+// It reports whether a new review was created.
+func (s *RecipeService) SaveReview(ctx context.Context, id primitive.ObjectID, rating int, text string,
+	actor *entity.User) (*entity.Review, bool, error) {
+	if actor == nil {
+		return nil, false, ErrUnauthorized
+	}
+	recipe, err := s.GetRecipe(ctx, id, actor)
+	if err != nil {
+		return nil, false, err
+	}
+	if actor.Owns(recipe) {
+		return nil, false, ErrReviewOwnRecipe
+	}
+
+	review, err := s.reviewRepo.FindByRecipeAndUser(ctx, id, actor.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	created := review == nil
+	if created {
+		review = entity.NewReview(id, actor.ID, rating, text)
+	} else {
+		review.Update(rating, text)
+	}
+	if err := review.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	if created {
+		err = s.reviewRepo.Create(ctx, review)
+		if err == repository.ErrDuplicateReview {
+			return nil, false, ErrDuplicateReview
+		}
+	} else {
+		err = s.reviewRepo.Update(ctx, review)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := s.refreshRating(ctx, id); err != nil {
+		return nil, false, err
+	}
+	return review, created, nil
+}
+
+// DeleteOwnReview deletes actor's review of a recipe
+func (s *RecipeService) DeleteOwnReview(ctx context.Context, id primitive.ObjectID, actor *entity.User) error {
+	if actor == nil {
+		return ErrUnauthorized
+	}
+	review, err := s.reviewRepo.FindByRecipeAndUser(ctx, id, actor.ID)
+	if err != nil {
+		return err
+	}
+	if review == nil {
+		return ErrReviewNotFound
+	}
+	return s.deleteReview(ctx, review, actor)
+}
+
+// DeleteReview deletes a review of a recipe on behalf of actor, who must be
+// its author or a moderator
+func (s *RecipeService) DeleteReview(ctx context.Context, id, reviewID primitive.ObjectID, actor *entity.User) error {
+	review, err := s.reviewRepo.FindByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review == nil || review.RecipeID != id {
+		return ErrReviewNotFound
+	}
+	return s.deleteReview(ctx, review, actor)
+}
+
+func (s *RecipeService) deleteReview(ctx context.Context, review *entity.Review, actor *entity.User) error {
+	if !CanDeleteReview(actor, review) {
+		if actor == nil {
+			return ErrUnauthorized
+		}
+		return ErrForbidden
+	}
+	if err := s.reviewRepo.Delete(ctx, review.ID); err != nil {
+		return err
+	}
+	return s.refreshRating(ctx, review.RecipeID)
+}
+
+// refreshRating recomputes a recipe's aggregate rating from its reviews.
+// Recomputing rather than adjusting the stored value keeps the aggregate
+// correct even if an earlier refresh was lost.
+func (s *RecipeService) refreshRating(ctx context.Context, id primitive.ObjectID) error {
+	average, count, err := s.reviewRepo.Summarize(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.recipeRepo.UpdateRating(ctx, id, math.Round(average*100)/100, count)
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+func TestReviewsRateRecipe(t *testing.T) {
+	f := newFixture()
+	owner := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", owner)
+	first, second := newUser(entity.RoleUser), newUser(entity.RoleUser)
+
+	review, isNew, err := f.recipes.SaveReview(f.ctx, created.ID, 5, "Bright", first)
+	if err != nil || !isNew {
+		t.Fatalf("SaveReview = %v, %v, want a new review", isNew, err)
+	}
+	if _, _, err := f.recipes.SaveReview(f.ctx, created.ID, 4, "", second); err != nil {
+		t.Fatalf("SaveReview: %v", err)
+	}
+	assertRating(t, f, created, 4.5, 2)
+
+	// Reviewing again replaces the rating rather than adding another
+	again, isNew, err := f.recipes.SaveReview(f.ctx, created.ID, 2, "Too sour", first)
+	if err != nil || isNew {
+		t.Fatalf("second SaveReview = %v, %v, want the existing review updated", isNew, err)
+	}
+	if again.ID != review.ID {
+		t.Errorf("second SaveReview ID = %v, want %v", again.ID, review.ID)
+	}
+	assertRating(t, f, created, 3, 2)
+
+	if err := f.recipes.DeleteOwnReview(f.ctx, created.ID, second); err != nil {
+		t.Fatalf("DeleteOwnReview: %v", err)
+	}
+	assertRating(t, f, created, 2, 1)
+	if err := f.recipes.DeleteOwnReview(f.ctx, created.ID, second); err != ErrReviewNotFound {
+		t.Errorf("second DeleteOwnReview error = %v, want ErrReviewNotFound", err)
+	}
+}
+
+func TestReviewRatingBumpsVersion(t *testing.T) {
+	f := newFixture()
+	owner := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", owner)
+
+	if _, _, err := f.recipes.SaveReview(f.ctx, created.ID, 5, "", newUser(entity.RoleUser)); err != nil {
+		t.Fatalf("SaveReview: %v", err)
+	}
+	// An ETag taken before the review no longer matches
+	stale := []int64{created.Version}
+	if _, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gin Gimlet"), owner, stale); err != ErrVersionConflict {
+		t.Errorf("UpdateRecipe at the version before the review error = %v, want ErrVersionConflict", err)
+	}
+}
+
+func TestSaveReviewRejects(t *testing.T) {
+	f := newFixture()
+	owner := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", owner)
+
+	if _, _, err := f.recipes.SaveReview(f.ctx, created.ID, 5, "", nil); err != ErrUnauthorized {
+		t.Errorf("anonymous SaveReview error = %v, want ErrUnauthorized", err)
+	}
+	if _, _, err := f.recipes.SaveReview(f.ctx, created.ID, 5, "", owner); err != ErrReviewOwnRecipe {
+		t.Errorf("SaveReview by the owner error = %v, want ErrReviewOwnRecipe", err)
+	}
+	var verr *entity.ValidationError
+	if _, _, err := f.recipes.SaveReview(f.ctx, created.ID, 6, "", newUser(entity.RoleUser)); !errors.As(err, &verr) {
+		t.Errorf("SaveReview with a rating of 6 error = %v, want a validation error", err)
+	}
+}
+
+func TestDeleteReview(t *testing.T) {
+	f := newFixture()
+	created := f.createRecipe(t, "Gimlet", newUser(entity.RoleUser))
+	author := newUser(entity.RoleUser)
+	review, _, err := f.recipes.SaveReview(f.ctx, created.ID, 1, "", author)
+	if err != nil {
+		t.Fatalf("SaveReview: %v", err)
+	}
+
+	if err := f.recipes.DeleteReview(f.ctx, created.ID, review.ID, newUser(entity.RoleUser)); err != ErrForbidden {
+		t.Errorf("DeleteReview by another user error = %v, want ErrForbidden", err)
+	}
+	if err := f.recipes.DeleteReview(f.ctx, created.ID, review.ID, newUser(entity.RoleModerator)); err != nil {
+		t.Fatalf("DeleteReview by a moderator: %v", err)
+	}
+	assertRating(t, f, created, 0, 0)
+}
+
+// assertRating checks the aggregate rating stored on recipe
+func assertRating(t *testing.T, f *fixture, recipe *entity.Recipe, rating float64, count int) {
+	t.Helper()
+	stored, err := f.recipes.GetRecipe(f.ctx, recipe.ID, nil)
+	if err != nil {
+		t.Fatalf("GetRecipe: %v", err)
+	}
+	if stored.Rating != rating || stored.RatingCount != count {
+		t.Errorf("rating = %v of %d, want %v of %d", stored.Rating, stored.RatingCount, rating, count)
+	}
+}
@@ -0,0 +1,163 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// RevisionEntry is a stored revision together with the changes it made
+// relative to the revision before it. The first revision has no changes.
+type RevisionEntry struct {
+	Revision *entity.Revision
+	Changes  []entity.FieldChange
+}
+
+// ListRevisions retrieves the revision history of a recipe viewer may see,
+// newest first
+func (s *RecipeService) ListRevisions(ctx context.Context, id primitive.ObjectID, viewer *entity.User) ([]*RevisionEntry, error) {
+	if _, err := s.GetRecipe(ctx, id, viewer); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.FindByRecipe(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*RevisionEntry, 0, len(revisions))
+	for i, revision := range revisions {
+		var previous *entity.Revision
+		if i+1 < len(revisions) {
+			previous = revisions[i+1]
+		}
+		entries = append(entries, newRevisionEntry(revision, previous))
+	}
+	return entries, nil
+}
+
+// GetRevision retrieves a single revision of a recipe viewer may see
+func (s *RecipeService) GetRevision(ctx context.Context, id primitive.ObjectID, number int,
+	viewer *entity.User) (*RevisionEntry, error) {
+	if _, err := s.GetRecipe(ctx, id, viewer); err != nil {
+		return nil, err
+	}
+	revision, err := s.getRevision(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.revisionRepo.FindBefore(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+	return newRevisionEntry(revision, previous), nil
+}
+
+// DiffRevisions lists the changes between two revisions of a recipe viewer
+// may see
+func (s *RecipeService) DiffRevisions(ctx context.Context, id primitive.ObjectID, from, to int,
+	viewer *entity.User) ([]entity.FieldChange, error) {
+	if _, err := s.GetRecipe(ctx, id, viewer); err != nil {
+		return nil, err
+	}
+	fromRevision, err := s.getRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.getRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	return entity.DiffRecipes(&fromRevision.Recipe, &toRevision.Recipe), nil
+}
+
+// RestoreRevision makes an old revision the current version of a recipe on
+// behalf of actor. The restore is itself recorded as a new revision, so
+// nothing is lost. If expectedVersions is set the restore only succeeds
+// while the recipe is still at one of them.
+func (s *RecipeService) RestoreRevision(ctx context.Context, id primitive.ObjectID, number int,
+	actor *entity.User, expectedVersions []int64) (*entity.Recipe, error) {
+	recipe, err := s.GetRecipe(ctx, id, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorize(actor, ActionEditRecipe, recipe); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(recipe, expectedVersions); err != nil {
+		return nil, err
+	}
+	readVersion := recipe.Version
+
+	revision, err := s.getRevision(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+
+	old := revision.Recipe
+	recipe.Update(old.Details())
+
+	if err := s.linkIngredients(ctx, recipe, false); err != nil {
+		return nil, err
+	}
+	if err := recipe.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveRecipe(ctx, recipe, readVersion); err != nil {
+		return nil, err
+	}
+	if err := s.recordRevision(ctx, recipe, actor.ID.Hex(), number); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
+
+func (s *RecipeService) getRevision(ctx context.Context, id primitive.ObjectID, number int) (*entity.Revision, error) {
+	revision, err := s.revisionRepo.FindByNumber(ctx, id, number)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, ErrRevisionNotFound
+	}
+	return revision, nil
+}
+
+// recordRevision appends a snapshot of the recipe's current state to its
+// history, numbered by the version the save that produced it won. Saves
+// are conditional on the version, so concurrent saves never share a number
+// and their revisions keep the order the recipe was written in.
+func (s *RecipeService) recordRevision(ctx context.Context, recipe *entity.Recipe, editedBy string,
+	restoredFrom int) error {
+	return s.revisionRepo.Create(ctx, entity.NewRevision(recipe, int(recipe.Version), editedBy, restoredFrom))
+}
+
+// ensureRevisionBaseline records the current state of a recipe created
+// before revision history existed, so its first update can be undone
+func (s *RecipeService) ensureRevisionBaseline(ctx context.Context, recipe *entity.Recipe) error {
+	latest, err := s.revisionRepo.FindLatest(ctx, recipe.ID)
+	if err != nil || latest != nil {
+		return err
+	}
+	err = s.revisionRepo.Create(ctx, entity.NewRevision(recipe, int(recipe.Version), "", 0))
+	if err == repository.ErrDuplicateRevision {
+		// A concurrent save recorded the baseline first
+		return nil
+	}
+	return err
+}
+
+func newRevisionEntry(revision, previous *entity.Revision) *RevisionEntry {
+	entry := &RevisionEntry{Revision: revision, Changes: []entity.FieldChange{}}
+	if previous != nil {
+		entry.Changes = entity.DiffRecipes(&previous.Recipe, &revision.Recipe)
+	}
+	return entry
+}
@@ -0,0 +1,116 @@
+package application
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+func TestRevisionsNumberedByVersion(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", user)
+
+	// Featuring bumps the version without recording a revision
+	if _, err := f.recipes.SetFeatured(f.ctx, created.ID, true, newUser(entity.RoleModerator), nil); err != nil {
+		t.Fatalf("SetFeatured: %v", err)
+	}
+	updated, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gin Gimlet"), user, nil)
+	if err != nil {
+		t.Fatalf("UpdateRecipe: %v", err)
+	}
+
+	entries, err := f.recipes.ListRevisions(f.ctx, created.ID, user)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	var numbers []int
+	for _, entry := range entries {
+		numbers = append(numbers, entry.Revision.Number)
+	}
+	if want := []int{int(updated.Version), int(created.Version)}; !reflect.DeepEqual(numbers, want) {
+		t.Fatalf("revision numbers = %v, want %v", numbers, want)
+	}
+
+	// The diff of a revision is against the one before it, across the gap
+	entry, err := f.recipes.GetRevision(f.ctx, created.ID, int(updated.Version), user)
+	if err != nil {
+		t.Fatalf("GetRevision: %v", err)
+	}
+	if len(entry.Changes) != 1 || entry.Changes[0].Field != "name" {
+		t.Errorf("Changes = %+v, want only the name", entry.Changes)
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", user)
+	if _, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gin Gimlet"), user, nil); err != nil {
+		t.Fatalf("UpdateRecipe: %v", err)
+	}
+
+	restored, err := f.recipes.RestoreRevision(f.ctx, created.ID, int(created.Version), user, nil)
+	if err != nil {
+		t.Fatalf("RestoreRevision: %v", err)
+	}
+	if restored.Name != "Gimlet" {
+		t.Errorf("Name = %q, want Gimlet", restored.Name)
+	}
+
+	entries, err := f.recipes.ListRevisions(f.ctx, created.ID, user)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d revisions, want 3", len(entries))
+	}
+	latest := entries[0].Revision
+	if latest.Number != int(restored.Version) || latest.RestoredFrom != int(created.Version) {
+		t.Errorf("latest revision = %d restored from %d, want %d restored from %d",
+			latest.Number, latest.RestoredFrom, restored.Version, created.Version)
+	}
+
+	if _, err := f.recipes.RestoreRevision(f.ctx, created.ID, 99, user, nil); err != ErrRevisionNotFound {
+		t.Errorf("RestoreRevision of an unknown revision error = %v, want ErrRevisionNotFound", err)
+	}
+}
+
+func TestConcurrentUpdatesKeepRevisionOrder(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", user)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail(fmt.Sprintf("Gimlet %d", i)), user, nil)
+			if err != nil && err != ErrVersionConflict {
+				t.Errorf("UpdateRecipe: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	stored, err := f.recipes.GetRecipe(f.ctx, created.ID, user)
+	if err != nil {
+		t.Fatalf("GetRecipe: %v", err)
+	}
+	entries, err := f.recipes.ListRevisions(f.ctx, created.ID, user)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	latest := entries[0].Revision
+	if latest.Number != int(stored.Version) || latest.Recipe.Name != stored.Name {
+		t.Errorf("latest revision is %d %q, want the stored recipe at %d %q",
+			latest.Number, latest.Recipe.Name, stored.Version, stored.Name)
+	}
+	if want := int(stored.Version - created.Version + 1); len(entries) != want {
+		t.Errorf("got %d revisions, want one per saved version, %d", len(entries), want)
+	}
+}
@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/units"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrInvalidServings  = errors.New("servings must be between 1 and 1000")
+	ErrInvalidDilution  = errors.New("dilution must be between 0 and 100 percent")
+	ErrBatchNotCocktail = errors.New("only cocktails can be batched")
+)
+
+const (
+	// MaxServings caps how far a recipe may be scaled up
+	MaxServings = 1000
+	// DefaultDilutionPercent is how much water a batch gets when the caller
+	// does not say, roughly what stirring over ice adds to a drink
+	DefaultDilutionPercent = 20.0
+)
+
+// BatchPlan describes how to pre-batch a scaled cocktail. Shaking or stirring
+// over ice normally dilutes each drink, so a batch that will be poured
+// straight from the bottle needs that water added up front.
+type BatchPlan struct {
+	DilutionPercent float64
+	Liquid          units.Quantity
+	Water           units.Quantity
+	Total           units.Quantity
+	PerServing      units.Quantity
+}
+
+// ScaledRecipe is a copy of a recipe with its ingredients scaled to a number
+// of servings. The copy is never saved.
+type ScaledRecipe struct {
+	Recipe   *entity.Recipe
+	Servings int
+	Factor   float64
+	Batch    *BatchPlan
+}
+
+// ScaleRecipe scales a recipe's ingredients from the servings it is written
+// for to the given number of servings, presenting amounts and the oven
+// temperature in system when it is not empty. When batch is true the result
+// also says how much water to add so a cocktail batch is diluted by
+// dilutionPercent. Only recipes viewer may see can be scaled.
+func (s *RecipeService) ScaleRecipe(ctx context.Context, id primitive.ObjectID, servings int,
+	batch bool, dilutionPercent float64, system units.System, viewer *entity.User) (*ScaledRecipe, error) {
+	if servings < 1 || servings > MaxServings {
+		return nil, ErrInvalidServings
+	}
+	if dilutionPercent < 0 || dilutionPercent > 100 {
+		return nil, ErrInvalidDilution
+	}
+
+	recipe, err := s.GetRecipe(ctx, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+	if batch && recipe.Type != entity.RecipeTypeCocktail {
+		return nil, ErrBatchNotCocktail
+	}
+
+	factor := float64(servings) / float64(recipe.BaseServings())
+	liquid := recipe.LiquidVolume() * factor
+	if system == "" {
+		system = recipe.PreferredSystem()
+	} else if recipe.OvenTemperature != nil {
+		oven := recipe.OvenTemperature.ToSystem(system)
+		recipe.OvenTemperature = &oven
+	}
+	recipe.Scale(factor, system)
+	recipe.Servings = servings
+
+	scaled := &ScaledRecipe{
+		Recipe:   recipe,
+		Servings: servings,
+		Factor:   factor,
+	}
+	if !batch {
+		return scaled, nil
+	}
+
+	water := liquid * dilutionPercent / 100
+	plan := &BatchPlan{
+		DilutionPercent: dilutionPercent,
+		Liquid:          humanizeVolume(liquid, system),
+		Water:           humanizeVolume(water, system),
+		Total:           humanizeVolume(liquid+water, system),
+		PerServing:      humanizeVolume((liquid+water)/float64(servings), system),
+	}
+	if water > 0 {
+		recipe.Ingredients = append(recipe.Ingredients, entity.Ingredient{
+			Name:   "Water",
+			Amount: plan.Water.Amount,
+			Unit:   plan.Water.Unit,
+			Notes:  "for dilution, chill the batch before serving",
+		})
+	}
+	scaled.Batch = plan
+	return scaled, nil
+}
+
+func humanizeVolume(ml float64, system units.System) units.Quantity {
+	amount, unit := units.Humanize(ml, units.Volume, system)
+	return units.Quantity{Amount: amount, Unit: unit}
+}
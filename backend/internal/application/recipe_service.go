@@ -6,46 +6,118 @@ import (
 
 	"fork-and-shaker/internal/domain/entity"
 	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/domain/search"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var (
-	ErrRecipeNotFound = errors.New("recipe not found")
-	ErrInvalidRecipe  = errors.New("invalid recipe data")
-	ErrUnauthorized   = errors.New("unauthorized")
+	ErrRecipeNotFound     = errors.New("recipe not found")
+	ErrInvalidRecipe      = errors.New("invalid recipe data")
+	ErrUnauthorized       = errors.New("authentication required")
+	ErrForbidden          = errors.New("you are not allowed to do that")
+	ErrInvalidListOptions = errors.New("invalid pagination or sort parameters")
+	ErrNoIngredients      = errors.New("at least one ingredient is required")
+	ErrVersionConflict    = errors.New("recipe has been modified since it was read")
+	ErrEmptySearch        = errors.New("search query or ABV range is required")
+	ErrInvalidABVRange    = errors.New("ABV bounds must be between 0 and 100 with min not above max")
+	ErrInvalidRecipeType  = errors.New("recipe type must be cocktail or food")
 )
 
+const (
+	// DefaultPageSize is the page size used when a listing does not set one
+	DefaultPageSize = 20
+	// MaxPageSize caps how many recipes a single page may return
+	MaxPageSize = 100
+
+	// DefaultMaxMissing is how many missing ingredients a near miss may have
+	// when the caller does not say
+	DefaultMaxMissing = 2
+	// MaxMissingLimit caps how far from makeable a near miss may be
+	MaxMissingLimit = 5
+)
+
+// MakeableResult splits the recipes that can be made from a set of
+// ingredients into those that are fully covered and those that are close
+type MakeableResult struct {
+	Makeable   []*entity.Recipe
+	NearMisses []*repository.MakeableRecipe
+}
+
 // RecipeService handles the business logic for recipes
 type RecipeService struct {
-	recipeRepo repository.RecipeRepository
+	recipeRepo     repository.RecipeRepository
+	revisionRepo   repository.RevisionRepository
+	ingredientRepo repository.IngredientRepository
+	reviewRepo     repository.ReviewRepository
+	favoriteRepo   repository.FavoriteRepository
+	collectionRepo repository.CollectionRepository
+	recommender    *Recommender
 }
 
 // NewRecipeService creates a new RecipeService
-func NewRecipeService(recipeRepo repository.RecipeRepository) *RecipeService {
+func NewRecipeService(recipeRepo repository.RecipeRepository,
+	revisionRepo repository.RevisionRepository,
+	ingredientRepo repository.IngredientRepository,
+	reviewRepo repository.ReviewRepository,
+	favoriteRepo repository.FavoriteRepository,
+	collectionRepo repository.CollectionRepository,
+	recommender *Recommender) *RecipeService {
 	return &RecipeService{
-		recipeRepo: recipeRepo,
+		recipeRepo:     recipeRepo,
+		revisionRepo:   revisionRepo,
+		ingredientRepo: ingredientRepo,
+		reviewRepo:     reviewRepo,
+		favoriteRepo:   favoriteRepo,
+		collectionRepo: collectionRepo,
+		recommender:    recommender,
 	}
 }
 
-// CreateRecipe creates a new recipe
-func (s *RecipeService) CreateRecipe(ctx context.Context, name, description string, 
-	ingredients []entity.Ingredient, instructions []string, glass, garnish string) (*entity.Recipe, error) {
-	
-	recipe := entity.NewRecipe(name, entity.RecipeTypeCocktail, description, 
-		ingredients, instructions, glass, garnish)
-
-	if !recipe.Validate() {
-		return nil, ErrInvalidRecipe
+// CreateRecipe creates a new recipe owned by creator. Recipes that do not say
+// what type they are are cocktails.
+func (s *RecipeService) CreateRecipe(ctx context.Context, details entity.RecipeDetails,
+	creator *entity.User) (*entity.Recipe, error) {
+	if creator == nil {
+		return nil, ErrUnauthorized
 	}
-
-	err := s.recipeRepo.Create(ctx, recipe)
+	recipe, err := s.newRecipe(ctx, details, creator)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.insertRecipe(ctx, recipe, creator); err != nil {
+		return nil, err
+	}
+	return recipe, nil
+}
 
+// newRecipe builds and validates a recipe owned by creator without saving it
+func (s *RecipeService) newRecipe(ctx context.Context, details entity.RecipeDetails,
+	creator *entity.User) (*entity.Recipe, error) {
+	if details.Type == "" {
+		details.Type = entity.RecipeTypeCocktail
+	}
+	recipe := entity.NewRecipe(details)
+	creatorID := creator.ID
+	recipe.CreatorID = &creatorID
+
+	if err := s.linkIngredients(ctx, recipe, true); err != nil {
+		return nil, err
+	}
+	if err := recipe.Validate(); err != nil {
+		return nil, err
+	}
 	return recipe, nil
 }
 
+// insertRecipe saves a recipe built by newRecipe with its first revision
+func (s *RecipeService) insertRecipe(ctx context.Context, recipe *entity.Recipe, creator *entity.User) error {
+	if err := s.recipeRepo.Create(ctx, recipe); err != nil {
+		return err
+	}
+	s.recommender.Refresh(recipe)
+	return s.recordRevision(ctx, recipe, creator.ID.Hex(), 0)
+}
+
 // GetRecipeByID retrieves a recipe by ID
 func (s *RecipeService) GetRecipeByID(ctx context.Context, id primitive.ObjectID) (*entity.Recipe, error) {
 	recipe, err := s.recipeRepo.FindByID(ctx, id)
@@ -58,59 +130,320 @@ func (s *RecipeService) GetRecipeByID(ctx context.Context, id primitive.ObjectID
 	return recipe, nil
 }
 
-// GetCocktailRecipes retrieves all cocktail recipes
-func (s *RecipeService) GetCocktailRecipes(ctx context.Context) ([]*entity.Recipe, error) {
-	return s.recipeRepo.FindByType(ctx, entity.RecipeTypeCocktail)
+// ListRecipes retrieves a page of the recipes passing filter together with
+// facet counts for each classification dimension. Tags and the season are
+// normalized; an unknown base spirit, technique, season or flavor axis is a
+// validation error.
+func (s *RecipeService) ListRecipes(ctx context.Context, filter repository.ClassificationFilter,
+	opts repository.ListOptions) (*repository.RecipePage, *repository.RecipeFacets, error) {
+	if filter.Type != "" && !filter.Type.Valid() {
+		return nil, nil, ErrInvalidRecipeType
+	}
+	filter, err := normalizeClassificationFilter(filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts, err = normalizeListOptions(opts, repository.SortByCreatedAt, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page, err := s.recipeRepo.FindByClassification(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	facets, err := s.recipeRepo.Facets(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page, facets, nil
 }
 
-// UpdateRecipe updates a recipe
-func (s *RecipeService) UpdateRecipe(ctx context.Context, id primitive.ObjectID, 
-	name, description string, ingredients []entity.Ingredient, 
-	instructions []string, glass, garnish string) (*entity.Recipe, error) {
-	
-	recipe, err := s.GetRecipeByID(ctx, id)
+// normalizeClassificationFilter normalizes the tags and season of a listing
+// filter the way they are stored and checks every other dimension names a
+// known value
+func normalizeClassificationFilter(filter repository.ClassificationFilter) (repository.ClassificationFilter, error) {
+	verr := &entity.ValidationError{}
+
+	var tags []string
+	for _, tag := range filter.Tags {
+		if tag = entity.NormalizeTag(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > entity.MaxTags {
+		verr.Add("tag", "at most %d tags can be combined", entity.MaxTags)
+	}
+	filter.Tags = tags
+
+	if !filter.BaseSpirit.Valid() {
+		verr.Add("base_spirit", "unknown base spirit %q", filter.BaseSpirit)
+	}
+	if !filter.Technique.Valid() {
+		verr.Add("technique", "unknown technique %q", filter.Technique)
+	}
+	filter.Season = entity.NormalizeSeason(filter.Season)
+	if !filter.Season.Valid() {
+		verr.Add("season", "unknown season %q", filter.Season)
+	}
+	for _, axis := range filter.Flavors {
+		if !axis.Valid() {
+			verr.Add("flavor", "unknown flavor %q", axis)
+		}
+	}
+
+	return filter, verr.Err()
+}
+
+// UpdateRecipe updates a recipe on behalf of actor, recording the new
+// version in its revision history. If expectedVersions is set the
+// update only succeeds while the recipe is still at one of them.
+func (s *RecipeService) UpdateRecipe(ctx context.Context, id primitive.ObjectID,
+	details entity.RecipeDetails, actor *entity.User, expectedVersions []int64) (*entity.Recipe, error) {
+	recipe, err := s.GetRecipe(ctx, id, actor)
 	if err != nil {
 		return nil, err
 	}
+	if err := authorize(actor, ActionEditRecipe, recipe); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(recipe, expectedVersions); err != nil {
+		return nil, err
+	}
+	readVersion := recipe.Version
 
-	recipe.Update(name, description, ingredients, instructions, glass, garnish)
-	
-	if !recipe.Validate() {
-		return nil, ErrInvalidRecipe
+	if err := s.ensureRevisionBaseline(ctx, recipe); err != nil {
+		return nil, err
+	}
+
+	recipe.Update(details)
+
+	if err := s.linkIngredients(ctx, recipe, true); err != nil {
+		return nil, err
+	}
+	if err := recipe.Validate(); err != nil {
+		return nil, err
 	}
 
-	err = s.recipeRepo.Update(ctx, recipe)
+	err = s.saveRecipe(ctx, recipe, readVersion)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.recordRevision(ctx, recipe, actor.ID.Hex(), 0); err != nil {
+		return nil, err
+	}
+
 	return recipe, nil
 }
 
-// DeleteRecipe deletes a recipe
-func (s *RecipeService) DeleteRecipe(ctx context.Context, id primitive.ObjectID) error {
-	_, err := s.GetRecipeByID(ctx, id)
+// DeleteRecipe deletes a recipe and its reviews and revisions on behalf of
+// actor, and takes it out of every favorites list and collection. If
+// expectedVersions is set the recipe is only deleted while it is still at
+// one of them; either way it is not deleted if someone saves it after it
+// was read.
+func (s *RecipeService) DeleteRecipe(ctx context.Context, id primitive.ObjectID, actor *entity.User,
+	expectedVersions []int64) error {
+	recipe, err := s.GetRecipe(ctx, id, actor)
 	if err != nil {
 		return err
 	}
+	if err := authorize(actor, ActionDeleteRecipe, recipe); err != nil {
+		return err
+	}
+	if err := checkVersion(recipe, expectedVersions); err != nil {
+		return err
+	}
 
-	return s.recipeRepo.Delete(ctx, id)
+	err = s.recipeRepo.Delete(ctx, id, recipe.Version)
+	if err == repository.ErrVersionConflict {
+		return ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+	s.recommender.Remove(id)
+	if err := s.reviewRepo.DeleteByRecipe(ctx, id); err != nil {
+		return err
+	}
+	if err := s.favoriteRepo.DeleteByRecipe(ctx, id); err != nil {
+		return err
+	}
+	if err := s.collectionRepo.RemoveRecipe(ctx, id); err != nil {
+		return err
+	}
+	return s.revisionRepo.DeleteByRecipe(ctx, id)
+}
+
+// saveRecipe writes back a recipe that was read at readVersion, failing
+// with ErrVersionConflict if someone else saved it in the meantime
+func (s *RecipeService) saveRecipe(ctx context.Context, recipe *entity.Recipe, readVersion int64) error {
+	err := s.recipeRepo.Update(ctx, recipe, readVersion)
+	if err == repository.ErrVersionConflict {
+		return ErrVersionConflict
+	}
+	if err != nil {
+		return err
+	}
+	s.recommender.Refresh(recipe)
+	return nil
 }
 
-// SearchRecipes searches for recipes
-func (s *RecipeService) SearchRecipes(ctx context.Context, query string, cocktailsOnly bool) ([]*entity.Recipe, error) {
-	var recipeType *entity.RecipeType
-	if cocktailsOnly {
-		t := entity.RecipeTypeCocktail
-		recipeType = &t
+// checkVersion reports a conflict when the caller expects versions other
+// than the one stored. A nil expectation matches any version.
+func checkVersion(recipe *entity.Recipe, expectedVersions []int64) error {
+	if expectedVersions == nil {
+		return nil
+	}
+	for _, version := range expectedVersions {
+		if version == recipe.Version {
+			return nil
+		}
 	}
-	return s.recipeRepo.Search(ctx, query, recipeType)
+	return ErrVersionConflict
 }
 
-// FindByIngredient searches for recipes containing a specific ingredient
-func (s *RecipeService) FindByIngredient(ctx context.Context, ingredient string) ([]*entity.Recipe, error) {
+// SearchRecipes searches for recipes, most relevant first unless another
+// sort is requested, and highlights where each recipe on the page matched.
+// Words match despite small typos and differences in case, accents and
+// inflection. The query may be empty when the filter has an ABV bound,
+// listing every recipe in that range by name.
+func (s *RecipeService) SearchRecipes(ctx context.Context, text string, filter repository.SearchFilter,
+	opts repository.ListOptions) (*repository.RecipePage, map[primitive.ObjectID][]search.Highlight, error) {
+	if err := validateABVRange(filter.MinABV, filter.MaxABV); err != nil {
+		return nil, nil, err
+	}
+	if filter.Type != nil && !filter.Type.Valid() {
+		return nil, nil, ErrInvalidRecipeType
+	}
+
+	query := search.ParseQuery(text)
+	defaultSort := repository.SortByRelevance
+	if query.Empty() {
+		if filter.MinABV == nil && filter.MaxABV == nil {
+			return nil, nil, ErrEmptySearch
+		}
+		defaultSort = repository.SortByName
+	}
+
+	opts, err := normalizeListOptions(opts, defaultSort, !query.Empty())
+	if err != nil {
+		return nil, nil, err
+	}
+	page, err := s.recipeRepo.Search(ctx, query, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	highlights := make(map[primitive.ObjectID][]search.Highlight, len(page.Recipes))
+	if !query.Empty() {
+		for _, recipe := range page.Recipes {
+			highlights[recipe.ID] = search.Highlights(recipe, query)
+		}
+	}
+	return page, highlights, nil
+}
+
+// FindByIngredient searches for recipes containing a specific ingredient.
+// When the ingredient is in the catalog, recipes using any of its aliases or
+// linking to it match too.
+func (s *RecipeService) FindByIngredient(ctx context.Context, ingredient string,
+	opts repository.ListOptions) (*repository.RecipePage, error) {
 	if ingredient == "" {
 		return nil, ErrInvalidRecipe
 	}
-	return s.recipeRepo.FindByIngredient(ctx, ingredient)
-} 
\ No newline at end of file
+	opts, err := normalizeListOptions(opts, repository.SortByCreatedAt, false)
+	if err != nil {
+		return nil, err
+	}
+	match, err := s.ingredientMatch(ctx, ingredient)
+	if err != nil {
+		return nil, err
+	}
+	return s.recipeRepo.FindByIngredient(ctx, match, opts)
+}
+
+// normalizeListOptions applies the default page size and sort and rejects
+// sorts the listing does not support
+func normalizeListOptions(opts repository.ListOptions, defaultSort repository.SortField,
+	allowRelevance bool) (repository.ListOptions, error) {
+	if opts.Limit < 0 {
+		return opts, ErrInvalidListOptions
+	}
+	if opts.Limit == 0 {
+		opts.Limit = DefaultPageSize
+	}
+	if opts.Limit > MaxPageSize {
+		opts.Limit = MaxPageSize
+	}
+
+	switch opts.Sort {
+	case "":
+		opts.Sort = defaultSort
+	case repository.SortByName, repository.SortByCreatedAt, repository.SortByUpdatedAt, repository.SortByRating:
+	case repository.SortByRelevance:
+		if !allowRelevance {
+			return opts, ErrInvalidListOptions
+		}
+	default:
+		return opts, ErrInvalidListOptions
+	}
+	return opts, nil
+} 
+
+// FindMakeable returns the recipes whose non-optional ingredients are all in
+// available, followed by near misses ranked by how many ingredients they
+// lack. Available ingredients are resolved through the catalog, so having
+// "fresh lime" covers a recipe calling for "lime juice". maxMissing is
+// capped at MaxMissingLimit; a zero limit means DefaultPageSize.
+func (s *RecipeService) FindMakeable(ctx context.Context, available []string, maxMissing, limit int) (*MakeableResult, error) {
+	seen := make(map[string]bool, len(available))
+	names := make([]string, 0, len(available))
+	for _, name := range available {
+		name = entity.NormalizeIngredientName(name)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, ErrNoIngredients
+	}
+
+	if maxMissing < 0 || limit < 0 {
+		return nil, ErrInvalidListOptions
+	}
+	if maxMissing > MaxMissingLimit {
+		maxMissing = MaxMissingLimit
+	}
+	if limit == 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	expanded, ids, err := s.expandIngredientNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	have := repository.AvailableIngredients{Names: expanded, CatalogIDs: ids}
+
+	matches, err := s.recipeRepo.FindMakeable(ctx, have, maxMissing, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MakeableResult{
+		Makeable:   []*entity.Recipe{},
+		NearMisses: []*repository.MakeableRecipe{},
+	}
+	for _, m := range matches {
+		if len(m.Missing) == 0 {
+			result.Makeable = append(result.Makeable, m.Recipe)
+		} else {
+			result.NearMisses = append(result.NearMisses, m)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,187 @@
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateRecipe(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+
+	created := f.createRecipe(t, "Gimlet", user)
+	if created.ID.IsZero() {
+		t.Fatal("CreateRecipe left the ID unset")
+	}
+	if created.Type != entity.RecipeTypeCocktail {
+		t.Errorf("Type = %q, want cocktail by default", created.Type)
+	}
+	if created.CreatorID == nil || *created.CreatorID != user.ID {
+		t.Errorf("CreatorID = %v, want %v", created.CreatorID, user.ID)
+	}
+
+	got, err := f.recipes.GetRecipe(f.ctx, created.ID, nil)
+	if err != nil {
+		t.Fatalf("GetRecipe: %v", err)
+	}
+	if got.Name != "Gimlet" || len(got.Ingredients) != 2 || len(got.Instructions) != 2 {
+		t.Errorf("GetRecipe = %+v, want the created recipe", got)
+	}
+}
+
+func TestCreateRecipeRejects(t *testing.T) {
+	f := newFixture()
+
+	if _, err := f.recipes.CreateRecipe(f.ctx, cocktail("Gimlet"), nil); err != ErrUnauthorized {
+		t.Errorf("anonymous CreateRecipe error = %v, want ErrUnauthorized", err)
+	}
+
+	details := cocktail("")
+	details.Ingredients = nil
+	_, err := f.recipes.CreateRecipe(f.ctx, details, newUser(entity.RoleUser))
+	var verr *entity.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("CreateRecipe error = %v, want a validation error", err)
+	}
+	fields := make(map[string]bool)
+	for _, fe := range verr.Errors {
+		fields[fe.Field] = true
+	}
+	if !fields["name"] || !fields["ingredients"] {
+		t.Errorf("validation errors = %+v, want name and ingredients", verr.Errors)
+	}
+}
+
+func TestUpdateRecipe(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", user)
+
+	details := cocktail("Gin Gimlet")
+	details.Garnish = "Lime wheel"
+	updated, err := f.recipes.UpdateRecipe(f.ctx, created.ID, details, user, nil)
+	if err != nil {
+		t.Fatalf("UpdateRecipe: %v", err)
+	}
+	if updated.Version <= created.Version {
+		t.Errorf("Version = %d, want more than %d", updated.Version, created.Version)
+	}
+
+	got, err := f.recipes.GetRecipe(f.ctx, created.ID, nil)
+	if err != nil {
+		t.Fatalf("GetRecipe: %v", err)
+	}
+	if got.Name != "Gin Gimlet" || got.Garnish != "Lime wheel" {
+		t.Errorf("stored recipe = %+v, want the update", got)
+	}
+}
+
+func TestDeleteRecipe(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", user)
+
+	if err := f.recipes.DeleteRecipe(f.ctx, created.ID, user, nil); err != nil {
+		t.Fatalf("DeleteRecipe: %v", err)
+	}
+	if _, err := f.recipes.GetRecipe(f.ctx, created.ID, nil); err != ErrRecipeNotFound {
+		t.Errorf("GetRecipe after delete error = %v, want ErrRecipeNotFound", err)
+	}
+	if err := f.recipes.DeleteRecipe(f.ctx, created.ID, user, nil); err != ErrRecipeNotFound {
+		t.Errorf("second DeleteRecipe error = %v, want ErrRecipeNotFound", err)
+	}
+	if _, err := f.recipes.GetRecipe(f.ctx, primitive.NewObjectID(), nil); err != ErrRecipeNotFound {
+		t.Errorf("GetRecipe of an unknown ID error = %v, want ErrRecipeNotFound", err)
+	}
+}
+
+func TestListRecipes(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	for _, name := range []string{"Gimlet", "Daiquiri", "Negroni"} {
+		f.createRecipe(t, name, user)
+	}
+
+	page, _, err := f.recipes.ListRecipes(f.ctx, repository.ClassificationFilter{},
+		repository.ListOptions{Limit: 2, Sort: repository.SortByName})
+	if err != nil {
+		t.Fatalf("ListRecipes: %v", err)
+	}
+	if page.Total != 3 || len(page.Recipes) != 2 || page.Recipes[0].Name != "Daiquiri" {
+		t.Errorf("ListRecipes = %d of %d starting %q, want 2 of 3 starting Daiquiri",
+			len(page.Recipes), page.Total, page.Recipes[0].Name)
+	}
+}
+
+func TestFindMakeable(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	kir := entity.RecipeDetails{
+		Name: "Kir Royale",
+		Ingredients: []entity.Ingredient{
+			{Name: "Crème de cassis", Amount: 0.5, Unit: "oz"},
+			{Name: "Champagne", Amount: 4, Unit: "oz"},
+			{Name: "Lemon twist", IsOptional: true},
+		},
+		Instructions: []string{"Build in a flute"},
+	}
+	if _, err := f.recipes.CreateRecipe(f.ctx, kir, user); err != nil {
+		t.Fatalf("CreateRecipe: %v", err)
+	}
+	f.createRecipe(t, "Gimlet", user)
+
+	result, err := f.recipes.FindMakeable(f.ctx, []string{" CRÈME DE CASSIS ", "champagne"}, 1, 0)
+	if err != nil {
+		t.Fatalf("FindMakeable: %v", err)
+	}
+	if len(result.Makeable) != 1 || result.Makeable[0].Name != "Kir Royale" {
+		t.Errorf("Makeable = %v, want only Kir Royale", result.Makeable)
+	}
+	if len(result.NearMisses) != 0 {
+		t.Errorf("NearMisses = %v, want none: the Gimlet lacks two ingredients", result.NearMisses)
+	}
+
+	result, err = f.recipes.FindMakeable(f.ctx, []string{"gin"}, 1, 0)
+	if err != nil {
+		t.Fatalf("FindMakeable: %v", err)
+	}
+	if len(result.NearMisses) != 1 || len(result.NearMisses[0].Missing) != 1 ||
+		result.NearMisses[0].Missing[0] != "Lime juice" {
+		t.Errorf("NearMisses = %+v, want the Gimlet missing Lime juice", result.NearMisses)
+	}
+
+	if _, err := f.recipes.FindMakeable(f.ctx, []string{" "}, 1, 0); err != ErrNoIngredients {
+		t.Errorf("FindMakeable with no names error = %v, want ErrNoIngredients", err)
+	}
+}
+
+func TestVersionConflicts(t *testing.T) {
+	f := newFixture()
+	user := newUser(entity.RoleUser)
+	created := f.createRecipe(t, "Gimlet", user)
+	stale := []int64{created.Version}
+
+	updated, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Gin Gimlet"), user, stale)
+	if err != nil {
+		t.Fatalf("UpdateRecipe at the current version: %v", err)
+	}
+	if _, err := f.recipes.UpdateRecipe(f.ctx, created.ID, cocktail("Vodka Gimlet"), user, stale); err != ErrVersionConflict {
+		t.Errorf("UpdateRecipe at a stale version error = %v, want ErrVersionConflict", err)
+	}
+	if _, err := f.recipes.SetFeatured(f.ctx, created.ID, true, newUser(entity.RoleModerator), stale); err != ErrVersionConflict {
+		t.Errorf("SetFeatured at a stale version error = %v, want ErrVersionConflict", err)
+	}
+	if err := f.recipes.DeleteRecipe(f.ctx, created.ID, user, stale); err != ErrVersionConflict {
+		t.Errorf("DeleteRecipe at a stale version error = %v, want ErrVersionConflict", err)
+	}
+
+	// Any one of several expected versions will do
+	current := []int64{created.Version, updated.Version}
+	if err := f.recipes.DeleteRecipe(f.ctx, created.ID, user, current); err != nil {
+		t.Errorf("DeleteRecipe at one of the expected versions: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidSimilarLimit = errors.New("limit must be a positive integer")
+
+// DefaultSimilarRecipes is how many similar recipes are returned when the
+// caller does not say
+const DefaultSimilarRecipes = 5
+
+// SimilarRecipe is a recipe recommended for being like another one
+type SimilarRecipe struct {
+	Recipe *entity.Recipe
+	// Score is how similar the recipe is, from 0 to 1
+	Score float64
+}
+
+// SimilarRecipes returns up to limit recipes most like the one with the
+// given ID, most similar first, on behalf of viewer, who may be nil. Recipes
+// are alike when they share ingredients in similar proportions, and more so
+// when they use the same glass and technique and have words of their name
+// and description in common.
+func (s *RecipeService) SimilarRecipes(ctx context.Context, id primitive.ObjectID, viewer *entity.User,
+	limit int) ([]*SimilarRecipe, error) {
+	if limit < 0 {
+		return nil, ErrInvalidSimilarLimit
+	}
+	if limit == 0 {
+		limit = DefaultSimilarRecipes
+	}
+	limit = min(limit, MaxSimilarRecipes)
+
+	if _, err := s.GetRecipe(ctx, id, viewer); err != nil {
+		return nil, err
+	}
+
+	scores := s.recommender.Similar(id, limit)
+	ids := make([]primitive.ObjectID, len(scores))
+	for i, score := range scores {
+		ids[i] = score.ID
+	}
+	recipes, err := s.recipeRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]*entity.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		byID[recipe.ID] = recipe
+	}
+
+	similar := make([]*SimilarRecipe, 0, len(scores))
+	for _, score := range scores {
+		recipe, ok := byID[score.ID]
+		if !ok || !Can(viewer, ActionViewRecipe, recipe) {
+			continue
+		}
+		similar = append(similar, &SimilarRecipe{Recipe: recipe, Score: score.Score})
+	}
+	return similar, nil
+}
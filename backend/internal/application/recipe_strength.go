@@ -0,0 +1,40 @@
+package application
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// EstimateStrength computes the estimated ABV, finished volume and standard
+// drinks of a cocktail, preferring the strengths recorded in the catalog. It
+// returns nil for other recipes and for cocktails without any liquid
+// ingredients.
+func (s *RecipeService) EstimateStrength(ctx context.Context, recipe *entity.Recipe) (*entity.Strength, error) {
+	if recipe.Type != entity.RecipeTypeCocktail {
+		return nil, nil
+	}
+	entries, err := s.catalogEntries(ctx, recipe)
+	if err != nil {
+		return nil, err
+	}
+	strength, ok := recipe.Strength(entity.CatalogABV(entries))
+	if !ok {
+		return nil, nil
+	}
+	return &strength, nil
+}
+
+// validateABVRange checks optional ABV bounds given in percent
+func validateABVRange(min, max *float64) error {
+	if min != nil && (*min < 0 || *min > 100) {
+		return ErrInvalidABVRange
+	}
+	if max != nil && (*max < 0 || *max > 100) {
+		return ErrInvalidABVRange
+	}
+	if min != nil && max != nil && *min > *max {
+		return ErrInvalidABVRange
+	}
+	return nil
+}
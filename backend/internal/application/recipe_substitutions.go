@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidConfidence is returned for a confidence threshold outside 0..1
+var ErrInvalidConfidence = errors.New("min_confidence must be between 0 and 1")
+
+// DefaultMinConfidence is how sure a substitution must be before it is used
+// to widen an ingredient search
+const DefaultMinConfidence = 0.5
+
+// IngredientSubstitutes pairs a recipe ingredient with what could replace it
+type IngredientSubstitutes struct {
+	Ingredient  entity.Ingredient
+	Substitutes []entity.Substitute
+}
+
+// GetSubstitutions suggests substitutes for each ingredient of a recipe
+// viewer may see. Ingredients linked to the catalog are also looked up under
+// the catalog entry's name and aliases.
+func (s *RecipeService) GetSubstitutions(ctx context.Context, id primitive.ObjectID,
+	viewer *entity.User) ([]IngredientSubstitutes, error) {
+	recipe, err := s.GetRecipe(ctx, id, viewer)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.catalogEntries(ctx, recipe)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]IngredientSubstitutes, 0, len(recipe.Ingredients))
+	for _, ing := range recipe.Ingredients {
+		names := []string{ing.Name}
+		if ing.CatalogID != nil {
+			if entry := entries[*ing.CatalogID]; entry != nil {
+				names = append(names, entry.Name)
+				names = append(names, entry.Aliases...)
+			}
+		}
+
+		subs := []entity.Substitute{}
+		for _, name := range names {
+			if subs = entity.SubstitutesFor(name); len(subs) > 0 {
+				break
+			}
+		}
+		result = append(result, IngredientSubstitutes{Ingredient: ing, Substitutes: subs})
+	}
+	return result, nil
+}
+
+// FindByIngredientOrSubstitute is FindByIngredient widened to the recipes
+// the ingredient can stand in for, such as recipes calling for rye when
+// searching for bourbon. It also returns the substitutions that were used.
+func (s *RecipeService) FindByIngredientOrSubstitute(ctx context.Context, ingredient string,
+	minConfidence float64, opts repository.ListOptions) (*repository.RecipePage, []entity.Substitution, error) {
+	if ingredient == "" {
+		return nil, nil, ErrInvalidRecipe
+	}
+	if minConfidence < 0 || minConfidence > 1 {
+		return nil, nil, ErrInvalidConfidence
+	}
+	opts, err := normalizeListOptions(opts, repository.SortByCreatedAt, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names, ids, err := s.expandIngredientNames(ctx, []string{ingredient})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	substitutions := []entity.Substitution{}
+	replaced := make(map[string]bool)
+	var targets []string
+	for _, name := range names {
+		for _, sub := range entity.ReplaceableBy(name, minConfidence) {
+			if replaced[sub.Ingredient] {
+				continue
+			}
+			replaced[sub.Ingredient] = true
+			substitutions = append(substitutions, sub)
+			targets = append(targets, sub.Ingredient)
+		}
+	}
+
+	if len(targets) > 0 {
+		targetNames, targetIDs, err := s.expandIngredientNames(ctx, targets)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, targetNames...)
+		ids = append(ids, targetIDs...)
+	}
+
+	match := repository.IngredientMatch{Names: names, CatalogIDs: ids}
+	page, err := s.recipeRepo.FindByIngredient(ctx, match, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return page, substitutions, nil
+}
@@ -0,0 +1,415 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/domain/search"
+	"fork-and-shaker/internal/domain/units"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxSimilarRecipes is how many similar recipes the recommender keeps for
+// each recipe, and so the most one request can ask for
+const MaxSimilarRecipes = 20
+
+// How much each kind of likeness counts toward a similarity score of at
+// most 1
+const (
+	ingredientSimilarityWeight = 0.6
+	glassSimilarityWeight      = 0.1
+	techniqueSimilarityWeight  = 0.1
+	textSimilarityWeight       = 0.2
+)
+
+// loadPageSize is how many recipes Load reads at a time
+const loadPageSize = 500
+
+// Recommender finds recipes similar to a given one. It keeps the features
+// of every recipe in memory together with each recipe's most similar
+// recipes, which Refresh and Remove update as recipes change so lookups
+// never compute anything. Recipes are compared only with recipes of the
+// same type sharing at least one ingredient. Hidden recipes are never
+// recommended. It is safe for concurrent use.
+type Recommender struct {
+	mu       sync.RWMutex
+	recipes  map[primitive.ObjectID]*recipeFeatures
+	byName   map[string]map[primitive.ObjectID]bool
+	similar  map[primitive.ObjectID][]SimilarityScore
+	listedBy map[primitive.ObjectID]map[primitive.ObjectID]bool
+}
+
+// SimilarityScore is how similar a recipe is to another, from 0 to 1
+type SimilarityScore struct {
+	ID    primitive.ObjectID
+	Score float64
+}
+
+// recipeFeatures is what the recommender compares recipes by
+type recipeFeatures struct {
+	id         primitive.ObjectID
+	recipeType entity.RecipeType
+	hidden     bool
+	// ingredients weighs each normalized ingredient name by its share of
+	// the recipe; the weights add up to 1
+	ingredients map[string]float64
+	glass       string
+	technique   entity.Technique
+	// stems holds the distinct word stems of the name and description
+	stems map[string]bool
+}
+
+// NewRecommender creates an empty Recommender
+func NewRecommender() *Recommender {
+	return &Recommender{
+		recipes:  make(map[primitive.ObjectID]*recipeFeatures),
+		byName:   make(map[string]map[primitive.ObjectID]bool),
+		similar:  make(map[primitive.ObjectID][]SimilarityScore),
+		listedBy: make(map[primitive.ObjectID]map[primitive.ObjectID]bool),
+	}
+}
+
+// Load indexes every stored recipe, replacing whatever was indexed before
+func (r *Recommender) Load(ctx context.Context, recipes repository.RecipeRepository) error {
+	var all []*entity.Recipe
+	opts := repository.ListOptions{
+		Limit:         loadPageSize,
+		Sort:          repository.SortByCreatedAt,
+		IncludeHidden: true,
+	}
+	for {
+		page, err := recipes.FindByClassification(ctx, repository.ClassificationFilter{}, opts)
+		if err != nil {
+			return err
+		}
+		all = append(all, page.Recipes...)
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recipes = make(map[primitive.ObjectID]*recipeFeatures, len(all))
+	r.byName = make(map[string]map[primitive.ObjectID]bool)
+	r.similar = make(map[primitive.ObjectID][]SimilarityScore, len(all))
+	r.listedBy = make(map[primitive.ObjectID]map[primitive.ObjectID]bool)
+	for _, recipe := range all {
+		r.add(newRecipeFeatures(recipe))
+	}
+	for _, f := range r.recipes {
+		r.setSimilar(f.id, r.rank(f))
+	}
+	return nil
+}
+
+// Similar returns up to limit of the recipes most similar to the recipe
+// with the given ID, most similar first
+func (r *Recommender) Similar(id primitive.ObjectID, limit int) []SimilarityScore {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	similar := r.similar[id]
+	if len(similar) > limit {
+		similar = similar[:limit]
+	}
+	return append([]SimilarityScore(nil), similar...)
+}
+
+// Refresh indexes a recipe that was created or changed
+func (r *Recommender) Refresh(recipe *entity.Recipe) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.recipes[recipe.ID]; ok {
+		r.unindex(old)
+	}
+	f := newRecipeFeatures(recipe)
+	r.add(f)
+	r.setSimilar(f.id, r.rank(f))
+
+	affected := r.candidates(f)
+	for id := range r.listedBy[f.id] {
+		affected[id] = true
+	}
+	for id := range affected {
+		r.reconsider(r.recipes[id], f)
+	}
+}
+
+// Remove takes a deleted recipe out of the index
+func (r *Recommender) Remove(id primitive.ObjectID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.recipes[id]
+	if !ok {
+		return
+	}
+	r.unindex(f)
+	delete(r.recipes, id)
+	r.setSimilar(id, nil)
+
+	for other := range r.listedBy[id] {
+		if len(r.similar[other]) == MaxSimilarRecipes {
+			// the list is full, so the recipe that takes the freed place
+			// is not known without ranking again
+			r.setSimilar(other, r.rank(r.recipes[other]))
+		} else {
+			r.setSimilar(other, withoutScore(r.similar[other], id))
+		}
+	}
+	delete(r.listedBy, id)
+}
+
+// add stores a recipe's features and indexes it by ingredient
+func (r *Recommender) add(f *recipeFeatures) {
+	r.recipes[f.id] = f
+	for name := range f.ingredients {
+		if r.byName[name] == nil {
+			r.byName[name] = make(map[primitive.ObjectID]bool)
+		}
+		r.byName[name][f.id] = true
+	}
+}
+
+// unindex removes a recipe from the ingredient index
+func (r *Recommender) unindex(f *recipeFeatures) {
+	for name := range f.ingredients {
+		delete(r.byName[name], f.id)
+		if len(r.byName[name]) == 0 {
+			delete(r.byName, name)
+		}
+	}
+}
+
+// candidates returns the other recipes sharing an ingredient with f
+func (r *Recommender) candidates(f *recipeFeatures) map[primitive.ObjectID]bool {
+	ids := make(map[primitive.ObjectID]bool)
+	for name := range f.ingredients {
+		for id := range r.byName[name] {
+			if id != f.id {
+				ids[id] = true
+			}
+		}
+	}
+	return ids
+}
+
+// rank scores every candidate against f and keeps the most similar
+func (r *Recommender) rank(f *recipeFeatures) []SimilarityScore {
+	var scores []SimilarityScore
+	for id := range r.candidates(f) {
+		if score := similarity(f, r.recipes[id]); score > 0 {
+			scores = append(scores, SimilarityScore{ID: id, Score: score})
+		}
+	}
+	sortScores(scores)
+	if len(scores) > MaxSimilarRecipes {
+		scores = scores[:MaxSimilarRecipes]
+	}
+	return scores
+}
+
+// reconsider updates the similar recipes of f now that changed has changed,
+// ranking f again only when changed drops out of a full list
+func (r *Recommender) reconsider(f, changed *recipeFeatures) {
+	list := r.similar[f.id]
+	score := similarity(f, changed)
+	for _, s := range list {
+		if s.ID == changed.id && score < s.Score && len(list) == MaxSimilarRecipes {
+			r.setSimilar(f.id, r.rank(f))
+			return
+		}
+	}
+
+	list = withoutScore(list, changed.id)
+	if score > 0 {
+		list = append(list, SimilarityScore{ID: changed.id, Score: score})
+		sortScores(list)
+		if len(list) > MaxSimilarRecipes {
+			list = list[:MaxSimilarRecipes]
+		}
+	}
+	r.setSimilar(f.id, list)
+}
+
+// setSimilar replaces the similar recipes of id, keeping track of which
+// lists each recipe is in
+func (r *Recommender) setSimilar(id primitive.ObjectID, list []SimilarityScore) {
+	for _, s := range r.similar[id] {
+		delete(r.listedBy[s.ID], id)
+	}
+	if len(list) == 0 {
+		delete(r.similar, id)
+		return
+	}
+	r.similar[id] = list
+	for _, s := range list {
+		if r.listedBy[s.ID] == nil {
+			r.listedBy[s.ID] = make(map[primitive.ObjectID]bool)
+		}
+		r.listedBy[s.ID][id] = true
+	}
+}
+
+// withoutScore returns a copy of list without the entry for id
+func withoutScore(list []SimilarityScore, id primitive.ObjectID) []SimilarityScore {
+	kept := make([]SimilarityScore, 0, len(list))
+	for _, s := range list {
+		if s.ID != id {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
+
+// sortScores orders scores most similar first, with ties by ID so the
+// order is stable
+func sortScores(scores []SimilarityScore) {
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].ID.Hex() < scores[j].ID.Hex()
+	})
+}
+
+// similarity rates how well other stands in for f, from 0 to 1. Hidden
+// recipes and recipes of another type are never similar.
+func similarity(f, other *recipeFeatures) float64 {
+	if other.hidden || other.recipeType != f.recipeType {
+		return 0
+	}
+	ingredients := weightedJaccard(f.ingredients, other.ingredients)
+	if ingredients == 0 {
+		return 0
+	}
+	score := ingredientSimilarityWeight*ingredients + textSimilarityWeight*jaccard(f.stems, other.stems)
+	if f.glass != "" && f.glass == other.glass {
+		score += glassSimilarityWeight
+	}
+	if f.technique != "" && f.technique == other.technique {
+		score += techniqueSimilarityWeight
+	}
+	return score
+}
+
+// weightedJaccard compares two weighted sets: the sum of the smaller weight
+// of each element over the sum of the larger
+func weightedJaccard(a, b map[string]float64) float64 {
+	var minSum, maxSum float64
+	for name, wa := range a {
+		wb := b[name]
+		minSum += min(wa, wb)
+		maxSum += max(wa, wb)
+	}
+	for name, wb := range b {
+		if _, ok := a[name]; !ok {
+			maxSum += wb
+		}
+	}
+	if maxSum == 0 {
+		return 0
+	}
+	return minSum / maxSum
+}
+
+// jaccard compares two sets: the size of their intersection over the size
+// of their union
+func jaccard(a, b map[string]bool) float64 {
+	shared := 0
+	for s := range a {
+		if b[s] {
+			shared++
+		}
+	}
+	union := len(a) + len(b) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+func newRecipeFeatures(recipe *entity.Recipe) *recipeFeatures {
+	f := &recipeFeatures{
+		id:          recipe.ID,
+		recipeType:  recipe.Type,
+		hidden:      recipe.Hidden,
+		ingredients: ingredientProportions(recipe.Ingredients),
+		glass:       strings.ToLower(strings.TrimSpace(recipe.Glass)),
+		technique:   recipe.Technique,
+		stems:       make(map[string]bool),
+	}
+	for _, stem := range search.Stems(recipe.Name + " " + recipe.Description) {
+		f.stems[stem] = true
+	}
+	return f
+}
+
+// ingredientProportions weighs each ingredient of a recipe by its share of
+// the recipe, adding up to 1. Ingredients measured in volume or weight
+// (taking a gram as a millilitre), or in parts, share out their part of
+// the recipe by amount; whichever of the two ways most ingredients are
+// measured in is used. The rest, such as garnishes counted in pieces, each
+// weigh as much as an even split would give them.
+func ingredientProportions(ingredients []entity.Ingredient) map[string]float64 {
+	type portion struct {
+		name   string
+		amount float64
+		pool   units.Dimension
+	}
+	var portions []portion
+	pools := make(map[units.Dimension]int)
+	for _, ing := range ingredients {
+		name := entity.NormalizeIngredientName(ing.Name)
+		if name == "" {
+			continue
+		}
+		p := portion{name: name}
+		if u, ok := units.Lookup(ing.Unit); ok && ing.Amount > 0 {
+			switch u.Dimension {
+			case units.Volume, units.Weight:
+				p.amount, p.pool = ing.Amount*u.Factor, units.Volume
+			case units.Relative:
+				p.amount, p.pool = ing.Amount, units.Relative
+			}
+		}
+		if p.pool != "" {
+			pools[p.pool]++
+		}
+		portions = append(portions, p)
+	}
+	if len(portions) == 0 {
+		return map[string]float64{}
+	}
+
+	measured := units.Volume
+	if pools[units.Relative] > pools[units.Volume] {
+		measured = units.Relative
+	}
+	var total float64
+	for _, p := range portions {
+		if p.pool == measured {
+			total += p.amount
+		}
+	}
+
+	n := float64(len(portions))
+	share := float64(pools[measured]) / n
+	weights := make(map[string]float64, len(portions))
+	for _, p := range portions {
+		if p.pool == measured && total > 0 {
+			weights[p.name] += share * p.amount / total
+		} else {
+			weights[p.name] += 1 / n
+		}
+	}
+	return weights
+}
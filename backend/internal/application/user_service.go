@@ -0,0 +1,159 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrInvalidRole  = errors.New("role must be user, moderator or admin")
+	ErrChangeSelf   = errors.New("admins cannot change their own role or disable their own account")
+)
+
+// UserProfile is the public summary of a user shown next to their recipes.
+// It leaves out the email address and role.
+type UserProfile struct {
+	ID          primitive.ObjectID
+	DisplayName string
+	RecipeCount int64
+}
+
+// UserService handles the business logic for user profiles and account
+// administration
+type UserService struct {
+	userRepo   repository.UserRepository
+	recipeRepo repository.RecipeRepository
+}
+
+// NewUserService creates a new UserService
+func NewUserService(userRepo repository.UserRepository, recipeRepo repository.RecipeRepository) *UserService {
+	return &UserService{
+		userRepo:   userRepo,
+		recipeRepo: recipeRepo,
+	}
+}
+
+// ListUserRecipes retrieves a page of the recipes a user created together
+// with the user's public profile. The recipe count is the total across all
+// pages. Hidden recipes are only listed when viewer, who may be nil, is the
+// user or a moderator.
+func (s *UserService) ListUserRecipes(ctx context.Context, userID primitive.ObjectID, viewer *entity.User,
+	opts repository.ListOptions) (*UserProfile, *repository.RecipePage, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts, err = normalizeListOptions(opts, repository.SortByCreatedAt, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.IncludeHidden = canListHidden(viewer, userID)
+	page, err := s.recipeRepo.FindByCreator(ctx, userID, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile := &UserProfile{
+		ID:          user.ID,
+		DisplayName: user.DisplayName,
+		RecipeCount: page.Total,
+	}
+	return profile, page, nil
+}
+
+// GetUser retrieves a user's full account on behalf of an admin
+func (s *UserService) GetUser(ctx context.Context, id primitive.ObjectID, actor *entity.User) (*entity.User, error) {
+	if err := authorizeUserManagement(actor); err != nil {
+		return nil, err
+	}
+	return s.getUser(ctx, id)
+}
+
+// FindUserByEmail looks up an account by email on behalf of an admin
+func (s *UserService) FindUserByEmail(ctx context.Context, email string, actor *entity.User) (*entity.User, error) {
+	if err := authorizeUserManagement(actor); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// SetRole changes a user's role on behalf of an admin
+func (s *UserService) SetRole(ctx context.Context, id primitive.ObjectID, role entity.Role,
+	actor *entity.User) (*entity.User, error) {
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+	return s.manageUser(ctx, id, actor, func(user *entity.User) {
+		user.Role = role
+	})
+}
+
+// SetDisabled disables or re-enables a user's account on behalf of an
+// admin. A disabled account's existing tokens stop working immediately.
+func (s *UserService) SetDisabled(ctx context.Context, id primitive.ObjectID, disabled bool,
+	actor *entity.User) (*entity.User, error) {
+	return s.manageUser(ctx, id, actor, func(user *entity.User) {
+		user.Disabled = disabled
+	})
+}
+
+// manageUser applies change to another user's account. Admins may not
+// change their own account this way, so the last admin cannot lock
+// everyone out by accident.
+func (s *UserService) manageUser(ctx context.Context, id primitive.ObjectID, actor *entity.User,
+	change func(*entity.User)) (*entity.User, error) {
+	if err := authorizeUserManagement(actor); err != nil {
+		return nil, err
+	}
+	if actor.ID == id {
+		return nil, ErrChangeSelf
+	}
+
+	user, err := s.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	change(user)
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UserService) getUser(ctx context.Context, id primitive.ObjectID) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// authorizeUserManagement returns ErrUnauthorized for anonymous callers and
+// ErrForbidden for anyone who is not an admin
+func authorizeUserManagement(actor *entity.User) error {
+	if actor == nil {
+		return ErrUnauthorized
+	}
+	if !CanManageUsers(actor) {
+		return ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyScope limits what a request authenticated with an API key may do
+type APIKeyScope string
+
+const (
+	// ScopeRead only allows requests that do not change anything
+	ScopeRead APIKeyScope = "read"
+	// ScopeWrite allows everything the key's owner may do
+	ScopeWrite APIKeyScope = "write"
+)
+
+// Valid reports whether s is a known scope
+func (s APIKeyScope) Valid() bool {
+	return s == ScopeRead || s == ScopeWrite
+}
+
+// APIKeyPrefix starts every API key so keys can be told apart from session
+// tokens and spotted when leaked
+const APIKeyPrefix = "fas_"
+
+// APIKey lets a machine client act as the user who created it. Only a hash
+// of the key is stored; Prefix keeps enough of the key for its owner to
+// recognize it. A nil ExpiresAt means the key does not expire.
+type APIKey struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Name       string             `json:"name" bson:"name"`
+	Prefix     string             `json:"prefix" bson:"prefix"`
+	Hash       string             `json:"-" bson:"hash"`
+	Scope      APIKeyScope        `json:"scope" bson:"scope"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
+	LastUsedAt *time.Time         `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewAPIKey creates a new APIKey entity for an already generated key
+func NewAPIKey(userID primitive.ObjectID, name string, scope APIKeyScope, expiresAt *time.Time,
+	prefix, hash string) *APIKey {
+	return &APIKey{
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		Prefix:    prefix,
+		Hash:      hash,
+		Scope:     scope,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Expired reports whether the key has expired at now
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// Allows reports whether the key's scope permits a request with the given
+// HTTP method. Read keys are limited to safe methods.
+func (k *APIKey) Allows(method string) bool {
+	if k.Scope == ScopeWrite {
+		return true
+	}
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// Validate checks the key's name, scope and expiry, returning a
+// *ValidationError that lists every invalid field
+func (k *APIKey) Validate() error {
+	verr := &ValidationError{}
+	verr.required("name", k.Name)
+	verr.maxLength("name", k.Name, MaxShortTextLength)
+	if !k.Scope.Valid() {
+		verr.Add("scope", "must be read or write")
+	}
+	if k.ExpiresAt != nil && !k.ExpiresAt.After(k.CreatedAt) {
+		verr.Add("expires_at", "must be in the future")
+	}
+	return verr.Err()
+}
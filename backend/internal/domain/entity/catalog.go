@@ -0,0 +1,132 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IngredientCategory groups catalog ingredients by what they are
+type IngredientCategory string
+
+const (
+	CategorySpirit  IngredientCategory = "spirit"
+	CategoryLiqueur IngredientCategory = "liqueur"
+	CategoryWine    IngredientCategory = "wine"
+	CategoryBeer    IngredientCategory = "beer"
+	CategoryBitters IngredientCategory = "bitters"
+	CategorySyrup   IngredientCategory = "syrup"
+	CategoryJuice   IngredientCategory = "juice"
+	CategoryMixer   IngredientCategory = "mixer"
+	CategoryDairy   IngredientCategory = "dairy"
+	CategoryProduce IngredientCategory = "produce"
+	CategoryPantry  IngredientCategory = "pantry"
+	CategoryGarnish IngredientCategory = "garnish"
+	CategoryOther   IngredientCategory = "other"
+)
+
+// Valid reports whether c is a known category
+func (c IngredientCategory) Valid() bool {
+	switch c {
+	case CategorySpirit, CategoryLiqueur, CategoryWine, CategoryBeer, CategoryBitters,
+		CategorySyrup, CategoryJuice, CategoryMixer, CategoryDairy, CategoryProduce,
+		CategoryPantry, CategoryGarnish, CategoryOther:
+		return true
+	}
+	return false
+}
+
+// CatalogIngredient is a canonical ingredient that recipe ingredients can
+// link to. Its name and aliases are the spellings recipes use for it
+// ("Lime juice", "fresh lime", "lime"), so searches and makeable queries
+// treat them as the same thing. ABV is in percent and BaseSpirit names the
+// spirit family a spirit belongs to, such as "gin" or "rum".
+type CatalogIngredient struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name       string             `json:"name" bson:"name"`
+	Aliases    []string           `json:"aliases" bson:"aliases"`
+	Category   IngredientCategory `json:"category" bson:"category"`
+	ABV        *float64           `json:"abv,omitempty" bson:"abv,omitempty"`
+	BaseSpirit string             `json:"base_spirit,omitempty" bson:"base_spirit,omitempty"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// NewCatalogIngredient creates a new CatalogIngredient entity
+func NewCatalogIngredient(name string, aliases []string, category IngredientCategory,
+	abv *float64, baseSpirit string) *CatalogIngredient {
+	now := time.Now()
+	return &CatalogIngredient{
+		Name:       strings.TrimSpace(name),
+		Aliases:    cleanAliases(name, aliases),
+		Category:   category,
+		ABV:        abv,
+		BaseSpirit: NormalizeIngredientName(baseSpirit),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Update updates the catalog ingredient's information
+func (c *CatalogIngredient) Update(name string, aliases []string, category IngredientCategory,
+	abv *float64, baseSpirit string) {
+	c.Name = strings.TrimSpace(name)
+	c.Aliases = cleanAliases(name, aliases)
+	c.Category = category
+	c.ABV = abv
+	c.BaseSpirit = NormalizeIngredientName(baseSpirit)
+	c.UpdatedAt = time.Now()
+}
+
+// Validate validates the catalog ingredient data, returning a
+// *ValidationError that lists every invalid field
+func (c *CatalogIngredient) Validate() error {
+	verr := &ValidationError{}
+
+	verr.required("name", c.Name)
+	verr.maxLength("name", c.Name, MaxNameLength)
+	if len(c.Aliases) > MaxAliases {
+		verr.Add("aliases", "must have at most %d aliases", MaxAliases)
+	}
+	for i, alias := range c.Aliases {
+		verr.maxLength(fmt.Sprintf("aliases[%d]", i), alias, MaxNameLength)
+	}
+	if !c.Category.Valid() {
+		verr.Add("category", "unknown category")
+	}
+	if c.ABV != nil && (*c.ABV < 0 || *c.ABV > 100) {
+		verr.Add("abv", "must be between 0 and 100")
+	}
+	verr.maxLength("base_spirit", c.BaseSpirit, MaxShortTextLength)
+
+	return verr.Err()
+}
+
+// Keys returns the normalized name and aliases a recipe ingredient name is
+// matched against. No two catalog ingredients may share a key.
+func (c *CatalogIngredient) Keys() []string {
+	keys := []string{NormalizeIngredientName(c.Name)}
+	for _, alias := range c.Aliases {
+		keys = append(keys, NormalizeIngredientName(alias))
+	}
+	return keys
+}
+
+// cleanAliases trims aliases and drops blanks and duplicates, including
+// repeats of the name itself
+func cleanAliases(name string, aliases []string) []string {
+	seen := map[string]bool{NormalizeIngredientName(name): true}
+	cleaned := []string{}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := NormalizeIngredientName(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, alias)
+	}
+	return cleaned
+}
@@ -0,0 +1,237 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Limits on how a recipe is tagged
+const (
+	MaxTags      = 20
+	MaxTagLength = 50
+)
+
+// CuratedTags are the tags the site suggests and builds its menus from.
+// Authors may use any other tag as well.
+var CuratedTags = []string{
+	"classic", "modern-classic", "tiki", "sour", "highball", "spritz",
+	"low-abv", "non-alcoholic", "aperitif", "digestif", "brunch", "dessert",
+	"party", "holiday", "frozen", "hot",
+}
+
+// NormalizeTag returns the form tags are stored and matched in: lower-case
+// words joined by hyphens, so "Modern Classic" and "modern_classic" are the
+// same tag. Characters other than letters, digits and separators are
+// dropped.
+func NormalizeTag(tag string) string {
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(tag), isTagSeparator) {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsNumber(r) {
+				return r
+			}
+			return -1
+		}, word)
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, "-")
+}
+
+func isTagSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == '-' || r == '_'
+}
+
+// normalizeTags normalizes tags and drops blanks and duplicates, keeping the
+// author's order
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var normalized []string
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// BaseSpirit is the spirit a cocktail is built on
+type BaseSpirit string
+
+const (
+	SpiritGin     BaseSpirit = "gin"
+	SpiritVodka   BaseSpirit = "vodka"
+	SpiritRum     BaseSpirit = "rum"
+	SpiritTequila BaseSpirit = "tequila"
+	SpiritMezcal  BaseSpirit = "mezcal"
+	SpiritWhiskey BaseSpirit = "whiskey"
+	SpiritBrandy  BaseSpirit = "brandy"
+	SpiritPisco   BaseSpirit = "pisco"
+	SpiritCachaca BaseSpirit = "cachaca"
+	SpiritLiqueur BaseSpirit = "liqueur"
+	SpiritWine    BaseSpirit = "wine"
+	SpiritBeer    BaseSpirit = "beer"
+	// SpiritNone is for drinks without alcohol
+	SpiritNone BaseSpirit = "none"
+)
+
+// BaseSpirits lists every base spirit
+var BaseSpirits = []BaseSpirit{
+	SpiritGin, SpiritVodka, SpiritRum, SpiritTequila, SpiritMezcal, SpiritWhiskey, SpiritBrandy,
+	SpiritPisco, SpiritCachaca, SpiritLiqueur, SpiritWine, SpiritBeer, SpiritNone,
+}
+
+// Valid reports whether s is a known base spirit. The empty base spirit is
+// valid and means the recipe does not say.
+func (s BaseSpirit) Valid() bool {
+	if s == "" {
+		return true
+	}
+	for _, spirit := range BaseSpirits {
+		if s == spirit {
+			return true
+		}
+	}
+	return false
+}
+
+// Techniques lists every mixing technique
+var Techniques = []Technique{TechniqueShaken, TechniqueStirred, TechniqueBuilt, TechniqueBlended}
+
+// Season is the time of year a recipe suits best
+type Season string
+
+const (
+	SeasonSpring Season = "spring"
+	SeasonSummer Season = "summer"
+	SeasonAutumn Season = "autumn"
+	SeasonWinter Season = "winter"
+)
+
+// Seasons lists every season
+var Seasons = []Season{SeasonSpring, SeasonSummer, SeasonAutumn, SeasonWinter}
+
+// Valid reports whether s is a known season. The empty season is valid and
+// means the recipe suits any time of year.
+func (s Season) Valid() bool {
+	switch s {
+	case "", SeasonSpring, SeasonSummer, SeasonAutumn, SeasonWinter:
+		return true
+	}
+	return false
+}
+
+// NormalizeSeason lower-cases a season and accepts "fall" for autumn
+func NormalizeSeason(s Season) Season {
+	s = Season(strings.ToLower(strings.TrimSpace(string(s))))
+	if s == "fall" {
+		return SeasonAutumn
+	}
+	return s
+}
+
+// FlavorAxis is one dimension of a flavor profile
+type FlavorAxis string
+
+const (
+	FlavorSweet  FlavorAxis = "sweet"
+	FlavorSour   FlavorAxis = "sour"
+	FlavorBitter FlavorAxis = "bitter"
+	FlavorBoozy  FlavorAxis = "boozy"
+	FlavorSmoky  FlavorAxis = "smoky"
+	FlavorFruity FlavorAxis = "fruity"
+)
+
+// FlavorAxes lists every flavor axis in the order profiles are shown
+var FlavorAxes = []FlavorAxis{FlavorSweet, FlavorSour, FlavorBitter, FlavorBoozy, FlavorSmoky, FlavorFruity}
+
+// Valid reports whether a is a known flavor axis
+func (a FlavorAxis) Valid() bool {
+	for _, axis := range FlavorAxes {
+		if a == axis {
+			return true
+		}
+	}
+	return false
+}
+
+// Flavor intensities run from 0, absent, to MaxFlavorLevel. A recipe is
+// described by an axis, and matches a filter on it, from FlavorPronounced
+// up.
+const (
+	MaxFlavorLevel   = 5
+	FlavorPronounced = 3
+)
+
+// FlavorProfile rates how strongly a recipe tastes along each flavor axis
+type FlavorProfile struct {
+	Sweet  int `json:"sweet" bson:"sweet"`
+	Sour   int `json:"sour" bson:"sour"`
+	Bitter int `json:"bitter" bson:"bitter"`
+	Boozy  int `json:"boozy" bson:"boozy"`
+	Smoky  int `json:"smoky" bson:"smoky"`
+	Fruity int `json:"fruity" bson:"fruity"`
+}
+
+// Level returns the intensity of one axis of the profile
+func (p FlavorProfile) Level(axis FlavorAxis) int {
+	switch axis {
+	case FlavorSweet:
+		return p.Sweet
+	case FlavorSour:
+		return p.Sour
+	case FlavorBitter:
+		return p.Bitter
+	case FlavorBoozy:
+		return p.Boozy
+	case FlavorSmoky:
+		return p.Smoky
+	case FlavorFruity:
+		return p.Fruity
+	}
+	return 0
+}
+
+// Pronounced reports whether a recipe with this profile, which may be nil,
+// is pronounced in axis
+func (p *FlavorProfile) Pronounced(axis FlavorAxis) bool {
+	return p != nil && p.Level(axis) >= FlavorPronounced
+}
+
+// validateClassification records problems with the recipe's tags, base
+// spirit, season and flavor profile
+func (r *Recipe) validateClassification(verr *ValidationError) {
+	if len(r.Tags) > MaxTags {
+		verr.Add("tags", "must have at most %d tags", MaxTags)
+	}
+	for i, tag := range r.Tags {
+		verr.maxLength(fmt.Sprintf("tags[%d]", i), tag, MaxTagLength)
+	}
+	if !r.BaseSpirit.Valid() {
+		verr.Add("base_spirit", "must be one of %s", joinValues(BaseSpirits))
+	} else if r.BaseSpirit != "" && r.Type != RecipeTypeCocktail {
+		verr.Add("base_spirit", "only cocktails have a base spirit")
+	}
+	if !r.Season.Valid() {
+		verr.Add("season", "must be one of %s", joinValues(Seasons))
+	}
+	if r.Flavor != nil {
+		for _, axis := range FlavorAxes {
+			verr.intRange("flavor."+string(axis), r.Flavor.Level(axis), 0, MaxFlavorLevel)
+		}
+	}
+}
+
+// joinValues lists the values of a string enum for an error message
+func joinValues[T ~string](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(v)
+	}
+	return strings.Join(parts, ", ")
+}
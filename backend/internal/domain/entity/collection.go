@@ -0,0 +1,199 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxCollectionEntries bounds how many recipes one collection may hold
+const MaxCollectionEntries = 500
+
+// CollectionEntry is a recipe in a collection with its owner's note about
+// it
+type CollectionEntry struct {
+	RecipeID primitive.ObjectID `json:"recipe_id" bson:"recipe_id"`
+	Note     string             `json:"note,omitempty" bson:"note,omitempty"`
+	AddedAt  time.Time          `json:"added_at" bson:"added_at"`
+}
+
+// Collection is a named, ordered list of recipes a user keeps, such as a
+// menu for an evening. Collections are private to their owner unless
+// shared, in which case anyone with ShareToken can view them. Version is
+// bumped by every change so concurrent saves cannot overwrite each other.
+type Collection struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	OwnerID     primitive.ObjectID `json:"owner_id" bson:"owner_id"`
+	Name        string             `json:"name" bson:"name"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Public      bool               `json:"public" bson:"public"`
+	ShareToken  string             `json:"share_token,omitempty" bson:"share_token,omitempty"`
+	Entries     []CollectionEntry  `json:"entries" bson:"entries"`
+	Version     int64              `json:"version" bson:"version"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// NewCollection creates a new, empty, private Collection entity
+func NewCollection(ownerID primitive.ObjectID, name, description string) *Collection {
+	now := time.Now()
+	return &Collection{
+		OwnerID:     ownerID,
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		Entries:     []CollectionEntry{},
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Update replaces the name and description of the collection
+func (c *Collection) Update(name, description string) {
+	c.Name = strings.TrimSpace(name)
+	c.Description = strings.TrimSpace(description)
+	c.Version++
+	c.UpdatedAt = time.Now()
+}
+
+// IndexOf returns the position of a recipe in the collection, or -1 when it
+// is not in it
+func (c *Collection) IndexOf(recipeID primitive.ObjectID) int {
+	for i, entry := range c.Entries {
+		if entry.RecipeID == recipeID {
+			return i
+		}
+	}
+	return -1
+}
+
+// AddEntry inserts a recipe at position, or appends it when position is out
+// of range. It reports false, changing nothing, when the recipe is already
+// in the collection.
+func (c *Collection) AddEntry(recipeID primitive.ObjectID, note string, position int) bool {
+	if c.IndexOf(recipeID) >= 0 {
+		return false
+	}
+	now := time.Now()
+	entry := CollectionEntry{RecipeID: recipeID, Note: strings.TrimSpace(note), AddedAt: now}
+	if position < 0 || position > len(c.Entries) {
+		position = len(c.Entries)
+	}
+	c.Entries = append(c.Entries, CollectionEntry{})
+	copy(c.Entries[position+1:], c.Entries[position:])
+	c.Entries[position] = entry
+	c.Version++
+	c.UpdatedAt = now
+	return true
+}
+
+// SetNote replaces the note on a recipe in the collection, reporting false
+// when the recipe is not in it
+func (c *Collection) SetNote(recipeID primitive.ObjectID, note string) bool {
+	i := c.IndexOf(recipeID)
+	if i < 0 {
+		return false
+	}
+	c.Entries[i].Note = strings.TrimSpace(note)
+	c.Version++
+	c.UpdatedAt = time.Now()
+	return true
+}
+
+// MoveEntry moves a recipe to position, clamped to the ends of the
+// collection, reporting false when the recipe is not in it
+func (c *Collection) MoveEntry(recipeID primitive.ObjectID, position int) bool {
+	i := c.IndexOf(recipeID)
+	if i < 0 {
+		return false
+	}
+	entry := c.Entries[i]
+	c.Entries = append(c.Entries[:i], c.Entries[i+1:]...)
+	if position < 0 {
+		position = 0
+	}
+	if position > len(c.Entries) {
+		position = len(c.Entries)
+	}
+	c.Entries = append(c.Entries, CollectionEntry{})
+	copy(c.Entries[position+1:], c.Entries[position:])
+	c.Entries[position] = entry
+	c.Version++
+	c.UpdatedAt = time.Now()
+	return true
+}
+
+// RemoveEntry takes a recipe out of the collection, reporting false when it
+// was not in it
+func (c *Collection) RemoveEntry(recipeID primitive.ObjectID) bool {
+	i := c.IndexOf(recipeID)
+	if i < 0 {
+		return false
+	}
+	c.Entries = append(c.Entries[:i], c.Entries[i+1:]...)
+	c.Version++
+	c.UpdatedAt = time.Now()
+	return true
+}
+
+// Reorder puts the entries in the order of recipeIDs, which must name every
+// recipe in the collection exactly once. It reports false, changing
+// nothing, otherwise.
+func (c *Collection) Reorder(recipeIDs []primitive.ObjectID) bool {
+	if len(recipeIDs) != len(c.Entries) {
+		return false
+	}
+	byID := make(map[primitive.ObjectID]CollectionEntry, len(c.Entries))
+	for _, entry := range c.Entries {
+		byID[entry.RecipeID] = entry
+	}
+	entries := make([]CollectionEntry, 0, len(recipeIDs))
+	for _, id := range recipeIDs {
+		entry, ok := byID[id]
+		if !ok {
+			return false
+		}
+		delete(byID, id)
+		entries = append(entries, entry)
+	}
+	c.Entries = entries
+	c.Version++
+	c.UpdatedAt = time.Now()
+	return true
+}
+
+// Share makes the collection viewable by anyone holding token
+func (c *Collection) Share(token string) {
+	c.Public = true
+	c.ShareToken = token
+	c.Version++
+	c.UpdatedAt = time.Now()
+}
+
+// Unshare makes the collection private again. The old share token is
+// discarded so links handed out earlier stop working even if the
+// collection is shared again later.
+func (c *Collection) Unshare() {
+	c.Public = false
+	c.ShareToken = ""
+	c.Version++
+	c.UpdatedAt = time.Now()
+}
+
+// Validate checks the collection's name, description and entries, returning
+// a *ValidationError that lists every invalid field
+func (c *Collection) Validate() error {
+	verr := &ValidationError{}
+	verr.required("name", c.Name)
+	verr.maxLength("name", c.Name, MaxNameLength)
+	verr.maxLength("description", c.Description, MaxDescriptionLength)
+	if len(c.Entries) > MaxCollectionEntries {
+		verr.Add("entries", "must have at most %d recipes", MaxCollectionEntries)
+	}
+	for i, entry := range c.Entries {
+		verr.maxLength(fmt.Sprintf("entries[%d].note", i), entry.Note, MaxNotesLength)
+	}
+	return verr.Err()
+}
@@ -0,0 +1,143 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	"fork-and-shaker/internal/domain/units"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldChange describes one field that differs between two versions of a
+// recipe. Field is a JSON-style path such as "glass", "instructions[1]" or
+// "ingredients[Campari].amount". From is omitted for additions and To for
+// removals.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from,omitempty"`
+	To    interface{} `json:"to,omitempty"`
+}
+
+// DiffRecipes lists the field-level changes that turn from into to.
+// Ingredients are matched by normalized name so reordering them is not
+// reported as a change; instructions are compared step by step.
+func DiffRecipes(from, to *Recipe) []FieldChange {
+	changes := []FieldChange{}
+
+	scalar := func(field string, a, b interface{}) {
+		if a != b {
+			changes = append(changes, FieldChange{Field: field, From: a, To: b})
+		}
+	}
+	scalar("name", from.Name, to.Name)
+	scalar("type", from.Type, to.Type)
+	scalar("description", from.Description, to.Description)
+	scalar("glass", from.Glass, to.Glass)
+	scalar("garnish", from.Garnish, to.Garnish)
+	scalar("technique", from.Technique, to.Technique)
+	scalar("base_spirit", from.BaseSpirit, to.BaseSpirit)
+	scalar("season", from.Season, to.Season)
+	scalar("servings", from.Servings, to.Servings)
+	scalar("prep_minutes", from.PrepMinutes, to.PrepMinutes)
+	scalar("cook_minutes", from.CookMinutes, to.CookMinutes)
+	scalar("yield", from.Yield, to.Yield)
+	if !sameTemperature(from.OvenTemperature, to.OvenTemperature) {
+		changes = append(changes, FieldChange{Field: "oven_temperature", From: from.OvenTemperature, To: to.OvenTemperature})
+	}
+	if strings.Join(from.Equipment, "\n") != strings.Join(to.Equipment, "\n") {
+		changes = append(changes, FieldChange{Field: "equipment", From: from.Equipment, To: to.Equipment})
+	}
+	if strings.Join(from.Tags, "\n") != strings.Join(to.Tags, "\n") {
+		changes = append(changes, FieldChange{Field: "tags", From: from.Tags, To: to.Tags})
+	}
+	if !sameFlavor(from.Flavor, to.Flavor) {
+		changes = append(changes, FieldChange{Field: "flavor", From: from.Flavor, To: to.Flavor})
+	}
+
+	changes = append(changes, diffIngredients(from.Ingredients, to.Ingredients)...)
+
+	for i := 0; i < len(from.Instructions) || i < len(to.Instructions); i++ {
+		field := fmt.Sprintf("instructions[%d]", i)
+		switch {
+		case i >= len(to.Instructions):
+			changes = append(changes, FieldChange{Field: field, From: from.Instructions[i]})
+		case i >= len(from.Instructions):
+			changes = append(changes, FieldChange{Field: field, To: to.Instructions[i]})
+		case from.Instructions[i] != to.Instructions[i]:
+			changes = append(changes, FieldChange{Field: field, From: from.Instructions[i], To: to.Instructions[i]})
+		}
+	}
+
+	return changes
+}
+
+func diffIngredients(from, to []Ingredient) []FieldChange {
+	var changes []FieldChange
+
+	before := make(map[string]Ingredient, len(from))
+	for _, ing := range from {
+		before[NormalizeIngredientName(ing.Name)] = ing
+	}
+	after := make(map[string]bool, len(to))
+
+	for _, ing := range to {
+		key := NormalizeIngredientName(ing.Name)
+		after[key] = true
+
+		old, ok := before[key]
+		if !ok {
+			changes = append(changes, FieldChange{Field: "ingredients[" + ing.Name + "]", To: ing})
+			continue
+		}
+
+		prefix := "ingredients[" + ing.Name + "]."
+		if old.Name != ing.Name {
+			changes = append(changes, FieldChange{Field: prefix + "name", From: old.Name, To: ing.Name})
+		}
+		if old.Amount != ing.Amount {
+			changes = append(changes, FieldChange{Field: prefix + "amount", From: old.Amount, To: ing.Amount})
+		}
+		if old.Unit != ing.Unit {
+			changes = append(changes, FieldChange{Field: prefix + "unit", From: old.Unit, To: ing.Unit})
+		}
+		if old.Notes != ing.Notes {
+			changes = append(changes, FieldChange{Field: prefix + "notes", From: old.Notes, To: ing.Notes})
+		}
+		if old.IsOptional != ing.IsOptional {
+			changes = append(changes, FieldChange{Field: prefix + "is_optional", From: old.IsOptional, To: ing.IsOptional})
+		}
+		if !sameID(old.CatalogID, ing.CatalogID) {
+			changes = append(changes, FieldChange{Field: prefix + "catalog_id", From: old.CatalogID, To: ing.CatalogID})
+		}
+	}
+
+	for _, ing := range from {
+		if !after[NormalizeIngredientName(ing.Name)] {
+			changes = append(changes, FieldChange{Field: "ingredients[" + ing.Name + "]", From: ing})
+		}
+	}
+
+	return changes
+}
+
+func sameID(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameTemperature(a, b *units.Temperature) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameFlavor(a, b *FlavorProfile) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Favorite is a recipe a user has bookmarked
+type Favorite struct {
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	RecipeID  primitive.ObjectID `json:"recipe_id" bson:"recipe_id"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewFavorite creates a new Favorite entity
+func NewFavorite(userID, recipeID primitive.ObjectID) *Favorite {
+	return &Favorite{
+		UserID:    userID,
+		RecipeID:  recipeID,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,215 @@
+package entity
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"fork-and-shaker/internal/domain/units"
+)
+
+// vulgarFractions are the single-character fractions recipes are written
+// with
+var vulgarFractions = map[rune]float64{
+	'½': 1.0 / 2, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 1.0 / 4, '¾': 3.0 / 4,
+	'⅕': 1.0 / 5, '⅖': 2.0 / 5, '⅗': 3.0 / 5, '⅘': 4.0 / 5, '⅙': 1.0 / 6,
+	'⅚': 5.0 / 6, '⅛': 1.0 / 8, '⅜': 3.0 / 8, '⅝': 5.0 / 8, '⅞': 7.0 / 8,
+}
+
+// maxUnitWords is the most words a unit is spelled with, as in "fl. oz."
+// or "ounces weight"
+const maxUnitWords = 2
+
+// ParseIngredientLine reads an ingredient written as free text, such as
+// "1 1/2 oz rye whiskey", "2 dashes Angostura bitters" or "30ml lime juice,
+// freshly squeezed". The amount may be a whole or decimal number, a
+// fraction, a mixed number or a range, of which the lower bound is kept and
+// the range noted. A known unit after it is stored in its canonical
+// spelling and a following "of" is skipped. Text after a comma or in
+// parentheses becomes the notes, and "optional" there marks the ingredient
+// optional. A line without an amount is all name.
+func ParseIngredientLine(line string) Ingredient {
+	var ing Ingredient
+	var notes []string
+
+	text := strings.Join(strings.Fields(line), " ")
+	text, parenthesized := cutParentheses(text)
+	notes = append(notes, parenthesized...)
+	if i := noteComma(text); i >= 0 {
+		notes = append(notes, strings.TrimSpace(text[i+1:]))
+		text = strings.TrimSpace(text[:i])
+	}
+
+	words := strings.Fields(splitAttachedUnit(text))
+	if amount, n, rangeText := parseAmount(words); n > 0 {
+		ing.Amount = amount
+		words = words[n:]
+		if rangeText != "" {
+			notes = append([]string{rangeText}, notes...)
+		}
+		for size := min(maxUnitWords, len(words)-1); size >= 1; size-- {
+			if u, ok := units.Lookup(strings.Join(words[:size], " ")); ok {
+				ing.Unit = u.Symbol
+				words = words[size:]
+				break
+			}
+		}
+		if len(words) > 1 && strings.EqualFold(words[0], "of") {
+			words = words[1:]
+		}
+	}
+	ing.Name = strings.Join(words, " ")
+
+	kept := notes[:0]
+	for _, note := range notes {
+		if strings.EqualFold(note, "optional") {
+			ing.IsOptional = true
+			continue
+		}
+		if note != "" {
+			kept = append(kept, note)
+		}
+	}
+	ing.Notes = strings.Join(kept, "; ")
+	return ing
+}
+
+// cutParentheses removes every parenthesized part of text, returning what
+// was inside each
+func cutParentheses(text string) (string, []string) {
+	var inside []string
+	for {
+		open := strings.IndexByte(text, '(')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(text[open:], ')')
+		if end < 0 {
+			break
+		}
+		inside = append(inside, strings.TrimSpace(text[open+1:open+end]))
+		text = strings.Join(strings.Fields(text[:open]+" "+text[open+end+1:]), " ")
+	}
+	return text, inside
+}
+
+// noteComma returns the index of the comma that starts the notes of an
+// ingredient line, skipping decimal commas such as the one in "1,5 cl", or
+// -1 if there is none
+func noteComma(text string) int {
+	for i := 0; i < len(text); i++ {
+		if text[i] != ',' {
+			continue
+		}
+		if i > 0 && i+1 < len(text) && isDigit(text[i-1]) && isDigit(text[i+1]) {
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// splitAttachedUnit separates a leading amount from a unit written against
+// it, so "30ml gin" reads as "30 ml gin"
+func splitAttachedUnit(text string) string {
+	i := strings.IndexFunc(text, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != ',' && r != '/' && vulgarFractions[r] == 0
+	})
+	if i <= 0 || text[i] == ' ' || text[i] == '-' {
+		return text
+	}
+	end := strings.IndexByte(text[i:], ' ')
+	if end < 0 {
+		end = len(text) - i
+	}
+	if _, ok := units.Lookup(text[i : i+end]); !ok {
+		return text
+	}
+	return text[:i] + " " + text[i:]
+}
+
+// ParseAmount reads a text that is only an amount, in any of the forms
+// ParseIngredientLine accepts, such as "1 1/2" or "¾". A range gives its
+// lower bound.
+func ParseAmount(text string) (float64, bool) {
+	words := strings.Fields(text)
+	amount, n, _ := parseAmount(words)
+	return amount, n > 0 && n == len(words)
+}
+
+// parseAmount reads the amount at the start of words, returning it with how
+// many words it took. A range such as "1-2" or "1 to 2" gives its lower
+// bound and its text.
+func parseAmount(words []string) (float64, int, string) {
+	if len(words) == 0 {
+		return 0, 0, ""
+	}
+	if low, high, ok := strings.Cut(words[0], "-"); ok {
+		lo, okLow := parseNumber(low)
+		_, okHigh := parseNumber(high)
+		if okLow && okHigh {
+			return lo, 1, words[0]
+		}
+	}
+
+	amount, ok := parseNumber(words[0])
+	if !ok {
+		return 0, 0, ""
+	}
+	n := 1
+	// a mixed number such as "1 1/2" or "1 ½"
+	if len(words) > 1 && !strings.ContainsAny(words[0], "/.,") {
+		if frac, ok := parseNumber(words[1]); ok && frac < 1 {
+			amount += frac
+			n = 2
+		}
+	}
+	if len(words) > n+1 && (words[n] == "-" || strings.EqualFold(words[n], "to")) {
+		if _, ok := parseNumber(words[n+1]); ok {
+			return amount, n + 2, strings.Join(words[:n+2], " ")
+		}
+	}
+	return amount, n, ""
+}
+
+// parseNumber reads a whole or decimal number, a fraction such as "3/4", a
+// vulgar fraction or a number followed by one such as "1½". A decimal comma
+// is read as a point.
+func parseNumber(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	runes := []rune(s)
+	if frac, ok := vulgarFractions[runes[len(runes)-1]]; ok {
+		if len(runes) == 1 {
+			return frac, true
+		}
+		whole, err := strconv.Atoi(string(runes[:len(runes)-1]))
+		if err != nil {
+			return 0, false
+		}
+		return float64(whole) + frac, true
+	}
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		n, errN := strconv.Atoi(num)
+		d, errD := strconv.Atoi(den)
+		if errN != nil || errD != nil || d == 0 {
+			return 0, false
+		}
+		return float64(n) / float64(d), true
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) && r != '.' && r != ',' {
+			return 0, false
+		}
+	}
+	v, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseIngredientLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Ingredient
+	}{
+		{"2 oz gin", Ingredient{Name: "gin", Amount: 2, Unit: "oz"}},
+		{"1 1/2 oz rye whiskey", Ingredient{Name: "rye whiskey", Amount: 1.5, Unit: "oz"}},
+		{"1 ½ oz rye whiskey", Ingredient{Name: "rye whiskey", Amount: 1.5, Unit: "oz"}},
+		{"1½ oz rye whiskey", Ingredient{Name: "rye whiskey", Amount: 1.5, Unit: "oz"}},
+		{"¾ oz lemon juice", Ingredient{Name: "lemon juice", Amount: 0.75, Unit: "oz"}},
+		{"3/4 ounce simple syrup", Ingredient{Name: "simple syrup", Amount: 0.75, Unit: "oz"}},
+		{"0.5 oz maraschino", Ingredient{Name: "maraschino", Amount: 0.5, Unit: "oz"}},
+		{"1,5 cl absinthe", Ingredient{Name: "absinthe", Amount: 1.5, Unit: "cl"}},
+		{"30ml lime juice, freshly squeezed",
+			Ingredient{Name: "lime juice", Amount: 30, Unit: "ml", Notes: "freshly squeezed"}},
+		{"1,5cl absinthe", Ingredient{Name: "absinthe", Amount: 1.5, Unit: "cl"}},
+		{"2 dashes Angostura bitters", Ingredient{Name: "Angostura bitters", Amount: 2, Unit: "dash"}},
+		{"2 fl. oz. gin", Ingredient{Name: "gin", Amount: 2, Unit: "oz"}},
+		{"1 cup of sugar", Ingredient{Name: "sugar", Amount: 1, Unit: "cup"}},
+		{"1-2 dashes orange bitters",
+			Ingredient{Name: "orange bitters", Amount: 1, Unit: "dash", Notes: "1-2"}},
+		{"1 to 2 dashes orange bitters",
+			Ingredient{Name: "orange bitters", Amount: 1, Unit: "dash", Notes: "1 to 2"}},
+		{"2 - 3 mint sprigs", Ingredient{Name: "mint sprigs", Amount: 2, Notes: "2 - 3"}},
+		{"1 egg white (optional)", Ingredient{Name: "egg white", Amount: 1, IsOptional: true}},
+		{"1 oz cream (heavy), optional",
+			Ingredient{Name: "cream", Amount: 1, Unit: "oz", Notes: "heavy", IsOptional: true}},
+		{"3 limes", Ingredient{Name: "limes", Amount: 3}},
+		{"salt, to taste", Ingredient{Name: "salt", Notes: "to taste"}},
+		{"Soda water", Ingredient{Name: "Soda water"}},
+		{"  2   oz    gin  ", Ingredient{Name: "gin", Amount: 2, Unit: "oz"}},
+		// A unit with nothing after it is read as the name rather than left empty
+		{"2 oz", Ingredient{Name: "oz", Amount: 2}},
+		{"of", Ingredient{Name: "of"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := ParseIngredientLine(tt.line)
+			if got.Name != tt.want.Name || got.Unit != tt.want.Unit || got.Notes != tt.want.Notes ||
+				got.IsOptional != tt.want.IsOptional || math.Abs(got.Amount-tt.want.Amount) > 1e-9 {
+				t.Errorf("ParseIngredientLine(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   float64
+		wantOK bool
+	}{
+		{"2", 2, true},
+		{"0.25", 0.25, true},
+		{"1,5", 1.5, true},
+		{"3/4", 0.75, true},
+		{"¾", 0.75, true},
+		{"1½", 1.5, true},
+		{"1 1/2", 1.5, true},
+		{"1 ½", 1.5, true},
+		{"1-2", 1, true},
+		{"1 to 2", 1, true},
+		{" 2 ", 2, true},
+		{"", 0, false},
+		{"a splash", 0, false},
+		{"1/0", 0, false},
+		{"x½", 0, false},
+		{"2 oz", 2, false},
+		{"1 3/2", 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := ParseAmount(tt.text)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ParseAmount(%q) = %v, %v, want %v, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// keywordTable matches ingredient names against the keys of a lookup table.
+// Keys are normalized names or name fragments; a name matches a key when it
+// equals it or contains all of its words in order, and the most specific
+// key wins ("ginger beer" over "beer").
+type keywordTable struct {
+	exact map[string]bool
+	// keys holds the tokenized keys, most words first
+	keys [][]string
+}
+
+func newKeywordTable[V any](table map[string]V) keywordTable {
+	t := keywordTable{exact: make(map[string]bool, len(table))}
+	for name := range table {
+		t.exact[name] = true
+		t.keys = append(t.keys, nameTokens(name))
+	}
+	sort.Slice(t.keys, func(i, j int) bool {
+		if len(t.keys[i]) != len(t.keys[j]) {
+			return len(t.keys[i]) > len(t.keys[j])
+		}
+		a, b := strings.Join(t.keys[i], " "), strings.Join(t.keys[j], " ")
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return t
+}
+
+// match returns the table key that best describes name
+func (t keywordTable) match(name string) (string, bool) {
+	normalized := NormalizeIngredientName(name)
+	if t.exact[normalized] {
+		return normalized, true
+	}
+
+	words := nameTokens(normalized)
+	for _, key := range t.keys {
+		if containsWords(words, key) {
+			return strings.Join(key, " "), true
+		}
+	}
+	return "", false
+}
+
+// nameTokens splits a name into lower-cased words, keeping apostrophes and
+// hyphens so keys such as "peychaud's" and "st-germain" survive
+func nameTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '\'' && r != '-'
+	})
+}
+
+// containsWords reports whether keyword appears as a run of whole words in
+// words
+func containsWords(words, keyword []string) bool {
+	for i := 0; i+len(keyword) <= len(words); i++ {
+		match := true
+		for j, k := range keyword {
+			if words[i+j] != k {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,12 @@
 package entity
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
+	"fork-and-shaker/internal/domain/units"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -14,66 +18,435 @@ const (
 	RecipeTypeFood     RecipeType = "food"
 )
 
-// Ingredient represents a single ingredient in a recipe
+// Valid reports whether t is a known recipe type
+func (t RecipeType) Valid() bool {
+	switch t {
+	case RecipeTypeCocktail, RecipeTypeFood:
+		return true
+	}
+	return false
+}
+
+// Oven temperatures outside this range, in degrees Celsius, are taken to be
+// typing mistakes
+const (
+	minOvenCelsius = 50
+	maxOvenCelsius = 300
+)
+
+// Ingredient represents a single ingredient in a recipe. Name stays free
+// text; CatalogID optionally links it to a canonical CatalogIngredient.
 type Ingredient struct {
-	Name       string  `json:"name" bson:"name"`
-	Amount     float64 `json:"amount" bson:"amount"`
-	Unit       string  `json:"unit" bson:"unit"`
-	Notes      string  `json:"notes,omitempty" bson:"notes,omitempty"`
-	IsOptional bool    `json:"is_optional" bson:"is_optional"`
+	Name       string              `json:"name" bson:"name"`
+	Amount     float64             `json:"amount" bson:"amount"`
+	Unit       string              `json:"unit" bson:"unit"`
+	Notes      string              `json:"notes,omitempty" bson:"notes,omitempty"`
+	IsOptional bool                `json:"is_optional" bson:"is_optional"`
+	CatalogID  *primitive.ObjectID `json:"catalog_id,omitempty" bson:"catalog_id,omitempty"`
+}
+
+// NormalizeIngredientName returns the form of an ingredient name used to
+// compare ingredients across recipes: trimmed and lower-cased
+func NormalizeIngredientName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
 }
 
-// Recipe represents a recipe entity in our domain
+// Recipe represents a recipe entity in our domain. ABV is the estimated
+// final strength of a cocktail, derived from its ingredients and technique
+// whenever the recipe changes. Servings is how many portions the ingredient
+// amounts make, where zero means one; the remaining timing, yield and oven
+// fields describe food recipes. Tags, Flavor, BaseSpirit and Season
+// classify the recipe for browsing; tags are stored normalized. CreatorID
+// is the user who created the recipe; recipes from before accounts existed
+// have none. Featured and Hidden are set by moderators. Rating is the
+// average of the recipe's reviews and RatingCount how many there are; both
+// are kept up to date as reviews change.
 type Recipe struct {
-	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Name         string             `json:"name" bson:"name"`
-	Type         RecipeType         `json:"type" bson:"type"`
-	Description  string             `json:"description" bson:"description"`
-	Ingredients  []Ingredient       `json:"ingredients" bson:"ingredients"`
-	Instructions []string           `json:"instructions" bson:"instructions"`
-	Glass        string             `json:"glass,omitempty" bson:"glass,omitempty"`
-	Garnish      string             `json:"garnish,omitempty" bson:"garnish,omitempty"`
-	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+	ID              primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Name            string              `json:"name" bson:"name"`
+	Type            RecipeType          `json:"type" bson:"type"`
+	Description     string              `json:"description" bson:"description"`
+	Ingredients     []Ingredient        `json:"ingredients" bson:"ingredients"`
+	Instructions    []string            `json:"instructions" bson:"instructions"`
+	Glass           string              `json:"glass,omitempty" bson:"glass,omitempty"`
+	Garnish         string              `json:"garnish,omitempty" bson:"garnish,omitempty"`
+	Technique       Technique           `json:"technique,omitempty" bson:"technique,omitempty"`
+	Tags            []string            `json:"tags,omitempty" bson:"tags,omitempty"`
+	Flavor          *FlavorProfile      `json:"flavor,omitempty" bson:"flavor,omitempty"`
+	BaseSpirit      BaseSpirit          `json:"base_spirit,omitempty" bson:"base_spirit,omitempty"`
+	Season          Season              `json:"season,omitempty" bson:"season,omitempty"`
+	ABV             *float64            `json:"abv,omitempty" bson:"abv,omitempty"`
+	Servings        int                 `json:"servings,omitempty" bson:"servings,omitempty"`
+	PrepMinutes     int                 `json:"prep_minutes,omitempty" bson:"prep_minutes,omitempty"`
+	CookMinutes     int                 `json:"cook_minutes,omitempty" bson:"cook_minutes,omitempty"`
+	Yield           string              `json:"yield,omitempty" bson:"yield,omitempty"`
+	Equipment       []string            `json:"equipment,omitempty" bson:"equipment,omitempty"`
+	OvenTemperature *units.Temperature  `json:"oven_temperature,omitempty" bson:"oven_temperature,omitempty"`
+	CreatorID       *primitive.ObjectID `json:"creator_id,omitempty" bson:"creator_id,omitempty"`
+	ForkedFrom      *primitive.ObjectID `json:"forked_from,omitempty" bson:"forked_from,omitempty"`
+	Featured        bool                `json:"featured" bson:"featured"`
+	Hidden          bool                `json:"hidden" bson:"hidden"`
+	Rating          float64             `json:"rating" bson:"rating"`
+	RatingCount     int                 `json:"rating_count" bson:"rating_count"`
+	Version         int64               `json:"version" bson:"version"`
+	CreatedAt       time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// RecipeDetails holds the fields of a recipe its author writes, as opposed
+// to the identity, lineage and bookkeeping the server maintains
+type RecipeDetails struct {
+	Name            string
+	Type            RecipeType
+	Description     string
+	Ingredients     []Ingredient
+	Instructions    []string
+	Glass           string
+	Garnish         string
+	Technique       Technique
+	Tags            []string
+	Flavor          *FlavorProfile
+	BaseSpirit      BaseSpirit
+	Season          Season
+	Servings        int
+	PrepMinutes     int
+	CookMinutes     int
+	Yield           string
+	Equipment       []string
+	OvenTemperature *units.Temperature
 }
 
 // NewRecipe creates a new Recipe entity
-func NewRecipe(name string, recipeType RecipeType, description string, ingredients []Ingredient,
-	instructions []string, glass, garnish string) *Recipe {
+func NewRecipe(details RecipeDetails) *Recipe {
 	now := time.Now()
-	return &Recipe{
-		Name:         name,
-		Type:         recipeType,
-		Description:  description,
-		Ingredients:  ingredients,
-		Instructions: instructions,
-		Glass:        glass,
-		Garnish:      garnish,
-		CreatedAt:    now,
-		UpdatedAt:    now,
-	}
-}
-
-// Update updates the recipe's information
-func (r *Recipe) Update(name, description string, ingredients []Ingredient,
-	instructions []string, glass, garnish string) {
-	r.Name = name
-	r.Description = description
-	r.Ingredients = ingredients
-	r.Instructions = instructions
-	r.Glass = glass
-	r.Garnish = garnish
+	recipe := &Recipe{
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	recipe.apply(details)
+	return recipe
+}
+
+// Details returns a deep copy of the recipe's authored fields
+func (r *Recipe) Details() RecipeDetails {
+	details := RecipeDetails{
+		Name:         r.Name,
+		Type:         r.Type,
+		Description:  r.Description,
+		Ingredients:  CloneIngredients(r.Ingredients),
+		Instructions: append([]string(nil), r.Instructions...),
+		Glass:        r.Glass,
+		Garnish:      r.Garnish,
+		Technique:    r.Technique,
+		Tags:         append([]string(nil), r.Tags...),
+		BaseSpirit:   r.BaseSpirit,
+		Season:       r.Season,
+		Servings:     r.Servings,
+		PrepMinutes:  r.PrepMinutes,
+		CookMinutes:  r.CookMinutes,
+		Yield:        r.Yield,
+		Equipment:    append([]string(nil), r.Equipment...),
+	}
+	if r.Flavor != nil {
+		flavor := *r.Flavor
+		details.Flavor = &flavor
+	}
+	if r.OvenTemperature != nil {
+		oven := *r.OvenTemperature
+		details.OvenTemperature = &oven
+	}
+	return details
+}
+
+// Fork creates a new, unsaved copy of the recipe that records the recipe it
+// was forked from. An empty name keeps the original name.
+func (r *Recipe) Fork(name string) *Recipe {
+	details := r.Details()
+	if name != "" {
+		details.Name = name
+	}
+	fork := NewRecipe(details)
+	parent := r.ID
+	fork.ForkedFrom = &parent
+	return fork
+}
+
+// SetFeatured marks the recipe as featured or not. Moderation changes what
+// clients see, so it bumps the version, but it is not an edit by the author
+// and leaves UpdatedAt alone.
+func (r *Recipe) SetFeatured(featured bool) {
+	r.Featured = featured
+	r.Version++
+}
+
+// CatalogChanged bumps the version of a recipe whose catalog links or
+// estimated ABV changed because the catalog did. Like moderation it is not
+// an edit by the author and leaves UpdatedAt alone.
+func (r *Recipe) CatalogChanged() {
+	r.Version++
+}
+
+// SetHidden hides the recipe from listings and from everyone but its
+// creator and moderators, or makes it visible again. Like SetFeatured it
+// bumps the version but not UpdatedAt.
+func (r *Recipe) SetHidden(hidden bool) {
+	r.Hidden = hidden
+	r.Version++
+}
+
+// Update updates the recipe's information and bumps its version. An empty
+// type keeps the recipe's current type.
+func (r *Recipe) Update(details RecipeDetails) {
+	if details.Type == "" {
+		details.Type = r.Type
+	}
+	r.apply(details)
+	r.Version++
 	r.UpdatedAt = time.Now()
 }
 
-// Validate validates the recipe data
-func (r *Recipe) Validate() bool {
-	if r.Name == "" || len(r.Ingredients) == 0 || len(r.Instructions) == 0 {
-		return false
+// apply copies details onto the recipe, normalizing units, equipment, tags,
+// the classification enums and the oven temperature scale and refreshing
+// the derived ABV
+func (r *Recipe) apply(details RecipeDetails) {
+	r.Name = details.Name
+	r.Type = details.Type
+	r.Description = details.Description
+	r.Ingredients = normalizeIngredients(details.Ingredients)
+	r.Instructions = details.Instructions
+	r.Glass = details.Glass
+	r.Garnish = details.Garnish
+	r.Technique = details.Technique
+	r.Tags = normalizeTags(details.Tags)
+	r.Flavor = nil
+	if details.Flavor != nil {
+		flavor := *details.Flavor
+		r.Flavor = &flavor
+	}
+	r.BaseSpirit = BaseSpirit(strings.ToLower(strings.TrimSpace(string(details.BaseSpirit))))
+	r.Season = NormalizeSeason(details.Season)
+	r.Servings = details.Servings
+	r.PrepMinutes = details.PrepMinutes
+	r.CookMinutes = details.CookMinutes
+	r.Yield = strings.TrimSpace(details.Yield)
+	r.Equipment = cleanEquipment(details.Equipment)
+	r.OvenTemperature = nil
+	if oven := details.OvenTemperature; oven != nil {
+		normalized := *oven
+		if scale, ok := units.ParseTemperatureScale(string(oven.Scale)); ok {
+			normalized.Scale = scale
+		}
+		r.OvenTemperature = &normalized
+	}
+	r.RefreshABV(DefaultABV)
+}
+
+// BaseServings is the number of servings the ingredient amounts make
+func (r *Recipe) BaseServings() int {
+	if r.Servings < 1 {
+		return 1
+	}
+	return r.Servings
+}
+
+// ConvertUnits rewrites ingredient amounts and the oven temperature in the
+// given measurement system. It is meant for presentation only and does not
+// bump the version.
+func (r *Recipe) ConvertUnits(system units.System) {
+	for i, ing := range r.Ingredients {
+		r.Ingredients[i].Amount, r.Ingredients[i].Unit = units.ToSystem(ing.Amount, ing.Unit, system)
+	}
+	if r.OvenTemperature != nil {
+		oven := r.OvenTemperature.ToSystem(system)
+		r.OvenTemperature = &oven
+	}
+}
+
+// Scale multiplies every ingredient amount by factor, moving amounts into
+// larger units as they grow. Amounts are given in system, or in each
+// ingredient's own system when system is empty; bar measures that become too
+// large to count out use the recipe's preferred system instead.
+func (r *Recipe) Scale(factor float64, system units.System) {
+	barSystem := system
+	if barSystem == "" {
+		barSystem = r.PreferredSystem()
+	}
+	for i, ing := range r.Ingredients {
+		target := system
+		if u, ok := units.Lookup(ing.Unit); ok && u.System == units.Bar {
+			target = barSystem
+		}
+		r.Ingredients[i].Amount, r.Ingredients[i].Unit = units.Scale(ing.Amount, ing.Unit, factor, target)
 	}
+}
 
-	// For cocktails, ensure we have at least one ingredient with an amount
-	if r.Type == RecipeTypeCocktail {
+// PreferredSystem guesses the measurement system the recipe is written in
+// from the units of its ingredients, favouring imperial on a tie
+func (r *Recipe) PreferredSystem() units.System {
+	metric, imperial := 0, 0
+	for _, ing := range r.Ingredients {
+		u, ok := units.Lookup(ing.Unit)
+		if !ok {
+			continue
+		}
+		switch u.System {
+		case units.Metric:
+			metric++
+		case units.Imperial:
+			imperial++
+		}
+	}
+	if metric > imperial {
+		return units.Metric
+	}
+	return units.Imperial
+}
+
+// LiquidVolume returns the combined volume in millilitres of the recipe's
+// non-optional ingredients that are measured by volume
+func (r *Recipe) LiquidVolume() float64 {
+	var ml float64
+	for _, ing := range r.Ingredients {
+		if ing.IsOptional {
+			continue
+		}
+		if u, ok := units.Lookup(ing.Unit); ok && u.Dimension == units.Volume {
+			ml += ing.Amount * u.Factor
+		}
+	}
+	return ml
+}
+
+// CloneIngredients returns a deep copy of ingredients
+func CloneIngredients(ingredients []Ingredient) []Ingredient {
+	if ingredients == nil {
+		return nil
+	}
+	cloned := make([]Ingredient, len(ingredients))
+	for i, ing := range ingredients {
+		if ing.CatalogID != nil {
+			id := *ing.CatalogID
+			ing.CatalogID = &id
+		}
+		cloned[i] = ing
+	}
+	return cloned
+}
+
+// normalizeIngredients returns a copy of ingredients with every unit in its
+// canonical spelling
+func normalizeIngredients(ingredients []Ingredient) []Ingredient {
+	if ingredients == nil {
+		return nil
+	}
+	normalized := make([]Ingredient, len(ingredients))
+	for i, ing := range ingredients {
+		ing.Unit = units.Normalize(ing.Unit)
+		normalized[i] = ing
+	}
+	return normalized
+}
+
+// cleanEquipment trims equipment names and drops blanks and duplicates
+func cleanEquipment(equipment []string) []string {
+	seen := make(map[string]bool, len(equipment))
+	var cleaned []string
+	for _, item := range equipment {
+		item = strings.TrimSpace(item)
+		key := strings.ToLower(item)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, item)
+	}
+	return cleaned
+}
+
+// MissingIngredients returns the names of the non-optional ingredients whose
+// normalized name is not in available and that do not link to one of the
+// available catalog ingredients
+func (r *Recipe) MissingIngredients(available map[string]bool, availableIDs map[primitive.ObjectID]bool) []string {
+	missing := []string{}
+	for _, ing := range r.Ingredients {
+		if ing.IsOptional || available[NormalizeIngredientName(ing.Name)] {
+			continue
+		}
+		if ing.CatalogID != nil && availableIDs[*ing.CatalogID] {
+			continue
+		}
+		missing = append(missing, ing.Name)
+	}
+	return missing
+}
+
+// Validate validates the recipe data, returning a *ValidationError that
+// lists every invalid field
+func (r *Recipe) Validate() error {
+	verr := &ValidationError{}
+
+	verr.required("name", r.Name)
+	verr.maxLength("name", r.Name, MaxNameLength)
+	if !r.Type.Valid() {
+		verr.Add("type", "must be cocktail or food")
+	}
+	verr.maxLength("description", r.Description, MaxDescriptionLength)
+	verr.maxLength("glass", r.Glass, MaxShortTextLength)
+	verr.maxLength("garnish", r.Garnish, MaxShortTextLength)
+	if !r.Technique.Valid() {
+		verr.Add("technique", "must be shaken, stirred, built or blended")
+	}
+	r.validateClassification(verr)
+
+	switch {
+	case len(r.Ingredients) == 0:
+		verr.Add("ingredients", "at least one ingredient is required")
+	case len(r.Ingredients) > MaxIngredients:
+		verr.Add("ingredients", "must have at most %d ingredients", MaxIngredients)
+	}
+	for i, ing := range r.Ingredients {
+		field := fmt.Sprintf("ingredients[%d]", i)
+		verr.required(field+".name", ing.Name)
+		verr.maxLength(field+".name", ing.Name, MaxNameLength)
+		if ing.Amount < 0 || ing.Amount > MaxAmount {
+			verr.Add(field+".amount", "must be between 0 and %d", MaxAmount)
+		}
+		// Food can count whole items ("2 eggs") without a unit; a drink
+		// measure always has one
+		if r.Type == RecipeTypeCocktail && ing.Amount > 0 {
+			verr.required(field+".unit", ing.Unit)
+		}
+		verr.maxLength(field+".unit", ing.Unit, MaxShortTextLength)
+		verr.maxLength(field+".notes", ing.Notes, MaxNotesLength)
+	}
+
+	switch {
+	case len(r.Instructions) == 0:
+		verr.Add("instructions", "at least one instruction is required")
+	case len(r.Instructions) > MaxInstructions:
+		verr.Add("instructions", "must have at most %d steps", MaxInstructions)
+	}
+	for i, step := range r.Instructions {
+		field := fmt.Sprintf("instructions[%d]", i)
+		verr.required(field, step)
+		verr.maxLength(field, step, MaxInstructionLength)
+	}
+
+	verr.intRange("servings", r.Servings, 0, MaxRecipeServings)
+	verr.intRange("prep_minutes", r.PrepMinutes, 0, MaxMinutes)
+	verr.intRange("cook_minutes", r.CookMinutes, 0, MaxMinutes)
+	verr.maxLength("yield", r.Yield, MaxShortTextLength)
+	if len(r.Equipment) > MaxEquipment {
+		verr.Add("equipment", "must list at most %d items", MaxEquipment)
+	}
+	for i, item := range r.Equipment {
+		verr.maxLength(fmt.Sprintf("equipment[%d]", i), item, MaxShortTextLength)
+	}
+
+	switch r.Type {
+	case RecipeTypeCocktail:
+		// Ensure we have at least one ingredient with an amount
 		hasValidIngredient := false
 		for _, ing := range r.Ingredients {
 			if ing.Name != "" && ing.Amount > 0 && ing.Unit != "" {
@@ -81,10 +454,34 @@ func (r *Recipe) Validate() bool {
 				break
 			}
 		}
-		if !hasValidIngredient {
-			return false
+		if len(r.Ingredients) > 0 && !hasValidIngredient {
+			verr.Add("ingredients", "at least one ingredient needs an amount and unit")
+		}
+		// Cocktails are not cooked
+		if r.CookMinutes != 0 {
+			verr.Add("cook_minutes", "only food recipes have a cook time")
+		}
+		if r.OvenTemperature != nil {
+			verr.Add("oven_temperature", "only food recipes have an oven temperature")
+		}
+
+	case RecipeTypeFood:
+		// Food needs to say how many it feeds, and the mixing technique
+		// only applies to drinks
+		if r.Servings < 1 {
+			verr.Add("servings", "required for food recipes")
+		}
+		if r.Technique != "" {
+			verr.Add("technique", "only cocktails have a mixing technique")
+		}
+		if oven := r.OvenTemperature; oven != nil {
+			if oven.Scale != units.Celsius && oven.Scale != units.Fahrenheit {
+				verr.Add("oven_temperature.scale", "must be C or F")
+			} else if c := oven.Celsius(); c < minOvenCelsius || c > maxOvenCelsius {
+				verr.Add("oven_temperature.degrees", "must be between %d and %d °C", minOvenCelsius, maxOvenCelsius)
+			}
 		}
 	}
 
-	return true
+	return verr.Err()
 }
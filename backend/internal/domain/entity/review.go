@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Review bounds. Ratings are whole stars.
+const (
+	MinRating       = 1
+	MaxRating       = 5
+	MaxReviewLength = 5000
+)
+
+// Review is one user's rating of a recipe, optionally with a written
+// review. A user has at most one review per recipe and edits it in place.
+type Review struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	RecipeID  primitive.ObjectID `json:"recipe_id" bson:"recipe_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Rating    int                `json:"rating" bson:"rating"`
+	Text      string             `json:"text,omitempty" bson:"text,omitempty"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// NewReview creates a new Review entity
+func NewReview(recipeID, userID primitive.ObjectID, rating int, text string) *Review {
+	now := time.Now()
+	return &Review{
+		RecipeID:  recipeID,
+		UserID:    userID,
+		Rating:    rating,
+		Text:      strings.TrimSpace(text),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Update replaces the rating and text of the review
+func (r *Review) Update(rating int, text string) {
+	r.Rating = rating
+	r.Text = strings.TrimSpace(text)
+	r.UpdatedAt = time.Now()
+}
+
+// Validate checks the rating and text, returning a *ValidationError that
+// lists every invalid field
+func (r *Review) Validate() error {
+	verr := &ValidationError{}
+	verr.intRange("rating", r.Rating, MinRating, MaxRating)
+	verr.maxLength("text", r.Text, MaxReviewLength)
+	return verr.Err()
+}
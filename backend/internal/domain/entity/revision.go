@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Revision is an immutable snapshot of a recipe as it stood after a create,
+// update or restore. A revision is numbered by the version of the recipe it
+// captures, so numbers follow the order the recipe was saved in but skip
+// versions that changed only its rating or moderation. RestoredFrom is set
+// when the revision was produced by restoring an older one.
+type Revision struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	RecipeID     primitive.ObjectID `json:"recipe_id" bson:"recipe_id"`
+	Number       int                `json:"number" bson:"number"`
+	Recipe       Recipe             `json:"recipe" bson:"recipe"`
+	EditedBy     string             `json:"edited_by,omitempty" bson:"edited_by,omitempty"`
+	RestoredFrom int                `json:"restored_from,omitempty" bson:"restored_from,omitempty"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// NewRevision snapshots the current state of a recipe
+func NewRevision(recipe *Recipe, number int, editedBy string, restoredFrom int) *Revision {
+	snapshot := *recipe
+	snapshot.Ingredients = append([]Ingredient(nil), recipe.Ingredients...)
+	snapshot.Instructions = append([]string(nil), recipe.Instructions...)
+
+	return &Revision{
+		RecipeID:     recipe.ID,
+		Number:       number,
+		Recipe:       snapshot,
+		EditedBy:     editedBy,
+		RestoredFrom: restoredFrom,
+		CreatedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,208 @@
+package entity
+
+import (
+	"math"
+
+	"fork-and-shaker/internal/domain/units"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Technique is how a cocktail is mixed. It decides how much melting ice
+// dilutes the drink.
+type Technique string
+
+const (
+	TechniqueShaken  Technique = "shaken"
+	TechniqueStirred Technique = "stirred"
+	TechniqueBuilt   Technique = "built"
+	TechniqueBlended Technique = "blended"
+)
+
+// Valid reports whether t is a known technique. The empty technique is
+// valid and means the recipe does not say.
+func (t Technique) Valid() bool {
+	switch t {
+	case "", TechniqueShaken, TechniqueStirred, TechniqueBuilt, TechniqueBlended:
+		return true
+	}
+	return false
+}
+
+// Dilution estimates the water melting ice adds to a drink, as a fraction of
+// its undiluted volume. abv is the undiluted strength as a fraction. Shaken
+// and stirred use the regressions from Dave Arnold's Liquid Intelligence,
+// stronger drinks melt more ice; built and blended drinks use typical
+// values. A recipe without a technique is assumed not to be diluted.
+func (t Technique) Dilution(abv float64) float64 {
+	switch t {
+	case TechniqueShaken:
+		return 1.567*abv*abv + 1.742*abv + 0.203
+	case TechniqueStirred:
+		return -1.21*abv*abv + 1.246*abv + 0.145
+	case TechniqueBuilt:
+		return 0.24
+	case TechniqueBlended:
+		return 0.5
+	}
+	return 0
+}
+
+// mlEthanolPerStandardDrink is the volume of 14 g of ethanol, the US
+// definition of a standard drink
+const mlEthanolPerStandardDrink = 14 / 0.789
+
+// Strength is the estimated alcohol content of a finished drink
+type Strength struct {
+	// ABV is the final alcohol by volume, in percent
+	ABV float64 `json:"abv"`
+	// VolumeML is the volume of the finished drink, including dilution
+	VolumeML float64 `json:"volume_ml"`
+	// DilutionPercent is the water added by ice relative to the undiluted
+	// volume
+	DilutionPercent float64 `json:"dilution_percent"`
+	StandardDrinks  float64 `json:"standard_drinks"`
+	// UnknownIngredients lists liquid ingredients whose strength is not
+	// known; they are counted as non-alcoholic
+	UnknownIngredients []string `json:"unknown_ingredients"`
+}
+
+// ABVLookup reports the strength of a recipe ingredient in percent ABV
+type ABVLookup func(Ingredient) (float64, bool)
+
+// DefaultABV looks an ingredient up by name in the built-in table of
+// typical strengths
+func DefaultABV(ing Ingredient) (float64, bool) {
+	return KnownABV(ing.Name)
+}
+
+// CatalogABV looks ingredient strengths up in the catalog entry they link
+// to, falling back to the built-in table
+func CatalogABV(entries map[primitive.ObjectID]*CatalogIngredient) ABVLookup {
+	return func(ing Ingredient) (float64, bool) {
+		if ing.CatalogID != nil {
+			if entry := entries[*ing.CatalogID]; entry != nil && entry.ABV != nil {
+				return *entry.ABV, true
+			}
+		}
+		return DefaultABV(ing)
+	}
+}
+
+// Strength estimates the alcohol content of the drink from the volume and
+// ABV of its non-optional liquid ingredients and the dilution its technique
+// adds. ok is false when the recipe has no liquid ingredients to base an
+// estimate on.
+func (r *Recipe) Strength(abvOf ABVLookup) (strength Strength, ok bool) {
+	strength.UnknownIngredients = []string{}
+
+	var volume, ethanol float64
+	for _, ing := range r.Ingredients {
+		if ing.IsOptional {
+			continue
+		}
+		u, found := units.Lookup(ing.Unit)
+		if !found || u.Dimension != units.Volume {
+			continue
+		}
+		ml := ing.Amount * u.Factor
+		abv, known := abvOf(ing)
+		if !known {
+			strength.UnknownIngredients = append(strength.UnknownIngredients, ing.Name)
+		}
+		volume += ml
+		ethanol += ml * abv / 100
+	}
+	if volume <= 0 {
+		return strength, false
+	}
+
+	dilution := r.Technique.Dilution(ethanol / volume)
+	final := volume * (1 + dilution)
+
+	strength.ABV = math.Round(ethanol/final*1000) / 10
+	strength.VolumeML = math.Round(final*10) / 10
+	strength.DilutionPercent = math.Round(dilution * 100)
+	strength.StandardDrinks = math.Round(ethanol/mlEthanolPerStandardDrink*10) / 10
+	return strength, true
+}
+
+// RefreshABV stores the estimated final ABV of a cocktail so listings can be
+// filtered by strength. Other recipes and cocktails without liquid
+// ingredients have no ABV.
+func (r *Recipe) RefreshABV(abvOf ABVLookup) {
+	r.ABV = nil
+	if r.Type != RecipeTypeCocktail {
+		return
+	}
+	if strength, ok := r.Strength(abvOf); ok {
+		r.ABV = &strength.ABV
+	}
+}
+
+// abvCatalog maps ingredient names and name fragments to typical bottled
+// strengths in percent ABV. Non-alcoholic staples are listed so they are not
+// reported as unknown.
+var abvCatalog = map[string]float64{
+	// Spirits
+	"vodka": 40, "gin": 40, "london dry gin": 43, "old tom gin": 40, "genever": 35,
+	"navy strength gin": 57, "sloe gin": 26,
+	"rum": 40, "white rum": 40, "light rum": 40, "dark rum": 40, "gold rum": 40,
+	"aged rum": 40, "spiced rum": 35, "overproof rum": 63, "demerara rum": 40,
+	"rhum agricole": 50, "cachaca": 40, "cachaça": 40, "batavia arrack": 50,
+	"tequila": 40, "blanco tequila": 40, "reposado tequila": 40, "mezcal": 45,
+	"whiskey": 40, "whisky": 40, "bourbon": 45, "rye": 45, "rye whiskey": 45,
+	"scotch": 40, "islay scotch": 43, "irish whiskey": 40, "japanese whisky": 43,
+	"brandy": 40, "cognac": 40, "armagnac": 40, "pisco": 40, "calvados": 40,
+	"applejack": 40, "apple brandy": 40, "absinthe": 60, "pastis": 45, "ouzo": 40,
+	"grappa": 40, "aquavit": 40,
+
+	// Liqueurs, amari and aperitifs
+	"campari": 24, "aperol": 11, "cynar": 16.5, "fernet": 39, "fernet branca": 39,
+	"amaro": 30, "amaro nonino": 35, "montenegro": 23, "averna": 29, "suze": 20,
+	"triple sec": 30, "cointreau": 40, "grand marnier": 40, "curacao": 25,
+	"curaçao": 25, "orange liqueur": 40, "maraschino": 32, "maraschino liqueur": 32,
+	"chartreuse": 55, "green chartreuse": 55, "yellow chartreuse": 40,
+	"benedictine": 40, "bénédictine": 40, "drambuie": 40, "galliano": 42,
+	"amaretto": 28, "kahlua": 20, "coffee liqueur": 20, "irish cream": 17,
+	"baileys": 17, "elderflower liqueur": 20, "st germain": 20, "st-germain": 20,
+	"creme de cassis": 15, "crème de cassis": 15, "creme de violette": 16,
+	"crème de violette": 16, "creme de cacao": 24, "crème de cacao": 24,
+	"creme de menthe": 24, "crème de menthe": 24, "creme de mure": 16,
+	"falernum": 11, "velvet falernum": 11, "allspice dram": 22, "limoncello": 30,
+	"sambuca": 40, "midori": 20, "melon liqueur": 20, "peach schnapps": 20,
+	"schnapps": 20, "cherry heering": 24, "cherry liqueur": 24, "becherovka": 38,
+	"pimm's": 25, "pimms": 25, "lillet": 17, "lillet blanc": 17, "cocchi americano": 16.5,
+	"bitters": 45, "angostura": 45, "angostura bitters": 45, "peychaud's bitters": 35,
+	"peychauds bitters": 35, "orange bitters": 28,
+
+	// Fortified and other wines, beer
+	"vermouth": 16, "sweet vermouth": 16, "rosso vermouth": 16, "dry vermouth": 18,
+	"blanc vermouth": 16, "bianco vermouth": 16, "sherry": 17, "fino sherry": 15,
+	"amontillado": 18, "oloroso": 19, "pedro ximenez": 17, "port": 20, "ruby port": 20,
+	"tawny port": 20, "madeira": 19, "wine": 12, "red wine": 13, "white wine": 12,
+	"champagne": 12, "prosecco": 11, "cava": 11.5, "sparkling wine": 12, "sake": 15,
+	"beer": 5, "lager": 5, "stout": 6, "cider": 5,
+
+	// Non-alcoholic
+	"water": 0, "soda": 0, "soda water": 0, "club soda": 0, "sparkling water": 0,
+	"tonic": 0, "tonic water": 0, "ginger beer": 0, "ginger ale": 0, "cola": 0,
+	"lemonade": 0, "juice": 0, "syrup": 0, "simple syrup": 0, "grenadine": 0,
+	"orgeat": 0, "honey": 0, "agave": 0, "agave nectar": 0, "sugar": 0, "cream": 0,
+	"heavy cream": 0, "coconut cream": 0, "cream of coconut": 0, "milk": 0,
+	"coconut milk": 0, "egg": 0, "egg white": 0, "egg yolk": 0, "aquafaba": 0,
+	"coffee": 0, "espresso": 0, "cold brew": 0, "tea": 0, "puree": 0, "purée": 0,
+	"cordial": 0, "shrub": 0, "brine": 0, "olive brine": 0, "tomato juice": 0,
+	"non-alcoholic": 0, "alcohol-free": 0, "verjus": 0,
+}
+
+var abvKeywords = newKeywordTable(abvCatalog)
+
+// KnownABV looks up the typical strength of an ingredient, in percent ABV,
+// matching the whole name first and then the most specific catalog entry
+// whose words appear in it
+func KnownABV(name string) (float64, bool) {
+	if key, ok := abvKeywords.match(name); ok {
+		return abvCatalog[key], true
+	}
+	return 0, false
+}
@@ -0,0 +1,288 @@
+package entity
+
+import "sort"
+
+// Substitute is an ingredient that can stand in for another. Confidence runs
+// from 0 to 1, where 1 means the drink will be practically unchanged.
+type Substitute struct {
+	Name       string  `json:"name"`
+	Confidence float64 `json:"confidence"`
+	Notes      string  `json:"notes,omitempty"`
+}
+
+// Substitution records that Substitute can replace Ingredient
+type Substitution struct {
+	Ingredient string  `json:"ingredient"`
+	Substitute string  `json:"substitute"`
+	Confidence float64 `json:"confidence"`
+}
+
+// substitutionTable maps normalized ingredient names and name fragments to
+// the ingredients a bar can reach for when it runs out of them
+var substitutionTable = map[string][]Substitute{
+	// Orange liqueurs
+	"cointreau": {
+		{Name: "triple sec", Confidence: 0.9},
+		{Name: "orange curacao", Confidence: 0.8, Notes: "slightly sweeter"},
+		{Name: "grand marnier", Confidence: 0.75, Notes: "cognac base adds weight"},
+	},
+	"triple sec": {
+		{Name: "cointreau", Confidence: 0.9},
+		{Name: "orange curacao", Confidence: 0.8},
+		{Name: "grand marnier", Confidence: 0.7},
+	},
+	"grand marnier": {
+		{Name: "cointreau", Confidence: 0.75},
+		{Name: "orange curacao", Confidence: 0.75},
+		{Name: "triple sec", Confidence: 0.7},
+	},
+	"orange curacao": {
+		{Name: "cointreau", Confidence: 0.8},
+		{Name: "triple sec", Confidence: 0.8},
+		{Name: "grand marnier", Confidence: 0.75},
+	},
+
+	// Whiskies
+	"rye": {
+		{Name: "bourbon", Confidence: 0.8, Notes: "sweeter and less spicy"},
+		{Name: "canadian whisky", Confidence: 0.6},
+	},
+	"bourbon": {
+		{Name: "rye whiskey", Confidence: 0.8, Notes: "drier and spicier"},
+		{Name: "tennessee whiskey", Confidence: 0.8},
+		{Name: "canadian whisky", Confidence: 0.55},
+	},
+	"scotch": {
+		{Name: "irish whiskey", Confidence: 0.6},
+		{Name: "japanese whisky", Confidence: 0.65},
+	},
+	"islay scotch": {
+		{Name: "mezcal", Confidence: 0.5, Notes: "smoke of a different kind"},
+		{Name: "scotch", Confidence: 0.5, Notes: "loses the peat"},
+	},
+
+	// Other spirits
+	"gin": {
+		{Name: "genever", Confidence: 0.6, Notes: "maltier"},
+		{Name: "vodka", Confidence: 0.4, Notes: "loses the botanicals"},
+	},
+	"london dry gin": {
+		{Name: "gin", Confidence: 0.9},
+		{Name: "plymouth gin", Confidence: 0.85},
+	},
+	"vodka": {
+		{Name: "gin", Confidence: 0.5, Notes: "adds botanicals"},
+		{Name: "white rum", Confidence: 0.45},
+	},
+	"white rum": {
+		{Name: "light rum", Confidence: 0.95},
+		{Name: "rhum agricole", Confidence: 0.6, Notes: "grassier"},
+		{Name: "cachaca", Confidence: 0.6},
+	},
+	"dark rum": {
+		{Name: "aged rum", Confidence: 0.85},
+		{Name: "demerara rum", Confidence: 0.8},
+		{Name: "gold rum", Confidence: 0.7},
+	},
+	"tequila": {
+		{Name: "mezcal", Confidence: 0.6, Notes: "smoky"},
+	},
+	"mezcal": {
+		{Name: "tequila", Confidence: 0.6, Notes: "loses the smoke"},
+	},
+	"cognac": {
+		{Name: "brandy", Confidence: 0.85},
+		{Name: "armagnac", Confidence: 0.85},
+	},
+	"brandy": {
+		{Name: "cognac", Confidence: 0.9},
+		{Name: "armagnac", Confidence: 0.85},
+	},
+	"applejack": {
+		{Name: "calvados", Confidence: 0.8},
+		{Name: "apple brandy", Confidence: 0.9},
+	},
+	"calvados": {
+		{Name: "applejack", Confidence: 0.8},
+		{Name: "apple brandy", Confidence: 0.85},
+	},
+	"absinthe": {
+		{Name: "pastis", Confidence: 0.7},
+		{Name: "herbsaint", Confidence: 0.8},
+	},
+
+	// Aperitifs, amari and vermouth
+	"campari": {
+		{Name: "red bitter aperitivo", Confidence: 0.85},
+		{Name: "aperol", Confidence: 0.55, Notes: "lighter and sweeter"},
+	},
+	"aperol": {
+		{Name: "campari", Confidence: 0.55, Notes: "more bitter and stronger"},
+	},
+	"sweet vermouth": {
+		{Name: "rosso vermouth", Confidence: 0.95},
+		{Name: "punt e mes", Confidence: 0.7, Notes: "more bitter"},
+	},
+	"dry vermouth": {
+		{Name: "blanc vermouth", Confidence: 0.7, Notes: "sweeter"},
+		{Name: "fino sherry", Confidence: 0.6},
+		{Name: "lillet blanc", Confidence: 0.55},
+	},
+	"lillet blanc": {
+		{Name: "cocchi americano", Confidence: 0.85},
+		{Name: "blanc vermouth", Confidence: 0.6},
+	},
+	"cocchi americano": {
+		{Name: "lillet blanc", Confidence: 0.85},
+	},
+	"green chartreuse": {
+		{Name: "yellow chartreuse", Confidence: 0.55, Notes: "sweeter and milder"},
+	},
+	"yellow chartreuse": {
+		{Name: "green chartreuse", Confidence: 0.55, Notes: "stronger and more herbal"},
+		{Name: "benedictine", Confidence: 0.5},
+	},
+	"maraschino": {
+		{Name: "cherry liqueur", Confidence: 0.5},
+	},
+	"st-germain": {
+		{Name: "elderflower liqueur", Confidence: 0.9},
+		{Name: "elderflower cordial", Confidence: 0.5, Notes: "non-alcoholic"},
+	},
+	"elderflower liqueur": {
+		{Name: "st-germain", Confidence: 0.9},
+	},
+	"amaretto": {
+		{Name: "orgeat", Confidence: 0.5, Notes: "non-alcoholic and sweeter"},
+	},
+	"kahlua": {
+		{Name: "coffee liqueur", Confidence: 0.95},
+	},
+	"coffee liqueur": {
+		{Name: "kahlua", Confidence: 0.95},
+	},
+
+	// Bitters
+	"angostura bitters": {
+		{Name: "aromatic bitters", Confidence: 0.85},
+		{Name: "peychaud's bitters", Confidence: 0.55},
+	},
+	"peychaud's bitters": {
+		{Name: "angostura bitters", Confidence: 0.55},
+	},
+	"orange bitters": {
+		{Name: "angostura orange bitters", Confidence: 0.9},
+		{Name: "angostura bitters", Confidence: 0.45},
+	},
+
+	// Citrus and juices
+	"lime juice": {
+		{Name: "lemon juice", Confidence: 0.7},
+	},
+	"lemon juice": {
+		{Name: "lime juice", Confidence: 0.7},
+	},
+	"grapefruit juice": {
+		{Name: "orange juice", Confidence: 0.45},
+	},
+
+	// Sweeteners
+	"simple syrup": {
+		{Name: "rich simple syrup", Confidence: 0.85, Notes: "use two thirds as much"},
+		{Name: "agave syrup", Confidence: 0.7},
+		{Name: "demerara syrup", Confidence: 0.7},
+		{Name: "honey syrup", Confidence: 0.6},
+	},
+	"rich simple syrup": {
+		{Name: "simple syrup", Confidence: 0.85, Notes: "use half as much again"},
+		{Name: "demerara syrup", Confidence: 0.75},
+	},
+	"demerara syrup": {
+		{Name: "rich simple syrup", Confidence: 0.75},
+		{Name: "simple syrup", Confidence: 0.7},
+	},
+	"agave syrup": {
+		{Name: "simple syrup", Confidence: 0.7},
+		{Name: "honey syrup", Confidence: 0.6},
+	},
+	"honey syrup": {
+		{Name: "agave syrup", Confidence: 0.6},
+		{Name: "simple syrup", Confidence: 0.55},
+	},
+	"orgeat": {
+		{Name: "almond syrup", Confidence: 0.8},
+	},
+	"grenadine": {
+		{Name: "pomegranate syrup", Confidence: 0.85},
+		{Name: "raspberry syrup", Confidence: 0.5},
+	},
+
+	// Mixers and other
+	"ginger beer": {
+		{Name: "ginger ale", Confidence: 0.6, Notes: "less spicy"},
+	},
+	"ginger ale": {
+		{Name: "ginger beer", Confidence: 0.6, Notes: "spicier"},
+	},
+	"club soda": {
+		{Name: "soda water", Confidence: 0.95},
+		{Name: "sparkling water", Confidence: 0.9},
+	},
+	"soda water": {
+		{Name: "club soda", Confidence: 0.95},
+		{Name: "sparkling water", Confidence: 0.9},
+	},
+	"egg white": {
+		{Name: "aquafaba", Confidence: 0.8},
+	},
+	"aquafaba": {
+		{Name: "egg white", Confidence: 0.8},
+	},
+	"heavy cream": {
+		{Name: "coconut cream", Confidence: 0.5},
+		{Name: "half and half", Confidence: 0.6},
+	},
+}
+
+var substitutionKeywords = newKeywordTable(substitutionTable)
+
+// SubstitutesFor suggests ingredients that can stand in for the named one,
+// most confident first
+func SubstitutesFor(name string) []Substitute {
+	key, ok := substitutionKeywords.match(name)
+	if !ok {
+		return []Substitute{}
+	}
+	subs := append([]Substitute(nil), substitutionTable[key]...)
+	sort.SliceStable(subs, func(i, j int) bool {
+		return subs[i].Confidence > subs[j].Confidence
+	})
+	return subs
+}
+
+// ReplaceableBy lists the ingredients the named one can stand in for with at
+// least minConfidence, most confident first. Having bourbon, for example,
+// covers recipes calling for rye.
+func ReplaceableBy(name string, minConfidence float64) []Substitution {
+	words := nameTokens(name)
+	var found []Substitution
+	for ingredient, subs := range substitutionTable {
+		for _, sub := range subs {
+			if sub.Confidence >= minConfidence && containsWords(words, nameTokens(sub.Name)) {
+				found = append(found, Substitution{
+					Ingredient: ingredient,
+					Substitute: sub.Name,
+					Confidence: sub.Confidence,
+				})
+				break
+			}
+		}
+	}
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].Confidence != found[j].Confidence {
+			return found[i].Confidence > found[j].Confidence
+		}
+		return found[i].Ingredient < found[j].Ingredient
+	})
+	return found
+}
@@ -0,0 +1,117 @@
+package entity
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Role decides what a user may do beyond managing their own recipes
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	switch r {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Password length limits. bcrypt ignores everything after 72 bytes, so
+// longer passwords are refused rather than silently truncated.
+const (
+	MinPasswordLength = 8
+	MaxPasswordBytes  = 72
+	MaxEmailLength    = 254
+)
+
+// User is an account that can sign in and own recipes. PasswordHash is a
+// bcrypt hash and is never serialized to clients. A disabled account can no
+// longer sign in or use its tokens.
+type User struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Email        string             `json:"email" bson:"email"`
+	DisplayName  string             `json:"display_name" bson:"display_name"`
+	PasswordHash string             `json:"-" bson:"password_hash"`
+	Role         Role               `json:"role" bson:"role"`
+	Disabled     bool               `json:"disabled" bson:"disabled"`
+	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// NewUser creates a new User entity with an already hashed password. An
+// empty display name defaults to the part of the email before the @.
+func NewUser(email, displayName, passwordHash string, role Role) *User {
+	email = NormalizeEmail(email)
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		displayName, _, _ = strings.Cut(email, "@")
+	}
+	now := time.Now()
+	return &User{
+		Email:        email,
+		DisplayName:  displayName,
+		PasswordHash: passwordHash,
+		Role:         role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// NormalizeEmail returns the form of an email address accounts are looked
+// up by: trimmed and lower-cased
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsAdmin reports whether the user administers the site
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsModerator reports whether the user moderates recipes. Admins are
+// moderators too.
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
+
+// Owns reports whether the user created the recipe
+func (u *User) Owns(recipe *Recipe) bool {
+	return recipe.CreatorID != nil && *recipe.CreatorID == u.ID
+}
+
+// ValidateCredentials checks an email, password and display name offered
+// at registration, returning a *ValidationError that lists every invalid
+// field
+func ValidateCredentials(email, password, displayName string) error {
+	verr := &ValidationError{}
+
+	email = NormalizeEmail(email)
+	verr.required("email", email)
+	if email != "" {
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			verr.Add("email", "must be a valid email address")
+		}
+	}
+	verr.maxLength("email", email, MaxEmailLength)
+
+	if len([]rune(password)) < MinPasswordLength {
+		verr.Add("password", "must be at least %d characters", MinPasswordLength)
+	}
+	if len(password) > MaxPasswordBytes {
+		verr.Add("password", "must be at most %d bytes", MaxPasswordBytes)
+	}
+
+	verr.maxLength("display_name", strings.TrimSpace(displayName), MaxNameLength)
+
+	return verr.Err()
+}
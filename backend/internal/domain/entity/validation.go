@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits on the size of the recipes and catalog ingredients users can write
+const (
+	MaxNameLength        = 200
+	MaxDescriptionLength = 5000
+	MaxShortTextLength   = 200
+	MaxNotesLength       = 500
+	MaxIngredients       = 100
+	MaxInstructions      = 100
+	MaxInstructionLength = 2000
+	MaxEquipment         = 50
+	MaxAliases           = 50
+	// MaxAmount bounds an ingredient amount in whatever unit it is given in
+	MaxAmount = 100000
+	// MaxRecipeServings bounds how many servings a recipe may be written for
+	MaxRecipeServings = 1000
+	// MaxMinutes bounds prep and cook times; a week covers ferments and cures
+	MaxMinutes = 7 * 24 * 60
+)
+
+// FieldError describes one invalid field. Field is a JSON path into the
+// request such as "name" or "ingredients[2].unit".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every problem found while validating an entity
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error joins the field errors as "field: message" pairs
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		parts[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Add records a problem with field
+func (e *ValidationError) Add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err returns e when it holds any field errors and nil otherwise, so callers
+// never return a non-nil error interface wrapping an empty list
+func (e *ValidationError) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// required records field as missing when value is blank
+func (e *ValidationError) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		e.Add(field, "required")
+	}
+}
+
+// maxLength records field as too long when value has more than max
+// characters
+func (e *ValidationError) maxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		e.Add(field, "must be at most %d characters", max)
+	}
+}
+
+// intRange records field as out of range when value is outside [min, max]
+func (e *ValidationError) intRange(field string, value, min, max int) {
+	if value < min || value > max {
+		e.Add(field, "must be between %d and %d", min, max)
+	}
+}
@@ -0,0 +1,179 @@
+package recipeimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// csvColumns are the columns a CSV import reads. Headers are matched
+// ignoring case, with spaces and hyphens read as underscores; other columns
+// are ignored.
+var csvColumns = map[string]bool{
+	"name": true, "type": true, "description": true, "glass": true, "garnish": true,
+	"technique": true, "tags": true, "base_spirit": true, "season": true,
+	"servings": true, "prep_minutes": true, "cook_minutes": true, "yield": true,
+	"instructions": true, "ingredient": true, "amount": true, "unit": true,
+	"notes": true, "optional": true,
+}
+
+// csvRecipe gathers the rows of one recipe
+type csvRecipe struct {
+	row   Row
+	probs problems
+	name  string
+}
+
+// decodeCSV reads a CSV file with a header row and one row per ingredient.
+// A row starts a new recipe when its name differs from the row before; a
+// row with a blank name continues the recipe above. Recipe fields are read
+// from a recipe's first row, except instructions, which every row may add
+// steps to, one per line or separated by "|". When a row has neither an
+// amount nor a unit its ingredient is read as a free-text line such as
+// "1 1/2 oz rye whiskey".
+func decodeCSV(data []byte) ([]Row, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, malformed("the file is empty")
+	}
+	if err != nil {
+		return nil, malformed("%v", err)
+	}
+	columns := make(map[string]int)
+	for i, h := range header {
+		key := strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(h)))
+		if csvColumns[key] {
+			if _, dup := columns[key]; !dup {
+				columns[key] = i
+			}
+		}
+	}
+	for _, required := range []string{"name", "ingredient"} {
+		if _, ok := columns[required]; !ok {
+			return nil, malformed("the header has no %s column", required)
+		}
+	}
+
+	var recipes []*csvRecipe
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, malformed("line %d: %v", parseErr.StartLine, parseErr.Err)
+			}
+			return nil, malformed("%v", err)
+		}
+		line, _ := r.FieldPos(0)
+		cell := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+
+		name := cell("name")
+		var current *csvRecipe
+		if n := len(recipes); n > 0 && (name == "" || strings.EqualFold(name, recipes[n-1].name)) {
+			current = recipes[n-1]
+		} else {
+			current = &csvRecipe{name: name, row: Row{Line: line}}
+			current.readRecipeFields(cell)
+			recipes = append(recipes, current)
+		}
+		current.readIngredient(cell)
+		for _, step := range strings.Split(cell("instructions"), "|") {
+			current.row.Details.Instructions = append(current.row.Details.Instructions, splitSteps(step)...)
+		}
+	}
+
+	rows := make([]Row, len(recipes))
+	for i, recipe := range recipes {
+		recipe.row.Problems = recipe.probs
+		rows[i] = recipe.row
+	}
+	return rows, nil
+}
+
+// readRecipeFields reads the recipe-level columns of a recipe's first row
+func (c *csvRecipe) readRecipeFields(cell func(string) string) {
+	d := &c.row.Details
+	d.Name = c.name
+	d.Type = entity.RecipeType(strings.ToLower(cell("type")))
+	d.Description = cell("description")
+	d.Glass = cell("glass")
+	d.Garnish = cell("garnish")
+	d.Technique = entity.Technique(strings.ToLower(cell("technique")))
+	d.Tags = splitList(cell("tags"))
+	d.BaseSpirit = entity.BaseSpirit(strings.ToLower(cell("base_spirit")))
+	d.Season = entity.Season(strings.ToLower(cell("season")))
+	d.Yield = cell("yield")
+	for column, dest := range map[string]*int{
+		"servings":     &d.Servings,
+		"prep_minutes": &d.PrepMinutes,
+		"cook_minutes": &d.CookMinutes,
+	} {
+		if text := cell(column); text != "" {
+			n, err := strconv.Atoi(text)
+			if err != nil {
+				c.probs.add(column, "must be a whole number")
+			}
+			*dest = n
+		}
+	}
+}
+
+// readIngredient adds the ingredient of one row, if it has one
+func (c *csvRecipe) readIngredient(cell func(string) string) {
+	text := cell("ingredient")
+	if text == "" {
+		return
+	}
+	d := &c.row.Details
+	field := fmt.Sprintf("ingredients[%d]", len(d.Ingredients))
+
+	amount, unit := cell("amount"), cell("unit")
+	var ing entity.Ingredient
+	if amount == "" && unit == "" {
+		ing = entity.ParseIngredientLine(text)
+	} else {
+		ing.Name = text
+		ing.Unit = unit
+		if amount != "" {
+			parsed, ok := entity.ParseAmount(amount)
+			if !ok {
+				c.probs.add(field+".amount", "must be a number")
+			}
+			ing.Amount = parsed
+		}
+	}
+	if notes := cell("notes"); notes != "" {
+		ing.Notes = notes
+	}
+	if optional := cell("optional"); optional != "" {
+		switch strings.ToLower(optional) {
+		case "yes", "y", "true", "1", "x":
+			ing.IsOptional = true
+		case "no", "n", "false", "0":
+		default:
+			c.probs.add(field+".is_optional", "must be yes or no")
+		}
+	}
+	d.Ingredients = append(d.Ingredients, ing)
+}
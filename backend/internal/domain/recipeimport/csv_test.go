@@ -0,0 +1,136 @@
+package recipeimport
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+func TestDecodeCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Row
+	}{
+		{
+			name: "one row per ingredient",
+			data: "name,type,glass,ingredient,amount,unit,instructions\n" +
+				"Negroni,cocktail,Rocks,Gin,1,oz,Stir with ice\n" +
+				",,,Campari,1,oz,Strain\n" +
+				",,,Sweet vermouth,1,oz,\n",
+			want: []Row{{
+				Line: 2,
+				Details: entity.RecipeDetails{
+					Name: "Negroni", Type: entity.RecipeTypeCocktail, Glass: "Rocks",
+					Ingredients: []entity.Ingredient{
+						{Name: "Gin", Amount: 1, Unit: "oz"},
+						{Name: "Campari", Amount: 1, Unit: "oz"},
+						{Name: "Sweet vermouth", Amount: 1, Unit: "oz"},
+					},
+					Instructions: []string{"Stir with ice", "Strain"},
+				},
+			}},
+		},
+		{
+			name: "a changed name starts the next recipe",
+			data: "Name,Ingredient\n" +
+				"Daiquiri,2 oz white rum\n" +
+				"daiquiri,3/4 oz lime juice\n" +
+				"Gimlet,2 oz gin\n",
+			want: []Row{
+				{Line: 2, Details: entity.RecipeDetails{Name: "Daiquiri", Ingredients: []entity.Ingredient{
+					{Name: "white rum", Amount: 2, Unit: "oz"},
+					{Name: "lime juice", Amount: 0.75, Unit: "oz"},
+				}}},
+				{Line: 4, Details: entity.RecipeDetails{Name: "Gimlet", Ingredients: []entity.Ingredient{
+					{Name: "gin", Amount: 2, Unit: "oz"},
+				}}},
+			},
+		},
+		{
+			name: "headers ignore case, spaces, hyphens and a byte order mark",
+			data: "\xef\xbb\xbfNAME, Base Spirit ,prep-minutes,tags,ingredient,unknown\n" +
+				"Mojito,RUM,5,\"summer, highball\",Mint,ignored\n",
+			want: []Row{{Line: 2, Details: entity.RecipeDetails{
+				Name: "Mojito", BaseSpirit: "rum", PrepMinutes: 5, Tags: []string{"summer", "highball"},
+				Ingredients: []entity.Ingredient{{Name: "Mint"}},
+			}}},
+		},
+		{
+			name: "instructions split on bars and lines across rows",
+			data: "name,ingredient,instructions\n" +
+				"Sour,Whiskey,Shake | Strain\n" +
+				",Lemon,\"Garnish\nServe\"\n",
+			want: []Row{{Line: 2, Details: entity.RecipeDetails{
+				Name:         "Sour",
+				Ingredients:  []entity.Ingredient{{Name: "Whiskey"}, {Name: "Lemon"}},
+				Instructions: []string{"Shake", "Strain", "Garnish", "Serve"},
+			}}},
+		},
+		{
+			name: "amount column with a mixed number, notes and optional",
+			data: "name,ingredient,amount,unit,notes,optional\n" +
+				"Sazerac,Rye,1 1/2,oz,,no\n" +
+				",Absinthe,,,rinse,yes\n",
+			want: []Row{{Line: 2, Details: entity.RecipeDetails{Name: "Sazerac", Ingredients: []entity.Ingredient{
+				{Name: "Rye", Amount: 1.5, Unit: "oz"},
+				{Name: "Absinthe", Notes: "rinse", IsOptional: true},
+			}}}},
+		},
+		{
+			name: "field problems are reported on the row",
+			data: "name,servings,ingredient,amount,unit,optional\n" +
+				"Punch,many,Rum,lots,oz,maybe\n",
+			want: []Row{{
+				Line: 2,
+				Details: entity.RecipeDetails{Name: "Punch", Ingredients: []entity.Ingredient{
+					{Name: "Rum", Unit: "oz"},
+				}},
+				Problems: []entity.FieldError{
+					{Field: "servings", Message: "must be a whole number"},
+					{Field: "ingredients[0].amount", Message: "must be a number"},
+					{Field: "ingredients[0].is_optional", Message: "must be yes or no"},
+				},
+			}},
+		},
+		{
+			name: "blank rows are skipped",
+			data: "name,ingredient\n,\nTonic,Tonic water\n\n",
+			want: []Row{{Line: 3, Details: entity.RecipeDetails{Name: "Tonic", Ingredients: []entity.Ingredient{
+				{Name: "Tonic water"},
+			}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCSV([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("decodeCSV: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeCSV =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCSVMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"no name column", "title,ingredient\nNegroni,Gin\n"},
+		{"no ingredient column", "name,amount\nNegroni,1\n"},
+		{"unterminated quote", "name,ingredient\nNegroni,\"Gin\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCSV([]byte(tt.data)); !errors.Is(err, ErrMalformedFile) {
+				t.Errorf("decodeCSV error = %v, want ErrMalformedFile", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package recipeimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/units"
+)
+
+// nativeRecipe is a recipe as this API writes it. Ingredients may also be
+// free-text lines and instructions a single text with one step per line.
+type nativeRecipe struct {
+	Name            string                `json:"name"`
+	Type            entity.RecipeType     `json:"type"`
+	Description     string                `json:"description"`
+	Ingredients     []json.RawMessage     `json:"ingredients"`
+	Instructions    json.RawMessage       `json:"instructions"`
+	Glass           string                `json:"glass"`
+	Garnish         string                `json:"garnish"`
+	Technique       entity.Technique      `json:"technique"`
+	Tags            []string              `json:"tags"`
+	Flavor          *entity.FlavorProfile `json:"flavor"`
+	BaseSpirit      entity.BaseSpirit     `json:"base_spirit"`
+	Season          entity.Season         `json:"season"`
+	Servings        int                   `json:"servings"`
+	PrepMinutes     int                   `json:"prep_minutes"`
+	CookMinutes     int                   `json:"cook_minutes"`
+	Yield           string                `json:"yield"`
+	Equipment       []string              `json:"equipment"`
+	OvenTemperature *units.Temperature    `json:"oven_temperature"`
+}
+
+// decodeJSON reads an array of recipes in the API's own format, or an
+// object holding them under "recipes", or a single recipe
+func decodeJSON(data []byte) ([]Row, error) {
+	var items []json.RawMessage
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, malformed("%v", err)
+		}
+	default:
+		var wrapper struct {
+			Recipes []json.RawMessage `json:"recipes"`
+		}
+		if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+			return nil, malformed("%v", err)
+		}
+		items = wrapper.Recipes
+		if items == nil {
+			items = []json.RawMessage{trimmed}
+		}
+	}
+
+	rows := make([]Row, len(items))
+	for i, item := range items {
+		rows[i] = decodeNativeRecipe(item)
+	}
+	return rows, nil
+}
+
+func decodeNativeRecipe(item json.RawMessage) Row {
+	var row Row
+	var probs problems
+
+	// Unmarshal fills every field it can before reporting the first one of
+	// the wrong type, so the rest of the recipe is still checked
+	var recipe nativeRecipe
+	if err := json.Unmarshal(item, &recipe); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) || typeErr.Field == "" {
+			probs.add("", "must be a recipe object")
+			row.Problems = probs
+			return row
+		}
+		probs.add(typeErr.Field, "must be %s", jsonTypeName(typeErr.Type))
+	}
+
+	var ingredients []entity.Ingredient
+	for i, raw := range recipe.Ingredients {
+		if isJSONString(raw) {
+			var line string
+			json.Unmarshal(raw, &line)
+			ingredients = append(ingredients, entity.ParseIngredientLine(line))
+			continue
+		}
+		var ing entity.Ingredient
+		if err := json.Unmarshal(raw, &ing); err != nil {
+			probs.add(fmt.Sprintf("ingredients[%d]", i), "must be an ingredient object or a line of text")
+		}
+		ingredients = append(ingredients, ing)
+	}
+
+	var instructions []string
+	if len(recipe.Instructions) > 0 && !bytes.Equal(bytes.TrimSpace(recipe.Instructions), []byte("null")) {
+		if isJSONString(recipe.Instructions) {
+			var text string
+			json.Unmarshal(recipe.Instructions, &text)
+			instructions = splitSteps(text)
+		} else if err := json.Unmarshal(recipe.Instructions, &instructions); err != nil {
+			probs.add("instructions", "must be a list of steps or a text")
+		}
+	}
+
+	row.Details = entity.RecipeDetails{
+		Name:            recipe.Name,
+		Type:            recipe.Type,
+		Description:     recipe.Description,
+		Ingredients:     ingredients,
+		Instructions:    instructions,
+		Glass:           recipe.Glass,
+		Garnish:         recipe.Garnish,
+		Technique:       recipe.Technique,
+		Tags:            recipe.Tags,
+		Flavor:          recipe.Flavor,
+		BaseSpirit:      recipe.BaseSpirit,
+		Season:          recipe.Season,
+		Servings:        recipe.Servings,
+		PrepMinutes:     recipe.PrepMinutes,
+		CookMinutes:     recipe.CookMinutes,
+		Yield:           recipe.Yield,
+		Equipment:       recipe.Equipment,
+		OvenTemperature: recipe.OvenTemperature,
+	}
+	row.Problems = probs
+	return row
+}
+
+// jsonTypeName describes the JSON value a Go type is decoded from
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	}
+	return "an object"
+}
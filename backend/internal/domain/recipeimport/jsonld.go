@@ -0,0 +1,205 @@
+package recipeimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// drinkWords mark a schema.org recipe as a cocktail when its category,
+// keywords or name mention one of them; other recipes are food
+var drinkWords = []string{"cocktail", "drink", "beverage"}
+
+// isoDuration matches the ISO 8601 durations schema.org times are written
+// in, such as "PT1H30M" or "P0DT20M"
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// leadingNumber finds the number a yield such as "4 servings" starts with
+var leadingNumber = regexp.MustCompile(`^\s*(\d+)`)
+
+// decodeJSONLD reads every schema.org Recipe in a JSON-LD document: a
+// single node, an array of nodes or a graph. Other nodes are skipped.
+func decodeJSONLD(data []byte) ([]Row, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, malformed("%v", err)
+	}
+	var rows []Row
+	for _, node := range recipeNodes(doc) {
+		rows = append(rows, decodeRecipeNode(node))
+	}
+	if len(rows) == 0 {
+		return nil, malformed("no schema.org Recipe found")
+	}
+	return rows, nil
+}
+
+// recipeNodes collects the nodes whose @type includes Recipe, looking into
+// arrays and @graph
+func recipeNodes(v interface{}) []map[string]interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		var nodes []map[string]interface{}
+		for _, item := range v {
+			nodes = append(nodes, recipeNodes(item)...)
+		}
+		return nodes
+	case map[string]interface{}:
+		if graph, ok := v["@graph"]; ok {
+			return recipeNodes(graph)
+		}
+		for _, t := range texts(v["@type"]) {
+			if t == "Recipe" || strings.HasSuffix(t, "/Recipe") {
+				return []map[string]interface{}{v}
+			}
+		}
+	}
+	return nil
+}
+
+func decodeRecipeNode(node map[string]interface{}) Row {
+	var probs problems
+	d := entity.RecipeDetails{
+		Name:        text(node["name"]),
+		Description: text(node["description"]),
+	}
+
+	lines := texts(node["recipeIngredient"])
+	if lines == nil {
+		lines = texts(node["ingredients"])
+	}
+	d.Ingredients = parseIngredientLines(lines)
+	d.Instructions = instructionSteps(node["recipeInstructions"])
+
+	var tags []string
+	for _, field := range []string{"recipeCategory", "recipeCuisine", "keywords"} {
+		for _, t := range texts(node[field]) {
+			tags = append(tags, splitList(t)...)
+		}
+	}
+	d.Tags = tags
+
+	d.Type = entity.RecipeTypeFood
+	classifiers := strings.ToLower(strings.Join(tags, " ") + " " + d.Name)
+	for _, word := range drinkWords {
+		if strings.Contains(classifiers, word) {
+			d.Type = entity.RecipeTypeCocktail
+			break
+		}
+	}
+
+	if yields := texts(node["recipeYield"]); len(yields) > 0 {
+		if m := leadingNumber.FindStringSubmatch(yields[0]); m != nil {
+			d.Servings, _ = strconv.Atoi(m[1])
+		}
+		for _, y := range yields {
+			if strings.IndexFunc(y, unicode.IsLetter) >= 0 {
+				d.Yield = y
+				break
+			}
+		}
+	}
+
+	for _, duration := range []struct {
+		property, field string
+		dest            *int
+	}{
+		{"prepTime", "prep_minutes", &d.PrepMinutes},
+		{"cookTime", "cook_minutes", &d.CookMinutes},
+	} {
+		if s := text(node[duration.property]); s != "" {
+			minutes, ok := durationMinutes(s)
+			if !ok {
+				probs.add(duration.field, "%s must be an ISO 8601 duration such as PT15M", duration.property)
+			}
+			*duration.dest = minutes
+		}
+	}
+	if d.Type == entity.RecipeTypeCocktail {
+		// a drink's "cook time" is mixing time
+		d.PrepMinutes += d.CookMinutes
+		d.CookMinutes = 0
+	}
+
+	return Row{Details: d, Problems: probs}
+}
+
+// instructionSteps reads recipeInstructions written as a text, a list of
+// texts, HowToSteps or HowToSections of steps
+func instructionSteps(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return splitSteps(html.UnescapeString(v))
+	case []interface{}:
+		var steps []string
+		for _, item := range v {
+			steps = append(steps, instructionSteps(item)...)
+		}
+		return steps
+	case map[string]interface{}:
+		if items, ok := v["itemListElement"]; ok {
+			return instructionSteps(items)
+		}
+		if t := text(v["text"]); t != "" {
+			return splitSteps(t)
+		}
+		return splitSteps(text(v["name"]))
+	}
+	return nil
+}
+
+// durationMinutes converts an ISO 8601 duration to whole minutes
+func durationMinutes(s string) (int, bool) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	m := isoDuration.FindStringSubmatch(s)
+	if m == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, false
+	}
+	days, _ := strconv.Atoi(m[1])
+	hours, _ := strconv.Atoi(m[2])
+	minutes, _ := strconv.Atoi(m[3])
+	seconds, _ := strconv.ParseFloat(m[4], 64)
+	return days*24*60 + hours*60 + minutes + int(seconds/60+0.5), true
+}
+
+// text reads a JSON-LD value as a string: a string or number, a value
+// object, or the first of a list
+func text(v interface{}) string {
+	if all := texts(v); len(all) > 0 {
+		return all[0]
+	}
+	return ""
+}
+
+// texts reads a JSON-LD value that may be a single value or a list as
+// strings, unescaping HTML entities
+func texts(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		if s := strings.TrimSpace(html.UnescapeString(v)); s != "" {
+			return []string{s}
+		}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case []interface{}:
+		var all []string
+		for _, item := range v {
+			all = append(all, texts(item)...)
+		}
+		return all
+	case map[string]interface{}:
+		if value, ok := v["@value"]; ok {
+			return texts(value)
+		}
+	case nil:
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Package recipeimport reads recipes exported from spreadsheets and other
+// apps: this API's own JSON, CSV with one row per ingredient, and
+// schema.org Recipe JSON-LD. Decoding only reads what each recipe says;
+// problems with single fields are reported on the recipe's Row so the rest
+// of the file can still be imported, and recipes are validated later like
+// any other.
+package recipeimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// Format is a file format recipes can be imported from
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatCSV    Format = "csv"
+	FormatJSONLD Format = "jsonld"
+)
+
+var (
+	// ErrUnknownFormat is returned for a format name Decode does not know
+	ErrUnknownFormat = errors.New("format must be json, csv or jsonld")
+	// ErrMalformedFile is returned, wrapped with the reason, when a file
+	// cannot be read as its format at all
+	ErrMalformedFile = errors.New("malformed import file")
+)
+
+// Row is one recipe read from a file
+type Row struct {
+	// Line is the line of a CSV file the recipe starts on; it is 0 for
+	// JSON formats
+	Line    int
+	Details entity.RecipeDetails
+	// Problems lists the fields that could not be read, such as an amount
+	// that is not a number. Their paths match those of validation errors.
+	Problems []entity.FieldError
+}
+
+// ParseFormat parses a format name, accepting "json-ld" for JSONLD
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case FormatJSON, FormatCSV, FormatJSONLD:
+		return f, nil
+	case "json-ld":
+		return FormatJSONLD, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// DetectFormat guesses the format of a file from its name and content: a
+// .csv file is CSV, and JSON mentioning schema.org types is JSON-LD
+func DetectFormat(filename string, data []byte) Format {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonld":
+		return FormatJSONLD
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return FormatCSV
+	}
+	if bytes.Contains(trimmed, []byte(`"@type"`)) || bytes.Contains(trimmed, []byte(`"@graph"`)) {
+		return FormatJSONLD
+	}
+	return FormatJSON
+}
+
+// Decode reads every recipe in a file of the given format
+func Decode(format Format, data []byte) ([]Row, error) {
+	switch format {
+	case FormatJSON:
+		return decodeJSON(data)
+	case FormatCSV:
+		return decodeCSV(data)
+	case FormatJSONLD:
+		return decodeJSONLD(data)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// malformed wraps the reason a file cannot be read in ErrMalformedFile
+func malformed(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrMalformedFile, fmt.Sprintf(format, args...))
+}
+
+// problems collects the field problems of one row
+type problems []entity.FieldError
+
+func (p *problems) add(field, format string, args ...interface{}) {
+	*p = append(*p, entity.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// splitSteps splits instructions written as one text into steps, one per
+// line
+func splitSteps(text string) []string {
+	var steps []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			steps = append(steps, line)
+		}
+	}
+	return steps
+}
+
+// splitList splits a comma-separated list, dropping blank items
+func splitList(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseIngredientLines reads ingredients written one per line of free text
+func parseIngredientLines(lines []string) []entity.Ingredient {
+	var ingredients []entity.Ingredient
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		ingredients = append(ingredients, entity.ParseIngredientLine(line))
+	}
+	return ingredients
+}
+
+// isJSONString reports whether raw is a JSON string
+func isJSONString(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '"'
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyRepository defines the interface for API key data access. Keys are
+// looked up by the hash of the secret, never the secret itself.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entity.APIKey) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*entity.APIKey, error)
+	// FindByUser lists a user's keys, newest first
+	FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.APIKey, error)
+	// TouchLastUsed records that a key was used at the given time
+	TouchLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+)
+
+// AutocompleteField is a free-text recipe field whose values already in use
+// are offered as suggestions
+type AutocompleteField string
+
+const (
+	AutocompleteIngredient AutocompleteField = "ingredient"
+	AutocompleteGlass      AutocompleteField = "glass"
+	AutocompleteGarnish    AutocompleteField = "garnish"
+	AutocompleteUnit       AutocompleteField = "unit"
+	AutocompleteName       AutocompleteField = "name"
+)
+
+// AutocompleteFields lists every field that can be autocompleted
+var AutocompleteFields = []AutocompleteField{
+	AutocompleteIngredient, AutocompleteGlass, AutocompleteGarnish, AutocompleteUnit, AutocompleteName,
+}
+
+// Valid reports whether f is a field that can be autocompleted
+func (f AutocompleteField) Valid() bool {
+	for _, field := range AutocompleteFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggestion is a value in use for an autocompleted field and how many
+// times visible recipes use it
+type Suggestion struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// NewSuggestions ranks the counts of a field's values, most used first with
+// ties by value, keeping at most limit of them. Values that differ only in
+// case or surrounding space count as one, shown as their most used spelling.
+func NewSuggestions(counts map[string]int64, limit int) []Suggestion {
+	type spelling struct {
+		value string
+		count int64
+	}
+	merged := make(map[string]*Suggestion)
+	best := make(map[string]spelling)
+	for value, count := range counts {
+		value = strings.TrimSpace(value)
+		if value == "" || count <= 0 {
+			continue
+		}
+		key := strings.ToLower(value)
+		s, ok := merged[key]
+		if !ok {
+			s = &Suggestion{}
+			merged[key] = s
+		}
+		s.Count += count
+		if b, ok := best[key]; !ok || count > b.count || (count == b.count && value < b.value) {
+			best[key] = spelling{value, count}
+		}
+	}
+
+	suggestions := make([]Suggestion, 0, len(merged))
+	for key, s := range merged {
+		suggestions = append(suggestions, Suggestion{Value: best[key].value, Count: s.Count})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].Value < suggestions[j].Value
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"sort"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// MaxTagFacets caps how many of the most used tags a facet count returns
+const MaxTagFacets = 50
+
+// ClassificationFilter narrows a recipe listing by how recipes are
+// classified. Empty fields do not filter. A recipe must carry every one of
+// Tags and be pronounced in every one of Flavors.
+type ClassificationFilter struct {
+	Type       entity.RecipeType
+	Tags       []string
+	BaseSpirit entity.BaseSpirit
+	Technique  entity.Technique
+	Season     entity.Season
+	Flavors    []entity.FlavorAxis
+}
+
+// WithoutBaseSpirit returns a copy of f that does not filter on base spirit
+func (f ClassificationFilter) WithoutBaseSpirit() ClassificationFilter {
+	f.BaseSpirit = ""
+	return f
+}
+
+// WithoutTechnique returns a copy of f that does not filter on technique
+func (f ClassificationFilter) WithoutTechnique() ClassificationFilter {
+	f.Technique = ""
+	return f
+}
+
+// WithoutSeason returns a copy of f that does not filter on season
+func (f ClassificationFilter) WithoutSeason() ClassificationFilter {
+	f.Season = ""
+	return f
+}
+
+// Matches reports whether recipe passes the filter
+func (f ClassificationFilter) Matches(recipe *entity.Recipe) bool {
+	if f.Type != "" && recipe.Type != f.Type {
+		return false
+	}
+	if f.BaseSpirit != "" && recipe.BaseSpirit != f.BaseSpirit {
+		return false
+	}
+	if f.Technique != "" && recipe.Technique != f.Technique {
+		return false
+	}
+	if f.Season != "" && recipe.Season != f.Season {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !hasTag(recipe, tag) {
+			return false
+		}
+	}
+	for _, axis := range f.Flavors {
+		if !recipe.Flavor.Pronounced(axis) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasTag(recipe *entity.Recipe, tag string) bool {
+	for _, t := range recipe.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// FacetCount is how many recipes share one value of a classification
+// dimension
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// RecipeFacets counts the recipes of a listing by each classification
+// dimension, most common value first with ties by value. Base spirit,
+// technique and season each hold one value per recipe, so their counts
+// ignore the filter's own constraint on that dimension and show what
+// choosing another value would return. Tags and flavors narrow the
+// listing further with every value chosen, so they count within the full
+// filter. Flavors count the recipes pronounced in each axis. Values no
+// matching recipe has are left out.
+type RecipeFacets struct {
+	Tags        []FacetCount `json:"tag"`
+	BaseSpirits []FacetCount `json:"base_spirit"`
+	Techniques  []FacetCount `json:"technique"`
+	Seasons     []FacetCount `json:"season"`
+	Flavors     []FacetCount `json:"flavor"`
+}
+
+// NewFacetCounts turns counts by value into facet counts, most common first
+// with ties by value, keeping at most limit of them when limit is positive.
+// Zero counts are dropped.
+func NewFacetCounts(counts map[string]int64, limit int) []FacetCount {
+	facets := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		if count > 0 {
+			facets = append(facets, FacetCount{Value: value, Count: count})
+		}
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	if limit > 0 && len(facets) > limit {
+		facets = facets[:limit]
+	}
+	return facets
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrCollectionConflict is returned by Update when the stored collection is
+// no longer at the version the caller read, or no longer exists
+var ErrCollectionConflict = errors.New("collection version conflict")
+
+// CollectionRepository defines the interface for recipe collection data
+// access. A collection is stored together with its entries.
+type CollectionRepository interface {
+	Create(ctx context.Context, collection *entity.Collection) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Collection, error)
+	// FindByShareToken returns the collection shared under token, if any
+	FindByShareToken(ctx context.Context, token string) (*entity.Collection, error)
+	// FindByOwner lists a user's collections, most recently updated first
+	FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]*entity.Collection, error)
+	CountByOwner(ctx context.Context, ownerID primitive.ObjectID) (int, error)
+	// Update replaces the stored collection, entries included, only if it is
+	// still at expectedVersion, returning ErrCollectionConflict otherwise
+	Update(ctx context.Context, collection *entity.Collection, expectedVersion int64) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// RemoveRecipe takes a recipe out of every collection it is in, bumping
+	// the version of each
+	RemoveRecipe(ctx context.Context, recipeID primitive.ObjectID) error
+}
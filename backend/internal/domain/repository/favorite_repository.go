@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteRepository defines the interface for favorite recipe data access
+type FavoriteRepository interface {
+	// Add bookmarks a recipe for a user. Adding a recipe that is already a
+	// favorite does nothing.
+	Add(ctx context.Context, favorite *entity.Favorite) error
+	Remove(ctx context.Context, userID, recipeID primitive.ObjectID) error
+	// FindByUser lists a user's favorites, most recently added first
+	FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.Favorite, error)
+	Exists(ctx context.Context, userID, recipeID primitive.ObjectID) (bool, error)
+	CountByUser(ctx context.Context, userID primitive.ObjectID) (int, error)
+	DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrDuplicateIngredient is returned when a catalog ingredient's name or one
+// of its aliases is already used by another catalog ingredient
+var ErrDuplicateIngredient = errors.New("ingredient name or alias already in catalog")
+
+// IngredientRepository defines the interface for ingredient catalog data
+// access. Lookups by name match names and aliases after normalizing them
+// with entity.NormalizeIngredientName.
+type IngredientRepository interface {
+	Create(ctx context.Context, ingredient *entity.CatalogIngredient) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CatalogIngredient, error)
+	// FindByNames returns the catalog ingredients whose name or alias is one
+	// of names, each at most once
+	FindByNames(ctx context.Context, names []string) ([]*entity.CatalogIngredient, error)
+	// List returns the catalog ordered by name, optionally restricted to one
+	// category
+	List(ctx context.Context, category entity.IngredientCategory) ([]*entity.CatalogIngredient, error)
+	Update(ctx context.Context, ingredient *entity.CatalogIngredient) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
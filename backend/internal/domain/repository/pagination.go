@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or was
+// issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortField is a field recipe listings can be ordered by
+type SortField string
+
+const (
+	SortByName      SortField = "name"
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+	// SortByRating orders recipes by their average review rating
+	SortByRating SortField = "rating"
+	// SortByRelevance orders search results by score, best first. It is
+	// only meaningful for Search.
+	SortByRelevance SortField = "relevance"
+)
+
+// ListOptions controls the size, position and order of a page of recipes,
+// and which moderated recipes it may contain. Hidden recipes are left out
+// unless IncludeHidden is set.
+type ListOptions struct {
+	Limit      int
+	Cursor     string
+	Sort       SortField
+	Descending bool
+	// IncludeHidden also lists recipes moderators have hidden
+	IncludeHidden bool
+	// FeaturedOnly lists only recipes moderators have featured
+	FeaturedOnly bool
+}
+
+// RecipePage is a single page of recipes
+type RecipePage struct {
+	Recipes []*entity.Recipe
+	// NextCursor is empty when there are no more results
+	NextCursor string
+	// Total is the number of recipes matching the query across all pages
+	Total int64
+}
+
+// Cursor is the decoded form of the opaque pagination token handed to
+// clients. Keyset sorts remember the sort key and ID of the last recipe
+// returned; relevance sorts, whose scores are not stable keys, remember an
+// offset instead.
+type Cursor struct {
+	Sort       SortField          `json:"s"`
+	Descending bool               `json:"d,omitempty"`
+	Name       string             `json:"n,omitempty"`
+	Time       time.Time          `json:"t,omitempty"`
+	Rating     float64            `json:"r,omitempty"`
+	ID         primitive.ObjectID `json:"i,omitempty"`
+	Offset     int                `json:"o,omitempty"`
+}
+
+// After decodes the cursor in the options, returning nil when listing
+// should start from the beginning
+func (o ListOptions) After() (*Cursor, error) {
+	if o.Cursor == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(o.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != o.Sort || c.Descending != o.Descending || c.Offset < 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// NextCursor builds the cursor pointing just past last, which was returned
+// at the given zero-based offset in the full result set
+func (o ListOptions) NextCursor(last *entity.Recipe, offset int) string {
+	c := Cursor{Sort: o.Sort, Descending: o.Descending}
+	switch o.Sort {
+	case SortByRelevance:
+		c.Offset = offset + 1
+	case SortByName:
+		c.Name = last.Name
+		c.ID = last.ID
+	case SortByRating:
+		c.Rating = last.Rating
+		c.ID = last.ID
+	default:
+		c.Time = SortTime(last, o.Sort)
+		c.ID = last.ID
+	}
+
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// SortTime returns the timestamp a recipe is ordered by for time-based sorts
+func SortTime(recipe *entity.Recipe, sort SortField) time.Time {
+	if sort == SortByUpdatedAt {
+		return recipe.UpdatedAt
+	}
+	return recipe.CreatedAt
+}
+
+// ScoredRecipe is a recipe paired with its relevance to a search
+type ScoredRecipe struct {
+	Recipe *entity.Recipe
+	Score  float64
+}
+
+// PageOf sorts matches as opts asks and returns the page of them opts points
+// at, for stores that rank or filter recipes in Go rather than in a query.
+// The page shares its recipes with matches.
+func PageOf(matches []ScoredRecipe, opts ListOptions) (*RecipePage, error) {
+	after, err := opts.After()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return compareMatches(matches[i], matches[j], opts) < 0
+	})
+
+	start := 0
+	if after != nil {
+		if opts.Sort == SortByRelevance {
+			start = after.Offset
+		} else {
+			start = sort.Search(len(matches), func(i int) bool {
+				return compareToCursor(matches[i].Recipe, after, opts) > 0
+			})
+		}
+	}
+	if start > len(matches) {
+		start = len(matches)
+	}
+
+	end := start + opts.Limit
+	if end > len(matches) {
+		end = len(matches)
+	}
+
+	page := &RecipePage{
+		Recipes: make([]*entity.Recipe, 0, end-start),
+		Total:   int64(len(matches)),
+	}
+	for _, m := range matches[start:end] {
+		page.Recipes = append(page.Recipes, m.Recipe)
+	}
+	if end < len(matches) && end > start {
+		page.NextCursor = opts.NextCursor(page.Recipes[len(page.Recipes)-1], end-1)
+	}
+	return page, nil
+}
+
+// compareMatches orders two matches by the requested sort, breaking ties on
+// ID so the order is total and stable between requests
+func compareMatches(a, b ScoredRecipe, opts ListOptions) int {
+	if opts.Sort == SortByRelevance {
+		// Highest score first, regardless of direction
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return strings.Compare(a.Recipe.ID.Hex(), b.Recipe.ID.Hex())
+	}
+
+	var c int
+	switch opts.Sort {
+	case SortByName:
+		c = strings.Compare(a.Recipe.Name, b.Recipe.Name)
+	case SortByRating:
+		c = cmp.Compare(a.Recipe.Rating, b.Recipe.Rating)
+	default:
+		c = SortTime(a.Recipe, opts.Sort).Compare(SortTime(b.Recipe, opts.Sort))
+	}
+	if c == 0 {
+		c = strings.Compare(a.Recipe.ID.Hex(), b.Recipe.ID.Hex())
+	}
+	if opts.Descending {
+		c = -c
+	}
+	return c
+}
+
+// compareToCursor reports where a recipe falls relative to a keyset cursor
+func compareToCursor(recipe *entity.Recipe, after *Cursor, opts ListOptions) int {
+	var c int
+	switch opts.Sort {
+	case SortByName:
+		c = strings.Compare(recipe.Name, after.Name)
+	case SortByRating:
+		c = cmp.Compare(recipe.Rating, after.Rating)
+	default:
+		c = SortTime(recipe, opts.Sort).Compare(after.Time)
+	}
+	if c == 0 {
+		c = strings.Compare(recipe.ID.Hex(), after.ID.Hex())
+	}
+	if opts.Descending {
+		c = -c
+	}
+	return c
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testMatches returns recipes with ascending IDs, so ties break in the order
+// they are listed here
+func testMatches() []ScoredRecipe {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recipes := []struct {
+		name   string
+		rating float64
+		score  float64
+	}{
+		{"Negroni", 4.5, 1},
+		{"Daiquiri", 4.8, 3},
+		{"Gimlet", 3.9, 2},
+		{"Martini", 4.5, 3},
+		{"Aviation", 4.1, 0.5},
+	}
+	matches := make([]ScoredRecipe, len(recipes))
+	for i, r := range recipes {
+		id := primitive.ObjectID{}
+		id[len(id)-1] = byte(i + 1)
+		matches[i] = ScoredRecipe{
+			Recipe: &entity.Recipe{
+				ID:        id,
+				Name:      r.name,
+				Rating:    r.rating,
+				CreatedAt: base.Add(time.Duration(i) * time.Hour),
+				UpdatedAt: base.Add(time.Duration(len(recipes)-i) * time.Hour),
+			},
+			Score: r.score,
+		}
+	}
+	return matches
+}
+
+// pageThrough follows NextCursor until the last page, returning the names
+// of every page
+func pageThrough(t *testing.T, opts ListOptions) [][]string {
+	t.Helper()
+	var pages [][]string
+	for {
+		page, err := PageOf(testMatches(), opts)
+		if err != nil {
+			t.Fatalf("PageOf: %v", err)
+		}
+		if page.Total != 5 {
+			t.Errorf("Total = %d, want 5", page.Total)
+		}
+		names := make([]string, len(page.Recipes))
+		for i, recipe := range page.Recipes {
+			names[i] = recipe.Name
+		}
+		pages = append(pages, names)
+		if page.NextCursor == "" {
+			return pages
+		}
+		if len(pages) > 5 {
+			t.Fatalf("still paging after %v", pages)
+		}
+		opts.Cursor = page.NextCursor
+	}
+}
+
+func TestPageOf(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ListOptions
+		want [][]string
+	}{
+		{
+			name: "name ascending",
+			opts: ListOptions{Limit: 2, Sort: SortByName},
+			want: [][]string{{"Aviation", "Daiquiri"}, {"Gimlet", "Martini"}, {"Negroni"}},
+		},
+		{
+			name: "name descending",
+			opts: ListOptions{Limit: 2, Sort: SortByName, Descending: true},
+			want: [][]string{{"Negroni", "Martini"}, {"Gimlet", "Daiquiri"}, {"Aviation"}},
+		},
+		{
+			name: "created at",
+			opts: ListOptions{Limit: 3, Sort: SortByCreatedAt},
+			want: [][]string{{"Negroni", "Daiquiri", "Gimlet"}, {"Martini", "Aviation"}},
+		},
+		{
+			name: "updated at",
+			opts: ListOptions{Limit: 3, Sort: SortByUpdatedAt},
+			want: [][]string{{"Aviation", "Martini", "Gimlet"}, {"Daiquiri", "Negroni"}},
+		},
+		{
+			name: "rating ties broken by ID",
+			opts: ListOptions{Limit: 2, Sort: SortByRating},
+			want: [][]string{{"Gimlet", "Aviation"}, {"Negroni", "Martini"}, {"Daiquiri"}},
+		},
+		{
+			name: "rating descending ties broken by ID",
+			opts: ListOptions{Limit: 2, Sort: SortByRating, Descending: true},
+			want: [][]string{{"Daiquiri", "Martini"}, {"Negroni", "Aviation"}, {"Gimlet"}},
+		},
+		{
+			name: "relevance best first whatever the direction",
+			opts: ListOptions{Limit: 2, Sort: SortByRelevance, Descending: true},
+			want: [][]string{{"Daiquiri", "Martini"}, {"Gimlet", "Negroni"}, {"Aviation"}},
+		},
+		{
+			name: "one page",
+			opts: ListOptions{Limit: 10, Sort: SortByName},
+			want: [][]string{{"Aviation", "Daiquiri", "Gimlet", "Martini", "Negroni"}},
+		},
+		{
+			name: "limit equal to the total",
+			opts: ListOptions{Limit: 5, Sort: SortByName},
+			want: [][]string{{"Aviation", "Daiquiri", "Gimlet", "Martini", "Negroni"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageThrough(t, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageOfCursorPastDeletedRecipe(t *testing.T) {
+	opts := ListOptions{Limit: 2, Sort: SortByName}
+	first, err := PageOf(testMatches(), opts)
+	if err != nil {
+		t.Fatalf("PageOf: %v", err)
+	}
+
+	// A keyset cursor still points just past the last recipe returned after
+	// that recipe is gone
+	var remaining []ScoredRecipe
+	for _, m := range testMatches() {
+		if m.Recipe.Name != "Daiquiri" {
+			remaining = append(remaining, m)
+		}
+	}
+	opts.Cursor = first.NextCursor
+	next, err := PageOf(remaining, opts)
+	if err != nil {
+		t.Fatalf("PageOf: %v", err)
+	}
+	if len(next.Recipes) == 0 || next.Recipes[0].Name != "Gimlet" {
+		t.Errorf("next page starts with %v, want Gimlet", next.Recipes)
+	}
+}
+
+func TestPageOfInvalidCursor(t *testing.T) {
+	nameCursor := ListOptions{Sort: SortByName}.NextCursor(testMatches()[0].Recipe, 0)
+
+	tests := []struct {
+		name string
+		opts ListOptions
+	}{
+		{"not base64", ListOptions{Limit: 2, Sort: SortByName, Cursor: "!!!"}},
+		{"not JSON", ListOptions{Limit: 2, Sort: SortByName, Cursor: "bm90IGpzb24"}},
+		{"other sort", ListOptions{Limit: 2, Sort: SortByRating, Cursor: nameCursor}},
+		{"other direction", ListOptions{Limit: 2, Sort: SortByName, Descending: true, Cursor: nameCursor}},
+		{"negative offset", ListOptions{Limit: 2, Sort: SortByRelevance, Cursor: "eyJzIjoicmVsZXZhbmNlIiwibyI6LTF9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := PageOf(testMatches(), tt.opts); err != ErrInvalidCursor {
+				t.Errorf("PageOf error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
@@ -2,18 +2,86 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/search"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrVersionConflict is returned by Update when the stored recipe is no
+// longer at the version the caller read
+var ErrVersionConflict = errors.New("recipe version conflict")
+
+// MaxSearchCandidates caps how many recipes a search ranks. Stores rank the
+// recipes sharing the most trigrams with the query.
+const MaxSearchCandidates = 1000
+
+// MakeableRecipe is a recipe paired with the required ingredients that are
+// missing from a given set of available ingredients
+type MakeableRecipe struct {
+	Recipe  *entity.Recipe
+	Missing []string
+}
+
+// IngredientMatch selects recipes with at least one ingredient whose name
+// contains one of Names, ignoring case, or that links to one of CatalogIDs
+type IngredientMatch struct {
+	Names      []string
+	CatalogIDs []primitive.ObjectID
+}
+
+// AvailableIngredients is what a makeable query has on hand: normalized
+// ingredient names and the catalog ingredients they resolve to
+type AvailableIngredients struct {
+	Names      []string
+	CatalogIDs []primitive.ObjectID
+}
+
+// SearchFilter narrows a search beyond its text query. Nil fields do not
+// filter. The ABV bounds are inclusive and match only recipes with an
+// estimated ABV.
+type SearchFilter struct {
+	Type   *entity.RecipeType
+	MinABV *float64
+	MaxABV *float64
+}
+
 // RecipeRepository defines the interface for recipe data access
 type RecipeRepository interface {
 	Create(ctx context.Context, recipe *entity.Recipe) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Recipe, error)
-	FindByType(ctx context.Context, recipeType entity.RecipeType) ([]*entity.Recipe, error)
-	FindByIngredient(ctx context.Context, ingredient string) ([]*entity.Recipe, error)
-	Update(ctx context.Context, recipe *entity.Recipe) error
-	Delete(ctx context.Context, id primitive.ObjectID) error
-	Search(ctx context.Context, query string, recipeType *entity.RecipeType) ([]*entity.Recipe, error)
-} 
\ No newline at end of file
+	// FindByIDs returns the recipes with the given IDs in no particular
+	// order. IDs with no recipe are skipped.
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entity.Recipe, error)
+	// FindByClassification lists the recipes that pass filter
+	FindByClassification(ctx context.Context, filter ClassificationFilter, opts ListOptions) (*RecipePage, error)
+	// Facets counts the recipes that pass filter by each classification
+	// dimension. Only the moderation options of opts apply.
+	Facets(ctx context.Context, filter ClassificationFilter, opts ListOptions) (*RecipeFacets, error)
+	// CountValues counts how often visible recipes use each value of field
+	// that begins with prefix, ignoring case. Empty values are left out.
+	CountValues(ctx context.Context, field AutocompleteField, prefix string) (map[string]int64, error)
+	FindByIngredient(ctx context.Context, match IngredientMatch, opts ListOptions) (*RecipePage, error)
+	FindForks(ctx context.Context, parentID primitive.ObjectID, opts ListOptions) (*RecipePage, error)
+	FindByCreator(ctx context.Context, creatorID primitive.ObjectID, opts ListOptions) (*RecipePage, error)
+	// Update replaces the stored recipe only if it is still at
+	// expectedVersion, returning ErrVersionConflict otherwise. The rating is
+	// left as stored; only UpdateRating changes it.
+	Update(ctx context.Context, recipe *entity.Recipe, expectedVersion int64) error
+	// UpdateRating sets a recipe's average rating and review count. The
+	// rating is part of what clients see, so the version is bumped too.
+	UpdateRating(ctx context.Context, id primitive.ObjectID, rating float64, count int) error
+	// Delete removes the recipe only if it is still at expectedVersion,
+	// returning ErrVersionConflict otherwise
+	Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error
+	// Search matches recipes against a free-text query and filter. An empty
+	// query matches every recipe the filter accepts. Otherwise recipes are
+	// scored with search.Score, leaving out those scoring 0, from at most
+	// MaxSearchCandidates found through search.IndexTrigrams.
+	Search(ctx context.Context, query search.Query, filter SearchFilter, opts ListOptions) (*RecipePage, error)
+	// FindMakeable returns up to limit recipes missing at most maxMissing
+	// non-optional ingredients, fewest missing first. An ingredient is
+	// available when its normalized name or its catalog link is.
+	FindMakeable(ctx context.Context, available AvailableIngredients, maxMissing, limit int) ([]*MakeableRecipe, error)
+}
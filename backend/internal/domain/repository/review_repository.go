@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrDuplicateReview is returned when a user already has a review of the
+// recipe
+var ErrDuplicateReview = errors.New("recipe already reviewed by this user")
+
+// ReviewRepository defines the interface for recipe review data access
+type ReviewRepository interface {
+	Create(ctx context.Context, review *entity.Review) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Review, error)
+	// FindByRecipeAndUser returns the user's review of the recipe, if any
+	FindByRecipeAndUser(ctx context.Context, recipeID, userID primitive.ObjectID) (*entity.Review, error)
+	// FindByRecipe returns every review of a recipe, newest first
+	FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Review, error)
+	Update(ctx context.Context, review *entity.Review) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error
+	// Summarize returns the average rating and number of reviews of a
+	// recipe; the average is zero when there are none
+	Summarize(ctx context.Context, recipeID primitive.ObjectID) (average float64, count int, err error)
+}
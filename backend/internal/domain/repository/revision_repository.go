@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrDuplicateRevision is returned when the recipe already has a revision
+// with the same number
+var ErrDuplicateRevision = errors.New("revision number already exists for recipe")
+
+// RevisionRepository defines the interface for recipe revision data access.
+// Revisions are append-only; there is deliberately no Update.
+type RevisionRepository interface {
+	// Create fails with ErrDuplicateRevision when the number is taken
+	Create(ctx context.Context, revision *entity.Revision) error
+	// FindByRecipe returns every revision of a recipe, newest first
+	FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Revision, error)
+	FindByNumber(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error)
+	// FindBefore returns the newest revision numbered below number, or nil
+	FindBefore(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error)
+	FindLatest(ctx context.Context, recipeID primitive.ObjectID) (*entity.Revision, error)
+	DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error
+}
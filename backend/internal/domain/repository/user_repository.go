@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrDuplicateEmail is returned when another account already uses an email
+// address
+var ErrDuplicateEmail = errors.New("email already registered")
+
+// UserRepository defines the interface for user account data access. Emails
+// are stored normalized with entity.NormalizeEmail and are unique.
+type UserRepository interface {
+	Create(ctx context.Context, user *entity.User) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*entity.User, error)
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) error
+}
@@ -0,0 +1,126 @@
+// Package search matches recipes against free-text queries. Text is split
+// into words that are folded (lower-cased, accents removed) and stemmed, so
+// "Limes" and "lime" or "Crème" and "creme" are the same word; words then
+// match exactly, by prefix or within a few typos. Stores narrow a search to
+// candidate recipes sharing trigrams with the query and rank those with
+// Score.
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// token is one word of a text with its position, so matches can be
+// highlighted in the original
+type token struct {
+	// word is the folded form of the word and stem its stem
+	word, stem string
+	// start and end are byte offsets into the text
+	start, end int
+}
+
+// tokenize splits text into words of letters and digits
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+	return tokens
+}
+
+func newToken(text string, start, end int) token {
+	word := fold(text[start:end])
+	return token{word: word, stem: Stem(word), start: start, end: end}
+}
+
+// fold lower-cases a word and strips its accents
+func fold(word string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(word) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// Stem reduces a folded English word to a stem shared by its inflections,
+// so "cherries" and "cherry", "stirred" and "stir" or "limes" and "lime"
+// compare equal. It is a light suffix-stripping stemmer suited to the short
+// words of recipes rather than a full Porter stemmer; stems are only ever
+// compared with other stems.
+func Stem(word string) string {
+	if utf8.RuneCountInString(word) <= 3 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		word = strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"),
+		strings.HasSuffix(word, "shes"), strings.HasSuffix(word, "zes"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = strings.TrimSuffix(word, "s")
+	}
+
+	for _, suffix := range []string{"ing", "ed"} {
+		if rest := strings.TrimSuffix(word, suffix); rest != word && len(rest) >= 3 && hasVowel(rest) {
+			word = undouble(rest)
+			break
+		}
+	}
+
+	if len(word) > 3 && strings.HasSuffix(word, "e") {
+		word = strings.TrimSuffix(word, "e")
+	}
+	return word
+}
+
+func hasVowel(s string) bool {
+	return strings.ContainsAny(s, "aeiouy")
+}
+
+// undouble drops the second of a doubled final consonant left behind by a
+// suffix, as in "stirr" from "stirring". Doubled l, s and z are kept since
+// they are usually part of the word ("chilled", "fizzing").
+func undouble(s string) string {
+	n := len(s)
+	if n >= 2 && s[n-1] == s[n-2] && !strings.ContainsRune("aeiouylsz", rune(s[n-1])) {
+		return s[:n-1]
+	}
+	return s
+}
+
+// trigrams returns the three-character sequences of a stem, padded with $ at
+// both ends so short words and word starts and ends have trigrams of their
+// own: "gin" gives "$gi", "gin" and "in$".
+func trigrams(stem string) []string {
+	runes := []rune("$" + stem + "$")
+	if len(runes) < 3 {
+		return nil
+	}
+	grams := make([]string, 0, len(runes)-2)
+	for i := 0; i+3 <= len(runes); i++ {
+		grams = append(grams, string(runes[i:i+3]))
+	}
+	return grams
+}
@@ -0,0 +1,92 @@
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+const (
+	// MaxHighlights caps how many fields of one recipe are highlighted
+	MaxHighlights = 5
+	// snippetBytes is about how much of a long field a snippet shows
+	snippetBytes = 160
+	// snippetLead is how many words before the first match a snippet of a
+	// long field starts at
+	snippetLead = 4
+)
+
+// Highlight shows where a recipe matched a query. Snippet is HTML: the
+// field's text is escaped and every matching word wrapped in <mark>. Long
+// fields are cut to the part around the first match, with an ellipsis
+// where text was left out. Terms are the query words that matched.
+type Highlight struct {
+	Field   string   `json:"field"`
+	Snippet string   `json:"snippet"`
+	Terms   []string `json:"terms"`
+}
+
+// Highlights returns the fields of a recipe that matched a query, most
+// important first: the name, then ingredients, then the description
+func Highlights(recipe *entity.Recipe, q Query) []Highlight {
+	_, matches := match(recipe, q)
+	if len(matches) > MaxHighlights {
+		matches = matches[:MaxHighlights]
+	}
+	highlights := make([]Highlight, 0, len(matches))
+	for _, m := range matches {
+		highlights = append(highlights, Highlight{
+			Field:   m.path,
+			Snippet: snippet(m),
+			Terms:   m.terms,
+		})
+	}
+	return highlights
+}
+
+// snippet marks the matching words of a field, cutting long text down to a
+// window that starts a few words before the first match
+func snippet(m fieldMatch) string {
+	hits := append([]int(nil), m.hits...)
+	sort.Ints(hits)
+
+	first, last := 0, len(m.tokens)-1
+	if len(m.text) > snippetBytes {
+		first = max(hits[0]-snippetLead, 0)
+		last = first
+		for last+1 < len(m.tokens) && m.tokens[last+1].end-m.tokens[first].start <= snippetBytes {
+			last++
+		}
+	}
+	start, end := 0, len(m.text)
+	if first > 0 {
+		start = m.tokens[first].start
+	}
+	if last < len(m.tokens)-1 {
+		end = m.tokens[last].end
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for i, hit := range hits {
+		if hit < first || hit > last || (i > 0 && hit == hits[i-1]) {
+			continue
+		}
+		tok := m.tokens[hit]
+		b.WriteString(html.EscapeString(m.text[pos:tok.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(m.text[tok.start:tok.end]))
+		b.WriteString("</mark>")
+		pos = tok.end
+	}
+	b.WriteString(html.EscapeString(m.text[pos:end]))
+	if end < len(m.text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package search
+
+import "unicode/utf8"
+
+// MaxQueryTerms caps how many words of a query are matched; the rest are
+// ignored
+const MaxQueryTerms = 10
+
+// stopWords are left out of queries since nearly every recipe has them
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "with": true,
+	"in": true, "on": true, "or": true, "for": true, "to": true,
+}
+
+// Term is one word of a query
+type Term struct {
+	// Word is the folded word as typed and Stem its stem
+	Word, Stem string
+	// MaxEdits is how many typos a word may have and still match the term
+	MaxEdits int
+}
+
+// Query is a parsed free-text query. Its words are plain text: nothing in
+// a query is ever interpreted as query syntax.
+type Query struct {
+	Terms []Term
+}
+
+// ParseQuery splits text into the distinct words to match, dropping stop
+// words unless the query has nothing else
+func ParseQuery(text string) Query {
+	var q Query
+	var stops []Term
+	seen := make(map[string]bool)
+	for _, tok := range tokenize(text) {
+		if seen[tok.stem] {
+			continue
+		}
+		seen[tok.stem] = true
+		term := Term{Word: tok.word, Stem: tok.stem, MaxEdits: maxEdits(tok.stem)}
+		if stopWords[tok.word] {
+			stops = append(stops, term)
+			continue
+		}
+		q.Terms = append(q.Terms, term)
+	}
+	if len(q.Terms) == 0 {
+		q.Terms = stops
+	}
+	if len(q.Terms) > MaxQueryTerms {
+		q.Terms = q.Terms[:MaxQueryTerms]
+	}
+	return q
+}
+
+// Stems returns the distinct stems of the words of text, leaving out stop
+// words, for comparing texts by the words they share
+func Stems(text string) []string {
+	var stems []string
+	seen := make(map[string]bool)
+	for _, tok := range tokenize(text) {
+		if stopWords[tok.word] || seen[tok.stem] {
+			continue
+		}
+		seen[tok.stem] = true
+		stems = append(stems, tok.stem)
+	}
+	return stems
+}
+
+// maxEdits allows one typo in words of four to six characters and two in
+// longer ones. Shorter words must match exactly, since one edit turns "gin"
+// into too many other words.
+func maxEdits(stem string) int {
+	switch n := utf8.RuneCountInString(stem); {
+	case n >= 7:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// Empty reports whether the query has no words to match
+func (q Query) Empty() bool {
+	return len(q.Terms) == 0
+}
+
+// Trigrams returns the distinct trigrams of the query's words, which stores
+// look up in IndexTrigrams to find candidate recipes. A recipe matching the
+// query shares at least one of them unless every typo'd word it matches is
+// so short, or so misspelled, that no trigram survives, as when the middle
+// letters of a four-letter word are swapped.
+func (q Query) Trigrams() []string {
+	var grams []string
+	seen := make(map[string]bool)
+	for _, term := range q.Terms {
+		for _, g := range trigrams(term.Stem) {
+			if !seen[g] {
+				seen[g] = true
+				grams = append(grams, g)
+			}
+		}
+	}
+	return grams
+}
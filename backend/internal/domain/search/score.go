@@ -0,0 +1,193 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// Field boosts weigh a match by where it was found: a word in a recipe's
+// name says more about it than one in an ingredient, which says more than
+// one in the description.
+const (
+	NameBoost        = 3.0
+	IngredientBoost  = 2.0
+	DescriptionBoost = 1.0
+)
+
+// How closely a word must match a term, and how much each kind of match is
+// worth relative to an exact one
+const (
+	exactWeight  = 1.0
+	prefixWeight = 0.8
+	// minPrefixLength is the shortest term that matches words it begins,
+	// so "negr" finds "negroni" but "ne" does not find "nectar"
+	minPrefixLength = 3
+)
+
+// editWeights is what a fuzzy match is worth by its number of edits
+var editWeights = []float64{exactWeight, 0.7, 0.4}
+
+// field is one searchable text of a recipe. path names it the way the API
+// names the field, such as "ingredients[2].name".
+type field struct {
+	path   string
+	text   string
+	boost  float64
+	tokens []token
+}
+
+// fields returns the searchable texts of a recipe in the order they are
+// highlighted
+func fields(recipe *entity.Recipe) []field {
+	fs := []field{{path: "name", text: recipe.Name, boost: NameBoost}}
+	for i, ing := range recipe.Ingredients {
+		fs = append(fs, field{path: fmt.Sprintf("ingredients[%d].name", i), text: ing.Name, boost: IngredientBoost})
+	}
+	fs = append(fs, field{path: "description", text: recipe.Description, boost: DescriptionBoost})
+	for i := range fs {
+		fs[i].tokens = tokenize(fs[i].text)
+	}
+	return fs
+}
+
+// IndexTrigrams returns the distinct trigrams of every searchable word of a
+// recipe, for stores to index candidates by
+func IndexTrigrams(recipe *entity.Recipe) []string {
+	var grams []string
+	seen := make(map[string]bool)
+	for _, f := range fields(recipe) {
+		for _, tok := range f.tokens {
+			for _, g := range trigrams(tok.stem) {
+				if !seen[g] {
+					seen[g] = true
+					grams = append(grams, g)
+				}
+			}
+		}
+	}
+	return grams
+}
+
+// similarity rates how well a word matches a term, from 0 for no match to 1
+// for the same stem
+func similarity(term Term, tok token) float64 {
+	if tok.stem == term.Stem {
+		return exactWeight
+	}
+	best := 0.0
+	if len(term.Word) >= minPrefixLength && strings.HasPrefix(tok.word, term.Word) {
+		best = prefixWeight
+	}
+	if term.MaxEdits > 0 {
+		if d := editDistance(term.Stem, tok.stem, term.MaxEdits); d <= term.MaxEdits && editWeights[d] > best {
+			best = editWeights[d]
+		}
+	}
+	return best
+}
+
+// fieldMatch is a field with the words in it that matched the query
+type fieldMatch struct {
+	field
+	// hits holds the index of every matching token
+	hits []int
+	// terms holds the query words that matched, in query order
+	terms []string
+}
+
+// match finds every field of recipe in which a query term matches and
+// scores the recipe. Each term contributes its best match across fields,
+// weighted by the field's boost and down by its length so a name that is
+// just "Negroni" outranks "Negroni Sbagliato". A score of 0 means the
+// recipe does not match.
+func match(recipe *entity.Recipe, q Query) (float64, []fieldMatch) {
+	best := make([]float64, len(q.Terms))
+	var matches []fieldMatch
+	for _, f := range fields(recipe) {
+		if len(f.tokens) == 0 {
+			continue
+		}
+		lengthNorm := 0.5 + 0.5/float64(len(f.tokens))
+		fm := fieldMatch{field: f}
+		for ti, term := range q.Terms {
+			termHit := false
+			for i, tok := range f.tokens {
+				sim := similarity(term, tok)
+				if sim == 0 {
+					continue
+				}
+				if !termHit {
+					termHit = true
+					fm.terms = append(fm.terms, term.Word)
+				}
+				fm.hits = append(fm.hits, i)
+				if score := sim * f.boost * lengthNorm; score > best[ti] {
+					best[ti] = score
+				}
+			}
+		}
+		if len(fm.hits) > 0 {
+			matches = append(matches, fm)
+		}
+	}
+
+	var score float64
+	for _, s := range best {
+		score += s
+	}
+	return score, matches
+}
+
+// Score rates how well a recipe matches a query; 0 means it does not match
+func Score(recipe *entity.Recipe, q Query) float64 {
+	score, _ := match(recipe, q)
+	return score
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b, counting insertions, deletions, substitutions and swaps of adjacent
+// characters as one edit each. It gives up once the distance must exceed
+// limit and returns limit+1.
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	// prev2, prev and cur are three rows of the distance matrix
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return min(prev[len(rb)], limit+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
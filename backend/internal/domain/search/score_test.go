@@ -0,0 +1,170 @@
+package search
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+func newRecipe(name, description string, ingredients ...string) *entity.Recipe {
+	recipe := &entity.Recipe{Name: name, Description: description}
+	for _, ing := range ingredients {
+		recipe.Ingredients = append(recipe.Ingredients, entity.Ingredient{Name: ing})
+	}
+	return recipe
+}
+
+func TestScoreMatches(t *testing.T) {
+	negroni := newRecipe("Negroni", "A bitter Italian aperitivo stirred over ice.",
+		"Gin", "Campari", "Sweet vermouth")
+	tests := []struct {
+		query string
+		match bool
+	}{
+		{"negroni", true},
+		{"NEGRONI", true},
+		{"negr", true},      // prefix of at least three letters
+		{"ne", false},       // too short to match by prefix
+		{"negorni", true},   // swapped letters
+		{"negronni", true},  // one typo
+		{"vermouths", true}, // stemmed plural
+		{"stir", true},      // "stirred" stems to "stir"
+		{"italián", true},   // accents are folded
+		{"margarita", false},
+		{"the", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			score := Score(negroni, ParseQuery(tt.query))
+			if (score > 0) != tt.match {
+				t.Errorf("Score(%q) = %v, want match %v", tt.query, score, tt.match)
+			}
+		})
+	}
+}
+
+func TestScoreRanking(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		better, worse *entity.Recipe
+	}{
+		{
+			name:   "name outranks ingredient",
+			query:  "gin",
+			better: newRecipe("Gin", ""),
+			worse:  newRecipe("Tonic", "", "Gin"),
+		},
+		{
+			name:   "ingredient outranks description",
+			query:  "lime",
+			better: newRecipe("Daiquiri", "", "Lime"),
+			worse:  newRecipe("Daiquiri", "Lime"),
+		},
+		{
+			name:   "shorter field outranks longer",
+			query:  "negroni",
+			better: newRecipe("Negroni", ""),
+			worse:  newRecipe("Negroni Sbagliato", ""),
+		},
+		{
+			name:   "exact outranks prefix",
+			query:  "gin",
+			better: newRecipe("Gin", ""),
+			worse:  newRecipe("Ginger", ""),
+		},
+		{
+			name:   "prefix outranks typo",
+			query:  "campa",
+			better: newRecipe("Campari", ""),
+			worse:  newRecipe("Canpa", ""),
+		},
+		{
+			name:   "more terms matched outranks fewer",
+			query:  "gin lime",
+			better: newRecipe("Gimlet", "", "Gin", "Lime"),
+			worse:  newRecipe("Martini", "", "Gin", "Vermouth"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ParseQuery(tt.query)
+			better, worse := Score(tt.better, q), Score(tt.worse, q)
+			if better <= worse {
+				t.Errorf("Score(%q) = %v for %q, want more than %v for %q",
+					tt.query, better, tt.better.Name, worse, tt.worse.Name)
+			}
+		})
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	tests := []struct {
+		name   string
+		recipe *entity.Recipe
+		query  string
+		want   []Highlight
+	}{
+		{
+			name:   "fields in order with matched terms",
+			recipe: newRecipe("Gin Sour", "A sour with gin.", "Gin", "Lemon juice"),
+			query:  "gin sour",
+			want: []Highlight{
+				{Field: "name", Snippet: "<mark>Gin</mark> <mark>Sour</mark>", Terms: []string{"gin", "sour"}},
+				{Field: "ingredients[0].name", Snippet: "<mark>Gin</mark>", Terms: []string{"gin"}},
+				{Field: "description", Snippet: "A <mark>sour</mark> with <mark>gin</mark>.", Terms: []string{"gin", "sour"}},
+			},
+		},
+		{
+			name:   "text is escaped",
+			recipe: newRecipe("Rum & Coke", "", "Rum"),
+			query:  "coke",
+			want: []Highlight{
+				{Field: "name", Snippet: "Rum &amp; <mark>Coke</mark>", Terms: []string{"coke"}},
+			},
+		},
+		{
+			name:   "no match",
+			recipe: newRecipe("Negroni", ""),
+			query:  "margarita",
+			want:   []Highlight{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Highlights(tt.recipe, ParseQuery(tt.query))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Highlights(%q) =\n%+v\nwant\n%+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightsSnippetWindow(t *testing.T) {
+	description := strings.Repeat("shake ", 40) + "with a lime wheel " + strings.Repeat("and serve ", 40) + "cold"
+	got := Highlights(newRecipe("Daiquiri", description), ParseQuery("lime"))
+	if len(got) != 1 {
+		t.Fatalf("Highlights = %+v, want one highlight", got)
+	}
+
+	snippet := got[0].Snippet
+	if want := "…shake shake with a <mark>lime</mark> wheel and serve"; !strings.HasPrefix(snippet, want) {
+		t.Errorf("snippet = %q, want it to start %q", snippet, want)
+	}
+	if !strings.HasSuffix(snippet, "serve…") {
+		t.Errorf("snippet = %q, want it to end with an ellipsis after a whole word", snippet)
+	}
+	text := strings.NewReplacer("…", "", "<mark>", "", "</mark>", "").Replace(snippet)
+	if len(text) > snippetBytes {
+		t.Errorf("snippet text is %d bytes, want at most %d", len(text), snippetBytes)
+	}
+}
+
+func TestHighlightsCapped(t *testing.T) {
+	recipe := newRecipe("Lime", "Lime", "Lime", "Lime", "Lime", "Lime", "Lime")
+	if got := Highlights(recipe, ParseQuery("lime")); len(got) != MaxHighlights {
+		t.Errorf("len(Highlights) = %d, want %d", len(got), MaxHighlights)
+	}
+}
@@ -0,0 +1,50 @@
+package units
+
+import "strings"
+
+// TemperatureScale is the scale a temperature is given in
+type TemperatureScale string
+
+const (
+	Celsius    TemperatureScale = "C"
+	Fahrenheit TemperatureScale = "F"
+)
+
+// ParseTemperatureScale parses "C", "F" or their spelled-out names
+func ParseTemperatureScale(s string) (TemperatureScale, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "c", "celsius":
+		return Celsius, true
+	case "f", "fahrenheit":
+		return Fahrenheit, true
+	}
+	return "", false
+}
+
+// Temperature is a cooking temperature such as an oven setting
+type Temperature struct {
+	Degrees float64          `json:"degrees" bson:"degrees"`
+	Scale   TemperatureScale `json:"scale" bson:"scale"`
+}
+
+// Celsius returns the temperature in degrees Celsius
+func (t Temperature) Celsius() float64 {
+	if t.Scale == Fahrenheit {
+		return (t.Degrees - 32) * 5 / 9
+	}
+	return t.Degrees
+}
+
+// ToSystem presents the temperature in Celsius for metric and Fahrenheit for
+// imperial, rounded to the steps oven dials use (10 °C, 25 °F) so that 180 °C
+// reads as 350 °F rather than 356 °F. A temperature already in the requested
+// scale is returned unchanged.
+func (t Temperature) ToSystem(system System) Temperature {
+	switch {
+	case system == Metric && t.Scale != Celsius:
+		return Temperature{Degrees: roundTo(t.Celsius(), 10), Scale: Celsius}
+	case system == Imperial && t.Scale != Fahrenheit:
+		return Temperature{Degrees: roundTo(t.Celsius()*9/5+32, 25), Scale: Fahrenheit}
+	}
+	return t
+}
@@ -0,0 +1,294 @@
+// Package units knows the measures used in recipes: metric and imperial
+// volumes and weights, bar-specific measures such as dashes and barspoons,
+// and countable items. It canonicalizes free-form unit strings and converts
+// amounts between units and measurement systems.
+package units
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// ErrIncompatibleUnits is returned when converting between units that do not
+// measure the same kind of quantity
+var ErrIncompatibleUnits = errors.New("incompatible units")
+
+// Dimension is the kind of quantity a unit measures
+type Dimension string
+
+const (
+	Volume Dimension = "volume"
+	Weight Dimension = "weight"
+	// Count units (pieces, wedges, sprigs...) cannot be converted
+	Count Dimension = "count"
+	// Relative units (parts) only make sense in proportion to each other
+	Relative Dimension = "relative"
+)
+
+// System is a measurement system amounts can be presented in
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+	// Bar units are specific to drink making and are never converted when
+	// presenting a recipe in another system
+	Bar System = "bar"
+)
+
+// ParseSystem parses a measurement system name
+func ParseSystem(s string) (System, bool) {
+	switch System(strings.ToLower(strings.TrimSpace(s))) {
+	case Metric:
+		return Metric, true
+	case Imperial:
+		return Imperial, true
+	}
+	return "", false
+}
+
+// Quantity is an amount together with the unit it is measured in
+type Quantity struct {
+	Amount float64 `json:"amount"`
+	Unit   string  `json:"unit"`
+}
+
+// Unit describes a known unit of measure
+type Unit struct {
+	// Symbol is the canonical spelling stored on ingredients
+	Symbol    string
+	Dimension Dimension
+	System    System
+	// Factor converts one of this unit into the dimension's base unit
+	// (millilitres for volume, grams for weight)
+	Factor  float64
+	aliases []string
+}
+
+const (
+	mlPerFluidOunce = 29.5735295625
+	gramsPerOunce   = 28.349523125
+
+	// barMeasureLimit is the volume in millilitres above which scaled bar
+	// measures are easier to pour with a jigger than to count out
+	barMeasureLimit = mlPerFluidOunce
+)
+
+var known = []Unit{
+	// Metric volume
+	{Symbol: "ml", Dimension: Volume, System: Metric, Factor: 1,
+		aliases: []string{"millilitre", "milliliter", "millilitres", "milliliters", "mls"}},
+	{Symbol: "cl", Dimension: Volume, System: Metric, Factor: 10,
+		aliases: []string{"centilitre", "centiliter", "centilitres", "centiliters"}},
+	{Symbol: "l", Dimension: Volume, System: Metric, Factor: 1000,
+		aliases: []string{"litre", "liter", "litres", "liters", "ltr"}},
+
+	// Imperial (US customary) volume
+	{Symbol: "oz", Dimension: Volume, System: Imperial, Factor: mlPerFluidOunce,
+		aliases: []string{"fl oz", "fl. oz", "fl. oz.", "floz", "ounce", "ounces", "fluid ounce", "fluid ounces", "oz."}},
+	{Symbol: "tsp", Dimension: Volume, System: Imperial, Factor: mlPerFluidOunce / 6,
+		aliases: []string{"teaspoon", "teaspoons", "tsps"}},
+	{Symbol: "tbsp", Dimension: Volume, System: Imperial, Factor: mlPerFluidOunce / 2,
+		aliases: []string{"tablespoon", "tablespoons", "tbsps", "tbs", "tbl"}},
+	{Symbol: "cup", Dimension: Volume, System: Imperial, Factor: mlPerFluidOunce * 8,
+		aliases: []string{"cups"}},
+	{Symbol: "pt", Dimension: Volume, System: Imperial, Factor: mlPerFluidOunce * 16,
+		aliases: []string{"pint", "pints"}},
+	{Symbol: "qt", Dimension: Volume, System: Imperial, Factor: mlPerFluidOunce * 32,
+		aliases: []string{"quart", "quarts"}},
+	{Symbol: "gal", Dimension: Volume, System: Imperial, Factor: mlPerFluidOunce * 128,
+		aliases: []string{"gallon", "gallons"}},
+
+	// Bar measures
+	{Symbol: "dash", Dimension: Volume, System: Bar, Factor: mlPerFluidOunce / 32,
+		aliases: []string{"dashes"}},
+	{Symbol: "drop", Dimension: Volume, System: Bar, Factor: 0.05,
+		aliases: []string{"drops"}},
+	{Symbol: "barspoon", Dimension: Volume, System: Bar, Factor: 5,
+		aliases: []string{"barspoons", "bar spoon", "bar spoons", "bsp"}},
+	{Symbol: "splash", Dimension: Volume, System: Bar, Factor: mlPerFluidOunce / 4,
+		aliases: []string{"splashes"}},
+	{Symbol: "jigger", Dimension: Volume, System: Bar, Factor: mlPerFluidOunce * 1.5,
+		aliases: []string{"jiggers"}},
+	{Symbol: "pony", Dimension: Volume, System: Bar, Factor: mlPerFluidOunce,
+		aliases: []string{"ponies"}},
+	{Symbol: "part", Dimension: Relative, System: Bar,
+		aliases: []string{"parts"}},
+
+	// Weight
+	{Symbol: "g", Dimension: Weight, System: Metric, Factor: 1,
+		aliases: []string{"gram", "grams", "gr"}},
+	{Symbol: "kg", Dimension: Weight, System: Metric, Factor: 1000,
+		aliases: []string{"kilogram", "kilograms", "kgs"}},
+	{Symbol: "oz wt", Dimension: Weight, System: Imperial, Factor: gramsPerOunce,
+		aliases: []string{"wt oz", "ounce weight", "ounces weight"}},
+	{Symbol: "lb", Dimension: Weight, System: Imperial, Factor: gramsPerOunce * 16,
+		aliases: []string{"lbs", "pound", "pounds"}},
+
+	// Countable items
+	{Symbol: "piece", Dimension: Count, aliases: []string{"pieces", "pc", "pcs", "whole"}},
+	{Symbol: "slice", Dimension: Count, aliases: []string{"slices"}},
+	{Symbol: "wedge", Dimension: Count, aliases: []string{"wedges"}},
+	{Symbol: "wheel", Dimension: Count, aliases: []string{"wheels"}},
+	{Symbol: "twist", Dimension: Count, aliases: []string{"twists"}},
+	{Symbol: "peel", Dimension: Count, aliases: []string{"peels"}},
+	{Symbol: "sprig", Dimension: Count, aliases: []string{"sprigs"}},
+	{Symbol: "leaf", Dimension: Count, aliases: []string{"leaves"}},
+	{Symbol: "cube", Dimension: Count, aliases: []string{"cubes"}},
+	{Symbol: "pinch", Dimension: Count, aliases: []string{"pinches"}},
+}
+
+var lookup = func() map[string]Unit {
+	m := make(map[string]Unit)
+	for _, u := range known {
+		m[u.Symbol] = u
+		for _, alias := range u.aliases {
+			m[alias] = u
+		}
+	}
+	return m
+}()
+
+// Lookup finds a unit by its symbol or any alias, ignoring case and
+// surrounding whitespace
+func Lookup(unit string) (Unit, bool) {
+	u, ok := lookup[clean(unit)]
+	return u, ok
+}
+
+// Normalize returns the canonical symbol for a known unit. Unknown units are
+// returned trimmed and lower-cased so equivalent spellings still compare
+// equal.
+func Normalize(unit string) string {
+	if u, ok := Lookup(unit); ok {
+		return u.Symbol
+	}
+	return clean(unit)
+}
+
+// Convert converts an amount between two units of the same dimension
+func Convert(amount float64, from, to string) (float64, error) {
+	f, ok := Lookup(from)
+	if !ok {
+		return 0, ErrIncompatibleUnits
+	}
+	t, ok := Lookup(to)
+	if !ok || f.Dimension != t.Dimension || f.Factor == 0 || t.Factor == 0 {
+		return 0, ErrIncompatibleUnits
+	}
+	return amount * f.Factor / t.Factor, nil
+}
+
+// ToSystem presents an amount in the given measurement system, choosing a
+// unit of sensible size and rounding to a precision a bartender can measure.
+// Bar, count, relative and unknown units are returned unchanged, as are
+// amounts already in the target system.
+func ToSystem(amount float64, unit string, system System) (float64, string) {
+	u, ok := Lookup(unit)
+	if !ok || u.System == system || u.System == Bar || u.Factor == 0 {
+		return amount, unit
+	}
+	if u.Dimension != Volume && u.Dimension != Weight {
+		return amount, unit
+	}
+	return Humanize(amount*u.Factor, u.Dimension, system)
+}
+
+// Scale multiplies an amount by factor and expresses the result the way it
+// would be measured. Volumes and weights move up to larger units as they grow
+// (48 oz becomes 1.5 qt) and are given in system, or in the unit's own system
+// when system is empty. Bar measures are kept until they add up to more than
+// an ounce, with dashes and drops rounded to whole counts. Countable items are
+// rounded up, parts keep their proportions and are not scaled at all, and
+// unknown units are simply multiplied.
+func Scale(amount float64, unit string, factor float64, system System) (float64, string) {
+	u, ok := Lookup(unit)
+	if !ok {
+		return roundTo(amount*factor, 0.01), unit
+	}
+
+	scaled := amount * factor
+	switch {
+	case u.Dimension == Relative:
+		return amount, unit
+	case u.Dimension == Count:
+		// Allow for floating point noise so 2 wedges times 1 stays 2
+		return math.Ceil(scaled - 1e-9), unit
+	case u.System == Bar:
+		base := scaled * u.Factor
+		if base <= barMeasureLimit {
+			step := 0.5
+			if u.Symbol == "dash" || u.Symbol == "drop" {
+				step = 1
+			}
+			return roundTo(scaled, step), unit
+		}
+		if system == "" {
+			system = Imperial
+		}
+		return Humanize(base, u.Dimension, system)
+	}
+
+	if system == "" {
+		system = u.System
+	}
+	return Humanize(scaled*u.Factor, u.Dimension, system)
+}
+
+// Humanize expresses a quantity given in the dimension's base unit
+// (millilitres or grams) in the most readable unit of the target system
+func Humanize(base float64, dim Dimension, system System) (float64, string) {
+	switch {
+	case dim == Volume && system == Metric:
+		if base >= 1000 {
+			return roundTo(base/1000, 0.01), "l"
+		}
+		return roundMillilitres(base), "ml"
+	case dim == Volume && system == Imperial:
+		oz := base / mlPerFluidOunce
+		switch {
+		case oz >= 128:
+			return roundTo(oz/128, 0.05), "gal"
+		case oz >= 32:
+			return roundTo(oz/32, 0.05), "qt"
+		case oz < 0.25:
+			return roundTo(oz*6, 0.25), "tsp"
+		}
+		return roundTo(oz, 0.125), "oz"
+	case dim == Weight && system == Metric:
+		if base >= 1000 {
+			return roundTo(base/1000, 0.01), "kg"
+		}
+		return roundTo(base, 1), "g"
+	case dim == Weight && system == Imperial:
+		oz := base / gramsPerOunce
+		if oz >= 16 {
+			return roundTo(oz/16, 0.05), "lb"
+		}
+		return roundTo(oz, 0.25), "oz wt"
+	}
+	return base, ""
+}
+
+// roundMillilitres rounds to the nearest half millilitre for small measures
+// and the nearest 2.5 ml for pours, which matches common jigger markings
+func roundMillilitres(ml float64) float64 {
+	if ml < 10 {
+		return roundTo(ml, 0.5)
+	}
+	return roundTo(ml, 2.5)
+}
+
+func roundTo(v, step float64) float64 {
+	r := math.Round(v/step) * step
+	if r == 0 && v > 0 {
+		return step
+	}
+	// Trim floating point noise such as 0.30000000000000004
+	return math.Round(r*1000) / 1000
+}
+
+func clean(unit string) string {
+	return strings.Join(strings.Fields(strings.ToLower(unit)), " ")
+}
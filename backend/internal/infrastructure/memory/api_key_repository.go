@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyRepository implements the domain.APIKeyRepository interface on top
+// of an in-process map. It is safe for concurrent use.
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[primitive.ObjectID]*entity.APIKey
+}
+
+// NewAPIKeyRepository creates a new, empty APIKeyRepository
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make(map[primitive.ObjectID]*entity.APIKey),
+	}
+}
+
+// Create implements APIKeyRepository.Create
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+	r.keys[key.ID] = cloneAPIKey(key)
+	return nil
+}
+
+// FindByID implements APIKeyRepository.FindByID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneAPIKey(key), nil
+}
+
+// FindByHash implements APIKeyRepository.FindByHash
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Hash == hash {
+			return cloneAPIKey(key), nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByUser implements APIKeyRepository.FindByUser
+func (r *APIKeyRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []*entity.APIKey{}
+	for _, key := range r.keys {
+		if key.UserID == userID {
+			keys = append(keys, cloneAPIKey(key))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.After(keys[j].CreatedAt)
+		}
+		return keys[i].ID.Hex() > keys[j].ID.Hex()
+	})
+	return keys, nil
+}
+
+// TouchLastUsed implements APIKeyRepository.TouchLastUsed
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if key, ok := r.keys[id]; ok {
+		key.LastUsedAt = &at
+	}
+	return nil
+}
+
+// Delete implements APIKeyRepository.Delete
+func (r *APIKeyRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.keys, id)
+	return nil
+}
+
+func cloneAPIKey(key *entity.APIKey) *entity.APIKey {
+	c := *key
+	if key.ExpiresAt != nil {
+		expiresAt := *key.ExpiresAt
+		c.ExpiresAt = &expiresAt
+	}
+	if key.LastUsedAt != nil {
+		lastUsedAt := *key.LastUsedAt
+		c.LastUsedAt = &lastUsedAt
+	}
+	return &c
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CollectionRepository implements the domain.CollectionRepository interface
+// on top of an in-process map. It is safe for concurrent use.
+type CollectionRepository struct {
+	mu          sync.RWMutex
+	collections map[primitive.ObjectID]*entity.Collection
+}
+
+// NewCollectionRepository creates a new, empty CollectionRepository
+func NewCollectionRepository() *CollectionRepository {
+	return &CollectionRepository{
+		collections: make(map[primitive.ObjectID]*entity.Collection),
+	}
+}
+
+// Create implements CollectionRepository.Create
+func (r *CollectionRepository) Create(ctx context.Context, collection *entity.Collection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if collection.ID.IsZero() {
+		collection.ID = primitive.NewObjectID()
+	}
+	r.collections[collection.ID] = cloneCollection(collection)
+	return nil
+}
+
+// FindByID implements CollectionRepository.FindByID
+func (r *CollectionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, ok := r.collections[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneCollection(collection), nil
+}
+
+// FindByShareToken implements CollectionRepository.FindByShareToken
+func (r *CollectionRepository) FindByShareToken(ctx context.Context, token string) (*entity.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if token == "" {
+		return nil, nil
+	}
+	for _, collection := range r.collections {
+		if collection.ShareToken == token {
+			return cloneCollection(collection), nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByOwner implements CollectionRepository.FindByOwner
+func (r *CollectionRepository) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]*entity.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collections := []*entity.Collection{}
+	for _, collection := range r.collections {
+		if collection.OwnerID == ownerID {
+			collections = append(collections, cloneCollection(collection))
+		}
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		if !collections[i].UpdatedAt.Equal(collections[j].UpdatedAt) {
+			return collections[i].UpdatedAt.After(collections[j].UpdatedAt)
+		}
+		return collections[i].ID.Hex() > collections[j].ID.Hex()
+	})
+	return collections, nil
+}
+
+// CountByOwner implements CollectionRepository.CountByOwner
+func (r *CollectionRepository) CountByOwner(ctx context.Context, ownerID primitive.ObjectID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, collection := range r.collections {
+		if collection.OwnerID == ownerID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Update implements CollectionRepository.Update
+func (r *CollectionRepository) Update(ctx context.Context, collection *entity.Collection, expectedVersion int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.collections[collection.ID]
+	if !ok || stored.Version != expectedVersion {
+		return repository.ErrCollectionConflict
+	}
+	r.collections[collection.ID] = cloneCollection(collection)
+	return nil
+}
+
+// Delete implements CollectionRepository.Delete
+func (r *CollectionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.collections, id)
+	return nil
+}
+
+// RemoveRecipe implements CollectionRepository.RemoveRecipe
+func (r *CollectionRepository) RemoveRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, collection := range r.collections {
+		entries := collection.Entries[:0]
+		for _, entry := range collection.Entries {
+			if entry.RecipeID != recipeID {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) < len(collection.Entries) {
+			collection.Version++
+		}
+		collection.Entries = entries
+	}
+	return nil
+}
+
+// cloneCollection copies a collection deeply enough that callers cannot
+// reach the stored entries through it
+func cloneCollection(collection *entity.Collection) *entity.Collection {
+	c := *collection
+	c.Entries = append([]entity.CollectionEntry{}, collection.Entries...)
+	return &c
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// favoriteKey identifies a favorite; a user favorites a recipe at most once
+type favoriteKey struct {
+	userID, recipeID primitive.ObjectID
+}
+
+// FavoriteRepository implements the domain.FavoriteRepository interface on
+// top of an in-process map. It is safe for concurrent use.
+type FavoriteRepository struct {
+	mu        sync.RWMutex
+	favorites map[favoriteKey]*entity.Favorite
+}
+
+// NewFavoriteRepository creates a new, empty FavoriteRepository
+func NewFavoriteRepository() *FavoriteRepository {
+	return &FavoriteRepository{
+		favorites: make(map[favoriteKey]*entity.Favorite),
+	}
+}
+
+// Add implements FavoriteRepository.Add
+func (r *FavoriteRepository) Add(ctx context.Context, favorite *entity.Favorite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := favoriteKey{favorite.UserID, favorite.RecipeID}
+	if _, ok := r.favorites[key]; !ok {
+		c := *favorite
+		r.favorites[key] = &c
+	}
+	return nil
+}
+
+// Remove implements FavoriteRepository.Remove
+func (r *FavoriteRepository) Remove(ctx context.Context, userID, recipeID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.favorites, favoriteKey{userID, recipeID})
+	return nil
+}
+
+// FindByUser implements FavoriteRepository.FindByUser
+func (r *FavoriteRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.Favorite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	favorites := []*entity.Favorite{}
+	for key, favorite := range r.favorites {
+		if key.userID == userID {
+			c := *favorite
+			favorites = append(favorites, &c)
+		}
+	}
+	sort.Slice(favorites, func(i, j int) bool {
+		if !favorites[i].CreatedAt.Equal(favorites[j].CreatedAt) {
+			return favorites[i].CreatedAt.After(favorites[j].CreatedAt)
+		}
+		return favorites[i].RecipeID.Hex() > favorites[j].RecipeID.Hex()
+	})
+	return favorites, nil
+}
+
+// Exists implements FavoriteRepository.Exists
+func (r *FavoriteRepository) Exists(ctx context.Context, userID, recipeID primitive.ObjectID) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.favorites[favoriteKey{userID, recipeID}]
+	return ok, nil
+}
+
+// CountByUser implements FavoriteRepository.CountByUser
+func (r *FavoriteRepository) CountByUser(ctx context.Context, userID primitive.ObjectID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for key := range r.favorites {
+		if key.userID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteByRecipe implements FavoriteRepository.DeleteByRecipe
+func (r *FavoriteRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.favorites {
+		if key.recipeID == recipeID {
+			delete(r.favorites, key)
+		}
+	}
+	return nil
+}
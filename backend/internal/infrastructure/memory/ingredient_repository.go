@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IngredientRepository implements the domain.IngredientRepository interface
+// on top of an in-process map. It is safe for concurrent use.
+type IngredientRepository struct {
+	mu          sync.RWMutex
+	ingredients map[primitive.ObjectID]*entity.CatalogIngredient
+	// keys maps every normalized name and alias to the ingredient using it
+	keys map[string]primitive.ObjectID
+}
+
+// NewIngredientRepository creates a new, empty IngredientRepository
+func NewIngredientRepository() *IngredientRepository {
+	return &IngredientRepository{
+		ingredients: make(map[primitive.ObjectID]*entity.CatalogIngredient),
+		keys:        make(map[string]primitive.ObjectID),
+	}
+}
+
+// Create implements IngredientRepository.Create
+func (r *IngredientRepository) Create(ctx context.Context, ingredient *entity.CatalogIngredient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if ingredient.ID.IsZero() {
+		ingredient.ID = primitive.NewObjectID()
+	}
+	if r.keyTaken(ingredient) {
+		return repository.ErrDuplicateIngredient
+	}
+	r.store(ingredient)
+	return nil
+}
+
+// FindByID implements IngredientRepository.FindByID
+func (r *IngredientRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CatalogIngredient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ingredient, ok := r.ingredients[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneCatalogIngredient(ingredient), nil
+}
+
+// FindByNames implements IngredientRepository.FindByNames
+func (r *IngredientRepository) FindByNames(ctx context.Context, names []string) ([]*entity.CatalogIngredient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[primitive.ObjectID]bool)
+	var found []*entity.CatalogIngredient
+	for _, name := range names {
+		id, ok := r.keys[entity.NormalizeIngredientName(name)]
+		if !ok || seen[id] {
+			continue
+		}
+		seen[id] = true
+		found = append(found, cloneCatalogIngredient(r.ingredients[id]))
+	}
+	return found, nil
+}
+
+// List implements IngredientRepository.List
+func (r *IngredientRepository) List(ctx context.Context, category entity.IngredientCategory) ([]*entity.CatalogIngredient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := []*entity.CatalogIngredient{}
+	for _, ingredient := range r.ingredients {
+		if category == "" || ingredient.Category == category {
+			list = append(list, cloneCatalogIngredient(ingredient))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].ID.Hex() < list[j].ID.Hex()
+	})
+	return list, nil
+}
+
+// Update implements IngredientRepository.Update
+func (r *IngredientRepository) Update(ctx context.Context, ingredient *entity.CatalogIngredient) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.ingredients[ingredient.ID]; !ok {
+		return nil
+	}
+	if r.keyTaken(ingredient) {
+		return repository.ErrDuplicateIngredient
+	}
+	r.remove(ingredient.ID)
+	r.store(ingredient)
+	return nil
+}
+
+// Delete implements IngredientRepository.Delete
+func (r *IngredientRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(id)
+	return nil
+}
+
+// keyTaken reports whether another ingredient already uses one of the
+// ingredient's keys
+func (r *IngredientRepository) keyTaken(ingredient *entity.CatalogIngredient) bool {
+	for _, key := range ingredient.Keys() {
+		if id, ok := r.keys[key]; ok && id != ingredient.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *IngredientRepository) store(ingredient *entity.CatalogIngredient) {
+	r.ingredients[ingredient.ID] = cloneCatalogIngredient(ingredient)
+	for _, key := range ingredient.Keys() {
+		r.keys[key] = ingredient.ID
+	}
+}
+
+func (r *IngredientRepository) remove(id primitive.ObjectID) {
+	ingredient, ok := r.ingredients[id]
+	if !ok {
+		return
+	}
+	for _, key := range ingredient.Keys() {
+		delete(r.keys, key)
+	}
+	delete(r.ingredients, id)
+}
+
+// cloneCatalogIngredient returns a deep copy so callers can never mutate
+// stored state
+func cloneCatalogIngredient(ingredient *entity.CatalogIngredient) *entity.CatalogIngredient {
+	c := *ingredient
+	c.Aliases = append([]string{}, ingredient.Aliases...)
+	if ingredient.ABV != nil {
+		abv := *ingredient.ABV
+		c.ABV = &abv
+	}
+	return &c
+}
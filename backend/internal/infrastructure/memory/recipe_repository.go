@@ -0,0 +1,386 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/domain/search"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RecipeRepository implements the domain.RecipeRepository interface
+// on top of an in-process map. It is safe for concurrent use.
+type RecipeRepository struct {
+	mu      sync.RWMutex
+	recipes map[primitive.ObjectID]*entity.Recipe
+}
+
+// NewRecipeRepository creates a new, empty RecipeRepository
+func NewRecipeRepository() *RecipeRepository {
+	return &RecipeRepository{
+		recipes: make(map[primitive.ObjectID]*entity.Recipe),
+	}
+}
+
+// Create implements RecipeRepository.Create
+func (r *RecipeRepository) Create(ctx context.Context, recipe *entity.Recipe) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if recipe.ID.IsZero() {
+		recipe.ID = primitive.NewObjectID()
+	}
+	r.recipes[recipe.ID] = cloneRecipe(recipe)
+	return nil
+}
+
+// FindByID implements RecipeRepository.FindByID
+func (r *RecipeRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Recipe, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recipe, ok := r.recipes[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneRecipe(recipe), nil
+}
+
+// FindByIDs implements RecipeRepository.FindByIDs
+func (r *RecipeRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entity.Recipe, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	recipes := make([]*entity.Recipe, 0, len(ids))
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	for _, id := range ids {
+		if recipe, ok := r.recipes[id]; ok && !seen[id] {
+			seen[id] = true
+			recipes = append(recipes, cloneRecipe(recipe))
+		}
+	}
+	return recipes, nil
+}
+
+// FindByClassification implements RecipeRepository.FindByClassification
+func (r *RecipeRepository) FindByClassification(ctx context.Context, filter repository.ClassificationFilter, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(opts, func(recipe *entity.Recipe) (float64, bool) {
+		return 0, filter.Matches(recipe)
+	})
+}
+
+// Facets implements RecipeRepository.Facets
+func (r *RecipeRepository) Facets(ctx context.Context, filter repository.ClassificationFilter, opts repository.ListOptions) (*repository.RecipeFacets, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var (
+		tags        = make(map[string]int64)
+		baseSpirits = make(map[string]int64)
+		techniques  = make(map[string]int64)
+		seasons     = make(map[string]int64)
+		flavors     = make(map[string]int64)
+	)
+	for _, recipe := range r.recipes {
+		if (recipe.Hidden && !opts.IncludeHidden) || (!recipe.Featured && opts.FeaturedOnly) {
+			continue
+		}
+		if filter.Matches(recipe) {
+			for _, tag := range recipe.Tags {
+				tags[tag]++
+			}
+			for _, axis := range entity.FlavorAxes {
+				if recipe.Flavor.Pronounced(axis) {
+					flavors[string(axis)]++
+				}
+			}
+		}
+		if recipe.BaseSpirit != "" && filter.WithoutBaseSpirit().Matches(recipe) {
+			baseSpirits[string(recipe.BaseSpirit)]++
+		}
+		if recipe.Technique != "" && filter.WithoutTechnique().Matches(recipe) {
+			techniques[string(recipe.Technique)]++
+		}
+		if recipe.Season != "" && filter.WithoutSeason().Matches(recipe) {
+			seasons[string(recipe.Season)]++
+		}
+	}
+
+	return &repository.RecipeFacets{
+		Tags:        repository.NewFacetCounts(tags, repository.MaxTagFacets),
+		BaseSpirits: repository.NewFacetCounts(baseSpirits, 0),
+		Techniques:  repository.NewFacetCounts(techniques, 0),
+		Seasons:     repository.NewFacetCounts(seasons, 0),
+		Flavors:     repository.NewFacetCounts(flavors, 0),
+	}, nil
+}
+
+// CountValues implements RecipeRepository.CountValues
+func (r *RecipeRepository) CountValues(ctx context.Context, field repository.AutocompleteField, prefix string) (map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	prefix = strings.ToLower(prefix)
+	counts := make(map[string]int64)
+	count := func(value string) {
+		if value != "" && strings.HasPrefix(strings.ToLower(value), prefix) {
+			counts[value]++
+		}
+	}
+	for _, recipe := range r.recipes {
+		if recipe.Hidden {
+			continue
+		}
+		switch field {
+		case repository.AutocompleteIngredient:
+			for _, ing := range recipe.Ingredients {
+				count(ing.Name)
+			}
+		case repository.AutocompleteUnit:
+			for _, ing := range recipe.Ingredients {
+				count(ing.Unit)
+			}
+		case repository.AutocompleteGlass:
+			count(recipe.Glass)
+		case repository.AutocompleteGarnish:
+			count(recipe.Garnish)
+		case repository.AutocompleteName:
+			count(recipe.Name)
+		}
+	}
+	return counts, nil
+}
+
+// FindByIngredient implements RecipeRepository.FindByIngredient
+func (r *RecipeRepository) FindByIngredient(ctx context.Context, match repository.IngredientMatch, opts repository.ListOptions) (*repository.RecipePage, error) {
+	needles := make([]string, len(match.Names))
+	for i, name := range match.Names {
+		needles[i] = strings.ToLower(name)
+	}
+	ids := make(map[primitive.ObjectID]bool, len(match.CatalogIDs))
+	for _, id := range match.CatalogIDs {
+		ids[id] = true
+	}
+
+	return r.findPage(opts, func(recipe *entity.Recipe) (float64, bool) {
+		for _, ing := range recipe.Ingredients {
+			if ing.CatalogID != nil && ids[*ing.CatalogID] {
+				return 0, true
+			}
+		}
+		for _, needle := range needles {
+			if hasIngredientMatching(recipe, needle) {
+				return 0, true
+			}
+		}
+		return 0, false
+	})
+}
+
+// FindForks implements RecipeRepository.FindForks
+func (r *RecipeRepository) FindForks(ctx context.Context, parentID primitive.ObjectID, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(opts, func(recipe *entity.Recipe) (float64, bool) {
+		return 0, recipe.ForkedFrom != nil && *recipe.ForkedFrom == parentID
+	})
+}
+
+// FindByCreator implements RecipeRepository.FindByCreator
+func (r *RecipeRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(opts, func(recipe *entity.Recipe) (float64, bool) {
+		return 0, recipe.CreatorID != nil && *recipe.CreatorID == creatorID
+	})
+}
+
+// Update implements RecipeRepository.Update
+func (r *RecipeRepository) Update(ctx context.Context, recipe *entity.Recipe, expectedVersion int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.recipes[recipe.ID]
+	if !ok || stored.Version != expectedVersion {
+		return repository.ErrVersionConflict
+	}
+	c := cloneRecipe(recipe)
+	c.Rating, c.RatingCount = stored.Rating, stored.RatingCount
+	r.recipes[recipe.ID] = c
+	return nil
+}
+
+// UpdateRating implements RecipeRepository.UpdateRating
+func (r *RecipeRepository) UpdateRating(ctx context.Context, id primitive.ObjectID, rating float64, count int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.recipes[id]; ok {
+		stored.Rating, stored.RatingCount = rating, count
+		stored.Version++
+	}
+	return nil
+}
+
+// Delete implements RecipeRepository.Delete
+func (r *RecipeRepository) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.recipes[id]
+	if !ok || stored.Version != expectedVersion {
+		return repository.ErrVersionConflict
+	}
+	delete(r.recipes, id)
+	return nil
+}
+
+// Search implements RecipeRepository.Search, scoring every recipe the
+// filter accepts
+func (r *RecipeRepository) Search(ctx context.Context, query search.Query, filter repository.SearchFilter, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(opts, func(recipe *entity.Recipe) (float64, bool) {
+		if !matchesFilter(recipe, filter) {
+			return 0, false
+		}
+		if query.Empty() {
+			return 0, true
+		}
+		score := search.Score(recipe, query)
+		return score, score > 0
+	})
+}
+
+// matchesFilter reports whether a recipe passes the non-text part of a search
+func matchesFilter(recipe *entity.Recipe, filter repository.SearchFilter) bool {
+	if filter.Type != nil && recipe.Type != *filter.Type {
+		return false
+	}
+	if filter.MinABV != nil || filter.MaxABV != nil {
+		if recipe.ABV == nil {
+			return false
+		}
+		if filter.MinABV != nil && *recipe.ABV < *filter.MinABV {
+			return false
+		}
+		if filter.MaxABV != nil && *recipe.ABV > *filter.MaxABV {
+			return false
+		}
+	}
+	return true
+}
+
+// findPage returns one page of copies of the recipes accepted by match,
+// ordered and positioned according to opts
+func (r *RecipeRepository) findPage(opts repository.ListOptions, match func(*entity.Recipe) (float64, bool)) (*repository.RecipePage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []repository.ScoredRecipe
+	for _, recipe := range r.recipes {
+		if (recipe.Hidden && !opts.IncludeHidden) || (!recipe.Featured && opts.FeaturedOnly) {
+			continue
+		}
+		if score, ok := match(recipe); ok {
+			matches = append(matches, repository.ScoredRecipe{Recipe: recipe, Score: score})
+		}
+	}
+
+	page, err := repository.PageOf(matches, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i, recipe := range page.Recipes {
+		page.Recipes[i] = cloneRecipe(recipe)
+	}
+	return page, nil
+}
+
+func hasIngredientMatching(recipe *entity.Recipe, needle string) bool {
+	for _, ing := range recipe.Ingredients {
+		if strings.Contains(strings.ToLower(ing.Name), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneRecipe returns a deep copy so callers can never mutate stored state
+func cloneRecipe(recipe *entity.Recipe) *entity.Recipe {
+	c := *recipe
+	c.Ingredients = entity.CloneIngredients(recipe.Ingredients)
+	if recipe.Instructions != nil {
+		c.Instructions = append([]string(nil), recipe.Instructions...)
+	}
+	if recipe.ForkedFrom != nil {
+		parent := *recipe.ForkedFrom
+		c.ForkedFrom = &parent
+	}
+	if recipe.CreatorID != nil {
+		creator := *recipe.CreatorID
+		c.CreatorID = &creator
+	}
+	if recipe.ABV != nil {
+		abv := *recipe.ABV
+		c.ABV = &abv
+	}
+	if recipe.Equipment != nil {
+		c.Equipment = append([]string(nil), recipe.Equipment...)
+	}
+	if recipe.Tags != nil {
+		c.Tags = append([]string(nil), recipe.Tags...)
+	}
+	if recipe.Flavor != nil {
+		flavor := *recipe.Flavor
+		c.Flavor = &flavor
+	}
+	if recipe.OvenTemperature != nil {
+		oven := *recipe.OvenTemperature
+		c.OvenTemperature = &oven
+	}
+	return &c
+}
+
+// FindMakeable implements RecipeRepository.FindMakeable
+func (r *RecipeRepository) FindMakeable(ctx context.Context, available repository.AvailableIngredients, maxMissing, limit int) ([]*repository.MakeableRecipe, error) {
+	have := make(map[string]bool, len(available.Names))
+	for _, name := range available.Names {
+		have[name] = true
+	}
+	haveIDs := make(map[primitive.ObjectID]bool, len(available.CatalogIDs))
+	for _, id := range available.CatalogIDs {
+		haveIDs[id] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var matches []*repository.MakeableRecipe
+	for _, recipe := range r.recipes {
+		if recipe.Hidden {
+			continue
+		}
+		missing := recipe.MissingIngredients(have, haveIDs)
+		if len(missing) <= maxMissing {
+			matches = append(matches, &repository.MakeableRecipe{
+				Recipe:  cloneRecipe(recipe),
+				Missing: missing,
+			})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if len(a.Missing) != len(b.Missing) {
+			return len(a.Missing) < len(b.Missing)
+		}
+		if a.Recipe.Name != b.Recipe.Name {
+			return a.Recipe.Name < b.Recipe.Name
+		}
+		return a.Recipe.ID.Hex() < b.Recipe.ID.Hex()
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
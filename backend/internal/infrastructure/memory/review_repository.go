@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewRepository implements the domain.ReviewRepository interface on top
+// of an in-process map. It is safe for concurrent use.
+type ReviewRepository struct {
+	mu      sync.RWMutex
+	reviews map[primitive.ObjectID]*entity.Review
+}
+
+// NewReviewRepository creates a new, empty ReviewRepository
+func NewReviewRepository() *ReviewRepository {
+	return &ReviewRepository{
+		reviews: make(map[primitive.ObjectID]*entity.Review),
+	}
+}
+
+// Create implements ReviewRepository.Create
+func (r *ReviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.reviews {
+		if existing.RecipeID == review.RecipeID && existing.UserID == review.UserID {
+			return repository.ErrDuplicateReview
+		}
+	}
+	if review.ID.IsZero() {
+		review.ID = primitive.NewObjectID()
+	}
+	c := *review
+	r.reviews[review.ID] = &c
+	return nil
+}
+
+// FindByID implements ReviewRepository.FindByID
+func (r *ReviewRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Review, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, nil
+	}
+	c := *review
+	return &c, nil
+}
+
+// FindByRecipeAndUser implements ReviewRepository.FindByRecipeAndUser
+func (r *ReviewRepository) FindByRecipeAndUser(ctx context.Context, recipeID, userID primitive.ObjectID) (*entity.Review, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, review := range r.reviews {
+		if review.RecipeID == recipeID && review.UserID == userID {
+			c := *review
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindByRecipe implements ReviewRepository.FindByRecipe
+func (r *ReviewRepository) FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Review, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reviews := []*entity.Review{}
+	for _, review := range r.reviews {
+		if review.RecipeID == recipeID {
+			c := *review
+			reviews = append(reviews, &c)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].CreatedAt.Equal(reviews[j].CreatedAt) {
+			return reviews[i].CreatedAt.After(reviews[j].CreatedAt)
+		}
+		return reviews[i].ID.Hex() > reviews[j].ID.Hex()
+	})
+	return reviews, nil
+}
+
+// Update implements ReviewRepository.Update
+func (r *ReviewRepository) Update(ctx context.Context, review *entity.Review) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.reviews[review.ID]; ok {
+		c := *review
+		r.reviews[review.ID] = &c
+	}
+	return nil
+}
+
+// Delete implements ReviewRepository.Delete
+func (r *ReviewRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.reviews, id)
+	return nil
+}
+
+// DeleteByRecipe implements ReviewRepository.DeleteByRecipe
+func (r *ReviewRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, review := range r.reviews {
+		if review.RecipeID == recipeID {
+			delete(r.reviews, id)
+		}
+	}
+	return nil
+}
+
+// Summarize implements ReviewRepository.Summarize
+func (r *ReviewRepository) Summarize(ctx context.Context, recipeID primitive.ObjectID) (float64, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sum, count := 0, 0
+	for _, review := range r.reviews {
+		if review.RecipeID == recipeID {
+			sum += review.Rating
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return float64(sum) / float64(count), count, nil
+}
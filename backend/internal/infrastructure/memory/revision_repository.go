@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevisionRepository implements the domain.RevisionRepository interface
+// on top of an in-process map. It is safe for concurrent use.
+type RevisionRepository struct {
+	mu sync.RWMutex
+	// revisions holds each recipe's revisions in ascending number order
+	revisions map[primitive.ObjectID][]*entity.Revision
+}
+
+// NewRevisionRepository creates a new, empty RevisionRepository
+func NewRevisionRepository() *RevisionRepository {
+	return &RevisionRepository{
+		revisions: make(map[primitive.ObjectID][]*entity.Revision),
+	}
+}
+
+// Create implements RevisionRepository.Create
+func (r *RevisionRepository) Create(ctx context.Context, revision *entity.Revision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing := r.revisions[revision.RecipeID]
+	i := sort.Search(len(existing), func(i int) bool {
+		return existing[i].Number >= revision.Number
+	})
+	if i < len(existing) && existing[i].Number == revision.Number {
+		return repository.ErrDuplicateRevision
+	}
+
+	if revision.ID.IsZero() {
+		revision.ID = primitive.NewObjectID()
+	}
+	r.revisions[revision.RecipeID] = slices.Insert(existing, i, cloneRevision(revision))
+	return nil
+}
+
+// FindByRecipe implements RevisionRepository.FindByRecipe
+func (r *RevisionRepository) FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing := r.revisions[recipeID]
+	revisions := make([]*entity.Revision, 0, len(existing))
+	for i := len(existing) - 1; i >= 0; i-- {
+		revisions = append(revisions, cloneRevision(existing[i]))
+	}
+	return revisions, nil
+}
+
+// FindByNumber implements RevisionRepository.FindByNumber
+func (r *RevisionRepository) FindByNumber(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, revision := range r.revisions[recipeID] {
+		if revision.Number == number {
+			return cloneRevision(revision), nil
+		}
+	}
+	return nil, nil
+}
+
+// FindBefore implements RevisionRepository.FindBefore
+func (r *RevisionRepository) FindBefore(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing := r.revisions[recipeID]
+	for i := len(existing) - 1; i >= 0; i-- {
+		if existing[i].Number < number {
+			return cloneRevision(existing[i]), nil
+		}
+	}
+	return nil, nil
+}
+
+// FindLatest implements RevisionRepository.FindLatest
+func (r *RevisionRepository) FindLatest(ctx context.Context, recipeID primitive.ObjectID) (*entity.Revision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	existing := r.revisions[recipeID]
+	if len(existing) == 0 {
+		return nil, nil
+	}
+	return cloneRevision(existing[len(existing)-1]), nil
+}
+
+// DeleteByRecipe implements RevisionRepository.DeleteByRecipe
+func (r *RevisionRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.revisions, recipeID)
+	return nil
+}
+
+func cloneRevision(revision *entity.Revision) *entity.Revision {
+	c := *revision
+	c.Recipe = *cloneRecipe(&revision.Recipe)
+	return &c
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserRepository implements the domain.UserRepository interface on top of
+// an in-process map. It is safe for concurrent use.
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[primitive.ObjectID]*entity.User
+	// emails maps every normalized email to the account using it
+	emails map[string]primitive.ObjectID
+}
+
+// NewUserRepository creates a new, empty UserRepository
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		users:  make(map[primitive.ObjectID]*entity.User),
+		emails: make(map[string]primitive.ObjectID),
+	}
+}
+
+// Create implements UserRepository.Create
+func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.emails[user.Email]; ok {
+		return repository.ErrDuplicateEmail
+	}
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	r.store(user)
+	return nil
+}
+
+// FindByID implements UserRepository.FindByID
+func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	c := *user
+	return &c, nil
+}
+
+// FindByEmail implements UserRepository.FindByEmail
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.emails[entity.NormalizeEmail(email)]
+	if !ok {
+		return nil, nil
+	}
+	c := *r.users[id]
+	return &c, nil
+}
+
+// Update implements UserRepository.Update
+func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.users[user.ID]
+	if !ok {
+		return nil
+	}
+	if id, taken := r.emails[user.Email]; taken && id != user.ID {
+		return repository.ErrDuplicateEmail
+	}
+	delete(r.emails, old.Email)
+	r.store(user)
+	return nil
+}
+
+func (r *UserRepository) store(user *entity.User) {
+	c := *user
+	r.users[user.ID] = &c
+	r.emails[user.Email] = user.ID
+}
@@ -0,0 +1,84 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// APIKeyRepository implements the domain.APIKeyRepository interface
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *mongo.Database) *APIKeyRepository {
+	return &APIKeyRepository{
+		collection: db.Collection("api_keys"),
+	}
+}
+
+// Create implements APIKeyRepository.Create
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	result, err := r.collection.InsertOne(ctx, key)
+	if err != nil {
+		return err
+	}
+	key.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID implements APIKeyRepository.FindByID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.APIKey, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindByHash implements APIKeyRepository.FindByHash
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	return r.findOne(ctx, bson.M{"hash": hash})
+}
+
+// FindByUser implements APIKeyRepository.FindByUser
+func (r *APIKeyRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.APIKey, error) {
+	findOpts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []*entity.APIKey{}
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// TouchLastUsed implements APIKeyRepository.TouchLastUsed
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
+
+// Delete implements APIKeyRepository.Delete
+func (r *APIKeyRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *APIKeyRepository) findOne(ctx context.Context, filter bson.M) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.collection.FindOne(ctx, filter).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
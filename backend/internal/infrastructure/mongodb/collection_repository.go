@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CollectionRepository implements the domain.CollectionRepository interface.
+// Entries are embedded in the collection document in order.
+type CollectionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCollectionRepository creates a new CollectionRepository
+func NewCollectionRepository(db *mongo.Database) *CollectionRepository {
+	return &CollectionRepository{
+		collection: db.Collection("collections"),
+	}
+}
+
+// Create implements CollectionRepository.Create
+func (r *CollectionRepository) Create(ctx context.Context, collection *entity.Collection) error {
+	result, err := r.collection.InsertOne(ctx, collection)
+	if err != nil {
+		return err
+	}
+	collection.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID implements CollectionRepository.FindByID
+func (r *CollectionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Collection, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindByShareToken implements CollectionRepository.FindByShareToken
+func (r *CollectionRepository) FindByShareToken(ctx context.Context, token string) (*entity.Collection, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return r.findOne(ctx, bson.M{"share_token": token})
+}
+
+// FindByOwner implements CollectionRepository.FindByOwner
+func (r *CollectionRepository) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]*entity.Collection, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "updated_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": ownerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	collections := []*entity.Collection{}
+	if err = cursor.All(ctx, &collections); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// CountByOwner implements CollectionRepository.CountByOwner
+func (r *CollectionRepository) CountByOwner(ctx context.Context, ownerID primitive.ObjectID) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"owner_id": ownerID})
+	return int(count), err
+}
+
+// Update implements CollectionRepository.Update
+func (r *CollectionRepository) Update(ctx context.Context, collection *entity.Collection, expectedVersion int64) error {
+	result, err := r.collection.ReplaceOne(ctx, versionFilter(collection.ID, expectedVersion), collection)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrCollectionConflict
+	}
+	return nil
+}
+
+// Delete implements CollectionRepository.Delete
+func (r *CollectionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// RemoveRecipe implements CollectionRepository.RemoveRecipe
+func (r *CollectionRepository) RemoveRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"entries.recipe_id": recipeID},
+		bson.M{
+			"$pull": bson.M{"entries": bson.M{"recipe_id": recipeID}},
+			"$inc":  bson.M{"version": 1},
+		})
+	return err
+}
+
+func (r *CollectionRepository) findOne(ctx context.Context, filter bson.M) (*entity.Collection, error) {
+	var collection entity.Collection
+	err := r.collection.FindOne(ctx, filter).Decode(&collection)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if collection.Entries == nil {
+		collection.Entries = []entity.CollectionEntry{}
+	}
+	return &collection, nil
+}
@@ -0,0 +1,79 @@
+package mongodb
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FavoriteRepository implements the domain.FavoriteRepository interface
+type FavoriteRepository struct {
+	collection *mongo.Collection
+}
+
+// NewFavoriteRepository creates a new FavoriteRepository
+func NewFavoriteRepository(db *mongo.Database) *FavoriteRepository {
+	return &FavoriteRepository{
+		collection: db.Collection("favorites"),
+	}
+}
+
+// Add implements FavoriteRepository.Add. An upsert keeps the original
+// time a recipe was favorited when it is added again.
+func (r *FavoriteRepository) Add(ctx context.Context, favorite *entity.Favorite) error {
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"user_id": favorite.UserID, "recipe_id": favorite.RecipeID},
+		bson.M{"$setOnInsert": bson.M{"created_at": favorite.CreatedAt}},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// A concurrent Add won the race; the favorite exists either way
+		return nil
+	}
+	return err
+}
+
+// Remove implements FavoriteRepository.Remove
+func (r *FavoriteRepository) Remove(ctx context.Context, userID, recipeID primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"user_id": userID, "recipe_id": recipeID})
+	return err
+}
+
+// FindByUser implements FavoriteRepository.FindByUser
+func (r *FavoriteRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.Favorite, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "recipe_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	favorites := []*entity.Favorite{}
+	if err = cursor.All(ctx, &favorites); err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+// Exists implements FavoriteRepository.Exists
+func (r *FavoriteRepository) Exists(ctx context.Context, userID, recipeID primitive.ObjectID) (bool, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "recipe_id": recipeID},
+		options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// CountByUser implements FavoriteRepository.CountByUser
+func (r *FavoriteRepository) CountByUser(ctx context.Context, userID primitive.ObjectID) (int, error) {
+	count, err := r.collection.CountDocuments(ctx, bson.M{"user_id": userID})
+	return int(count), err
+}
+
+// DeleteByRecipe implements FavoriteRepository.DeleteByRecipe
+func (r *FavoriteRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"recipe_id": recipeID})
+	return err
+}
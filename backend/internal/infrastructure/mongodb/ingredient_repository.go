@@ -0,0 +1,119 @@
+package mongodb
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ingredientDocument is how a catalog ingredient is stored. Keys holds the
+// normalized name and aliases and carries the unique index that keeps them
+// from being shared between ingredients.
+type ingredientDocument struct {
+	entity.CatalogIngredient `bson:",inline"`
+	Keys                     []string `bson:"keys"`
+}
+
+// IngredientRepository implements the domain.IngredientRepository interface
+type IngredientRepository struct {
+	collection *mongo.Collection
+}
+
+// NewIngredientRepository creates a new IngredientRepository
+func NewIngredientRepository(db *mongo.Database) *IngredientRepository {
+	return &IngredientRepository{
+		collection: db.Collection("ingredients"),
+	}
+}
+
+// Create implements IngredientRepository.Create
+func (r *IngredientRepository) Create(ctx context.Context, ingredient *entity.CatalogIngredient) error {
+	result, err := r.collection.InsertOne(ctx, newIngredientDocument(ingredient))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrDuplicateIngredient
+		}
+		return err
+	}
+	ingredient.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID implements IngredientRepository.FindByID
+func (r *IngredientRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CatalogIngredient, error) {
+	var doc ingredientDocument
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &doc.CatalogIngredient, nil
+}
+
+// FindByNames implements IngredientRepository.FindByNames
+func (r *IngredientRepository) FindByNames(ctx context.Context, names []string) ([]*entity.CatalogIngredient, error) {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = entity.NormalizeIngredientName(name)
+	}
+	return r.find(ctx, bson.M{"keys": bson.M{"$in": keys}})
+}
+
+// List implements IngredientRepository.List
+func (r *IngredientRepository) List(ctx context.Context, category entity.IngredientCategory) ([]*entity.CatalogIngredient, error) {
+	filter := bson.M{}
+	if category != "" {
+		filter["category"] = category
+	}
+	return r.find(ctx, filter)
+}
+
+// Update implements IngredientRepository.Update
+func (r *IngredientRepository) Update(ctx context.Context, ingredient *entity.CatalogIngredient) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": ingredient.ID}, newIngredientDocument(ingredient))
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicateIngredient
+	}
+	return err
+}
+
+// Delete implements IngredientRepository.Delete
+func (r *IngredientRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *IngredientRepository) find(ctx context.Context, filter bson.M) ([]*entity.CatalogIngredient, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []ingredientDocument
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ingredients := make([]*entity.CatalogIngredient, 0, len(docs))
+	for i := range docs {
+		ingredients = append(ingredients, &docs[i].CatalogIngredient)
+	}
+	return ingredients, nil
+}
+
+func newIngredientDocument(ingredient *entity.CatalogIngredient) ingredientDocument {
+	return ingredientDocument{
+		CatalogIngredient: *ingredient,
+		Keys:              ingredient.Keys(),
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"log"
 	"time"
 
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/search"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -20,6 +23,39 @@ func InitializeDatabase(db *mongo.Database) error {
 		return err
 	}
 
+	// Initialize Recipe revisions collection
+	if err := initializeRevisionsCollection(ctx, db); err != nil {
+		return err
+	}
+
+	// Initialize Ingredient catalog collection
+	if err := initializeIngredientsCollection(ctx, db); err != nil {
+		return err
+	}
+
+	// Initialize Users collection
+	if err := initializeUsersCollection(ctx, db); err != nil {
+		return err
+	}
+
+	// Initialize API keys collection
+	if err := initializeAPIKeysCollection(ctx, db); err != nil {
+		return err
+	}
+
+	// Initialize Recipe reviews collection
+	if err := initializeReviewsCollection(ctx, db); err != nil {
+		return err
+	}
+
+	if err := initializeFavoritesCollection(ctx, db); err != nil {
+		return err
+	}
+
+	if err := initializeCollectionsCollection(ctx, db); err != nil {
+		return err
+	}
+
 	log.Println("Database initialization completed successfully")
 	return nil
 }
@@ -43,6 +79,74 @@ func initializeRecipesCollection(ctx context.Context, db *mongo.Database) error
 			Keys:    bson.D{{Key: "ingredients.name", Value: 1}},
 			Options: options.Index().SetName("recipe_ingredients"),
 		},
+		{
+			Keys:    bson.D{{Key: "forked_from", Value: 1}},
+			Options: options.Index().SetName("recipe_forked_from"),
+		},
+		{
+			Keys:    bson.D{{Key: "abv", Value: 1}},
+			Options: options.Index().SetName("recipe_abv"),
+		},
+		{
+			Keys:    bson.D{{Key: "ingredients.catalog_id", Value: 1}},
+			Options: options.Index().SetName("recipe_ingredient_catalog"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}},
+			Options: options.Index().SetName("recipe_creator"),
+		},
+		{
+			Keys:    bson.D{{Key: "featured", Value: 1}},
+			Options: options.Index().SetName("recipe_featured"),
+		},
+		{
+			Keys:    bson.D{{Key: "rating", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("recipe_rating"),
+		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}},
+			Options: options.Index().SetName("recipe_tags"),
+		},
+		{
+			Keys:    bson.D{{Key: "base_spirit", Value: 1}},
+			Options: options.Index().SetName("recipe_base_spirit"),
+		},
+		{
+			Keys:    bson.D{{Key: "technique", Value: 1}},
+			Options: options.Index().SetName("recipe_technique"),
+		},
+		{
+			Keys:    bson.D{{Key: "season", Value: 1}},
+			Options: options.Index().SetName("recipe_season"),
+		},
+		{
+			Keys:    bson.D{{Key: "search_trigrams", Value: 1}},
+			Options: options.Index().SetName("recipe_search_trigrams"),
+		},
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetName("recipe_name"),
+		},
+		{
+			Keys:    bson.D{{Key: "folded.name", Value: 1}},
+			Options: options.Index().SetName("recipe_folded_name"),
+		},
+		{
+			Keys:    bson.D{{Key: "folded.glass", Value: 1}},
+			Options: options.Index().SetName("recipe_folded_glass"),
+		},
+		{
+			Keys:    bson.D{{Key: "folded.garnish", Value: 1}},
+			Options: options.Index().SetName("recipe_folded_garnish"),
+		},
+		{
+			Keys:    bson.D{{Key: "folded.ingredients", Value: 1}},
+			Options: options.Index().SetName("recipe_folded_ingredients"),
+		},
+		{
+			Keys:    bson.D{{Key: "folded.units", Value: 1}},
+			Options: options.Index().SetName("recipe_folded_units"),
+		},
 	}
 
 	_, err := db.Collection("recipes").Indexes().CreateMany(ctx, recipeIndexes)
@@ -50,6 +154,275 @@ func initializeRecipesCollection(ctx context.Context, db *mongo.Database) error
 		return err
 	}
 
+	if err := backfillSearchTrigrams(ctx, db.Collection("recipes")); err != nil {
+		return err
+	}
+	if err := backfillFoldedValues(ctx, db.Collection("recipes")); err != nil {
+		return err
+	}
+	if err := backfillABV(ctx, db.Collection("recipes"), db.Collection("ingredients")); err != nil {
+		return err
+	}
+	if err := backfillRequiredIngredients(ctx, db.Collection("recipes")); err != nil {
+		return err
+	}
+
 	log.Println("Recipes collection initialized with indexes")
 	return nil
-} 
\ No newline at end of file
+} 
+
+// backfillSearchTrigrams indexes the recipes stored before Search looked
+// them up by trigram
+func backfillSearchTrigrams(ctx context.Context, recipes *mongo.Collection) error {
+	return backfillRecipes(ctx, recipes, "search_trigrams", func(recipe *entity.Recipe) interface{} {
+		return search.IndexTrigrams(recipe)
+	})
+}
+
+// backfillFoldedValues stores the lower-cased values autocomplete looks up
+// on the recipes saved before it did
+func backfillFoldedValues(ctx context.Context, recipes *mongo.Collection) error {
+	return backfillRecipes(ctx, recipes, "folded", func(recipe *entity.Recipe) interface{} {
+		return newFoldedValues(recipe)
+	})
+}
+
+// backfillRequiredIngredients stores the normalized ingredient names
+// FindMakeable compares on the recipes saved before it did
+func backfillRequiredIngredients(ctx context.Context, recipes *mongo.Collection) error {
+	return backfillRecipes(ctx, recipes, "required_ingredients", func(recipe *entity.Recipe) interface{} {
+		return newRequiredIngredients(recipe)
+	})
+}
+
+// backfillABV estimates the strength of the recipes stored before it was
+// estimated, so the ABV filter does not drop them. Recipes without an
+// estimate get a null abv and are not looked at again.
+func backfillABV(ctx context.Context, recipes, ingredients *mongo.Collection) error {
+	cursor, err := ingredients.Find(ctx, bson.M{"abv": bson.M{"$ne": nil}})
+	if err != nil {
+		return err
+	}
+	var catalog []*entity.CatalogIngredient
+	if err := cursor.All(ctx, &catalog); err != nil {
+		return err
+	}
+	entries := make(map[primitive.ObjectID]*entity.CatalogIngredient, len(catalog))
+	for _, entry := range catalog {
+		entries[entry.ID] = entry
+	}
+
+	return backfillRecipes(ctx, recipes, "abv", func(recipe *entity.Recipe) interface{} {
+		recipe.RefreshABV(entity.CatalogABV(entries))
+		return recipe.ABV
+	})
+}
+
+// backfillRecipes sets field to value(recipe) on every recipe stored
+// without it
+func backfillRecipes(ctx context.Context, recipes *mongo.Collection, field string,
+	value func(*entity.Recipe) interface{}) error {
+	cursor, err := recipes.Find(ctx, bson.M{field: bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var recipe entity.Recipe
+		if err := cursor.Decode(&recipe); err != nil {
+			return err
+		}
+		_, err := recipes.UpdateOne(ctx, bson.M{"_id": recipe.ID},
+			bson.M{"$set": bson.M{field: value(&recipe)}})
+		if err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func initializeRevisionsCollection(ctx context.Context, db *mongo.Database) error {
+	revisionIndexes := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "recipe_id", Value: 1},
+				{Key: "number", Value: -1},
+			},
+			Options: options.Index().SetName("revision_recipe_number").SetUnique(true),
+		},
+	}
+
+	_, err := db.Collection("recipe_revisions").Indexes().CreateMany(ctx, revisionIndexes)
+	if err != nil {
+		return err
+	}
+
+	if err := alignRecipeVersions(ctx, db.Collection("recipes"), db.Collection("recipe_revisions")); err != nil {
+		return err
+	}
+
+	log.Println("Recipe revisions collection initialized with indexes")
+	return nil
+}
+
+// alignRecipeVersions raises the version of every recipe to at least its
+// latest revision number. Revisions are numbered by version, and recipes
+// revised before versions were tracked would otherwise reuse old numbers.
+func alignRecipeVersions(ctx context.Context, recipes, revisions *mongo.Collection) error {
+	cursor, err := revisions.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$recipe_id", "latest": bson.M{"$max": "$number"}}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var latest struct {
+			RecipeID primitive.ObjectID `bson:"_id"`
+			Number   int64              `bson:"latest"`
+		}
+		if err := cursor.Decode(&latest); err != nil {
+			return err
+		}
+		_, err := recipes.UpdateOne(ctx, bson.M{"_id": latest.RecipeID},
+			bson.M{"$max": bson.M{"version": latest.Number}})
+		if err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func initializeIngredientsCollection(ctx context.Context, db *mongo.Database) error {
+	ingredientIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "keys", Value: 1}},
+			Options: options.Index().SetName("ingredient_keys").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName("ingredient_category_name"),
+		},
+	}
+
+	_, err := db.Collection("ingredients").Indexes().CreateMany(ctx, ingredientIndexes)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Ingredients collection initialized with indexes")
+	return nil
+}
+
+func initializeUsersCollection(ctx context.Context, db *mongo.Database) error {
+	userIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("user_email").SetUnique(true),
+		},
+	}
+
+	_, err := db.Collection("users").Indexes().CreateMany(ctx, userIndexes)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Users collection initialized with indexes")
+	return nil
+}
+
+func initializeAPIKeysCollection(ctx context.Context, db *mongo.Database) error {
+	apiKeyIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "hash", Value: 1}},
+			Options: options.Index().SetName("api_key_hash").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("api_key_user"),
+		},
+	}
+
+	_, err := db.Collection("api_keys").Indexes().CreateMany(ctx, apiKeyIndexes)
+	if err != nil {
+		return err
+	}
+
+	log.Println("API keys collection initialized with indexes")
+	return nil
+}
+
+func initializeReviewsCollection(ctx context.Context, db *mongo.Database) error {
+	reviewIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "recipe_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("review_recipe_user").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "recipe_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("review_recipe"),
+		},
+	}
+
+	_, err := db.Collection("recipe_reviews").Indexes().CreateMany(ctx, reviewIndexes)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Recipe reviews collection initialized with indexes")
+	return nil
+}
+
+func initializeFavoritesCollection(ctx context.Context, db *mongo.Database) error {
+	favoriteIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "recipe_id", Value: 1}},
+			Options: options.Index().SetName("favorite_user_recipe").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("favorite_user"),
+		},
+		{
+			Keys:    bson.D{{Key: "recipe_id", Value: 1}},
+			Options: options.Index().SetName("favorite_recipe"),
+		},
+	}
+
+	_, err := db.Collection("favorites").Indexes().CreateMany(ctx, favoriteIndexes)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Favorites collection initialized with indexes")
+	return nil
+}
+
+func initializeCollectionsCollection(ctx context.Context, db *mongo.Database) error {
+	collectionIndexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "updated_at", Value: -1}},
+			Options: options.Index().SetName("collection_owner"),
+		},
+		{
+			// Sparse so the many unshared collections without a token do
+			// not collide
+			Keys:    bson.D{{Key: "share_token", Value: 1}},
+			Options: options.Index().SetName("collection_share_token").SetUnique(true).SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "entries.recipe_id", Value: 1}},
+			Options: options.Index().SetName("collection_entry_recipe"),
+		},
+	}
+
+	_, err := db.Collection("collections").Indexes().CreateMany(ctx, collectionIndexes)
+	if err != nil {
+		return err
+	}
+
+	log.Println("Collections collection initialized with indexes")
+	return nil
+}
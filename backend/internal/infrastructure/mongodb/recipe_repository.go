@@ -2,14 +2,86 @@ package mongodb
 
 import (
 	"context"
+	"regexp"
+	"strings"
 
 	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/domain/search"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// recipeDocument is a recipe as stored, with the trigrams Search finds it
+// by and the lower-cased values CountValues looks prefixes up in
+type recipeDocument struct {
+	entity.Recipe  `bson:",inline"`
+	SearchTrigrams []string     `bson:"search_trigrams"`
+	Folded         foldedValues `bson:"folded"`
+	// RequiredIngredients are the ingredients FindMakeable checks
+	RequiredIngredients []requiredIngredient `bson:"required_ingredients"`
+}
+
+// foldedValues are lower-cased copies of the fields autocomplete completes.
+// MongoDB can only bound an index scan by a case-sensitive prefix, so
+// case-insensitive lookups go through these.
+type foldedValues struct {
+	Name        string   `bson:"name"`
+	Glass       string   `bson:"glass"`
+	Garnish     string   `bson:"garnish"`
+	Ingredients []string `bson:"ingredients"`
+	Units       []string `bson:"units"`
+}
+
+// requiredIngredient is a non-optional ingredient with its name normalized
+// by entity.NormalizeIngredientName. MongoDB's $toLower only folds ASCII, so
+// names such as "Crème de cassis" are normalized before they are stored.
+type requiredIngredient struct {
+	Name       string              `bson:"name"`
+	Normalized string              `bson:"normalized"`
+	CatalogID  *primitive.ObjectID `bson:"catalog_id"`
+}
+
+func newRecipeDocument(recipe *entity.Recipe) recipeDocument {
+	return recipeDocument{
+		Recipe:              *recipe,
+		SearchTrigrams:      search.IndexTrigrams(recipe),
+		Folded:              newFoldedValues(recipe),
+		RequiredIngredients: newRequiredIngredients(recipe),
+	}
+}
+
+func newRequiredIngredients(recipe *entity.Recipe) []requiredIngredient {
+	required := make([]requiredIngredient, 0, len(recipe.Ingredients))
+	for _, ing := range recipe.Ingredients {
+		if !ing.IsOptional {
+			required = append(required, requiredIngredient{
+				Name:       ing.Name,
+				Normalized: entity.NormalizeIngredientName(ing.Name),
+				CatalogID:  ing.CatalogID,
+			})
+		}
+	}
+	return required
+}
+
+func newFoldedValues(recipe *entity.Recipe) foldedValues {
+	folded := foldedValues{
+		Name:        strings.ToLower(recipe.Name),
+		Glass:       strings.ToLower(recipe.Glass),
+		Garnish:     strings.ToLower(recipe.Garnish),
+		Ingredients: make([]string, 0, len(recipe.Ingredients)),
+		Units:       make([]string, 0, len(recipe.Ingredients)),
+	}
+	for _, ing := range recipe.Ingredients {
+		folded.Ingredients = append(folded.Ingredients, strings.ToLower(ing.Name))
+		folded.Units = append(folded.Units, strings.ToLower(ing.Unit))
+	}
+	return folded
+}
+
 // RecipeRepository implements the domain.RecipeRepository interface
 type RecipeRepository struct {
 	collection *mongo.Collection
@@ -18,7 +90,7 @@ type RecipeRepository struct {
 // NewRecipeRepository creates a new RecipeRepository
 func NewRecipeRepository(db *mongo.Database) *RecipeRepository {
 	collection := db.Collection("recipes")
-	
+
 	// Create indexes
 	indexes := []mongo.IndexModel{
 		{
@@ -29,11 +101,11 @@ func NewRecipeRepository(db *mongo.Database) *RecipeRepository {
 			Options: options.Index().SetName("recipe_text_search"),
 		},
 		{
-			Keys: bson.D{{Key: "type", Value: 1}},
+			Keys:    bson.D{{Key: "type", Value: 1}},
 			Options: options.Index().SetName("recipe_type"),
 		},
 		{
-			Keys: bson.D{{Key: "creator_id", Value: 1}},
+			Keys:    bson.D{{Key: "creator_id", Value: 1}},
 			Options: options.Index().SetName("recipe_creator"),
 		},
 	}
@@ -53,7 +125,7 @@ func NewRecipeRepository(db *mongo.Database) *RecipeRepository {
 
 // Create implements RecipeRepository.Create
 func (r *RecipeRepository) Create(ctx context.Context, recipe *entity.Recipe) error {
-	result, err := r.collection.InsertOne(ctx, recipe)
+	result, err := r.collection.InsertOne(ctx, newRecipeDocument(recipe))
 	if err != nil {
 		return err
 	}
@@ -74,82 +146,400 @@ func (r *RecipeRepository) FindByID(ctx context.Context, id primitive.ObjectID)
 	return &recipe, nil
 }
 
-// FindByCreator implements RecipeRepository.FindByCreator
-func (r *RecipeRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]*entity.Recipe, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"creator_id": creatorID})
+// FindByIDs implements RecipeRepository.FindByIDs
+func (r *RecipeRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entity.Recipe, error) {
+	recipes := []*entity.Recipe{}
+	if len(ids) == 0 {
+		return recipes, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
-
-	var recipes []*entity.Recipe
 	if err = cursor.All(ctx, &recipes); err != nil {
 		return nil, err
 	}
 	return recipes, nil
 }
 
-// FindByType implements RecipeRepository.FindByType
-func (r *RecipeRepository) FindByType(ctx context.Context, recipeType entity.RecipeType) ([]*entity.Recipe, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"type": recipeType})
+// FindByCreator implements RecipeRepository.FindByCreator
+func (r *RecipeRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(ctx, bson.M{"creator_id": creatorID}, opts)
+}
+
+// FindByClassification implements RecipeRepository.FindByClassification
+func (r *RecipeRepository) FindByClassification(ctx context.Context, filter repository.ClassificationFilter, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(ctx, classificationFilter(filter), opts)
+}
+
+// Facets implements RecipeRepository.Facets with a single aggregation. The
+// stages shared by every dimension run first; each single-valued dimension
+// then applies the remaining constraints other than its own.
+func (r *RecipeRepository) Facets(ctx context.Context, filter repository.ClassificationFilter, opts repository.ListOptions) (*repository.RecipeFacets, error) {
+	shared := repository.ClassificationFilter{Type: filter.Type, Tags: filter.Tags, Flavors: filter.Flavors}
+	match := bson.M{"$and": []bson.M{classificationFilter(shared), moderationFilter(opts)}}
+
+	flavorCounts := bson.M{"_id": nil}
+	for _, axis := range entity.FlavorAxes {
+		field := "flavor." + string(axis)
+		flavorCounts[string(axis)] = bson.M{"$sum": bson.M{"$cond": bson.A{
+			bson.M{"$gte": bson.A{bson.M{"$ifNull": bson.A{"$" + field, 0}}, entity.FlavorPronounced}}, 1, 0,
+		}}}
+	}
+
+	// countBy groups the recipes passing the rest of the filter by a
+	// single-valued field
+	countBy := func(field string, rest repository.ClassificationFilter) bson.A {
+		rest.Type, rest.Tags, rest.Flavors = "", nil, nil
+		return bson.A{
+			bson.M{"$match": bson.M{"$and": []bson.M{classificationFilter(rest), {field: bson.M{"$nin": bson.A{"", nil}}}}}},
+			bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		}
+	}
+	full := classificationFilter(repository.ClassificationFilter{
+		BaseSpirit: filter.BaseSpirit, Technique: filter.Technique, Season: filter.Season,
+	})
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$facet", Value: bson.M{
+			"tags": bson.A{
+				bson.M{"$match": full},
+				bson.M{"$unwind": "$tags"},
+				bson.M{"$group": bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$limit": repository.MaxTagFacets},
+			},
+			"base_spirits": countBy("base_spirit", filter.WithoutBaseSpirit()),
+			"techniques":   countBy("technique", filter.WithoutTechnique()),
+			"seasons":      countBy("season", filter.WithoutSeason()),
+			"flavors": bson.A{
+				bson.M{"$match": full},
+				bson.M{"$group": flavorCounts},
+			},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var recipes []*entity.Recipe
-	if err = cursor.All(ctx, &recipes); err != nil {
+	type valueCount struct {
+		Value string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	var results []struct {
+		Tags        []valueCount       `bson:"tags"`
+		BaseSpirits []valueCount       `bson:"base_spirits"`
+		Techniques  []valueCount       `bson:"techniques"`
+		Seasons     []valueCount       `bson:"seasons"`
+		Flavors     []map[string]int64 `bson:"flavors"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
 		return nil, err
 	}
-	return recipes, nil
+
+	toMap := func(counts []valueCount) map[string]int64 {
+		m := make(map[string]int64, len(counts))
+		for _, c := range counts {
+			m[c.Value] = c.Count
+		}
+		return m
+	}
+	facets := &repository.RecipeFacets{}
+	if len(results) == 0 {
+		return facets, nil
+	}
+	result := results[0]
+	facets.Tags = repository.NewFacetCounts(toMap(result.Tags), repository.MaxTagFacets)
+	facets.BaseSpirits = repository.NewFacetCounts(toMap(result.BaseSpirits), 0)
+	facets.Techniques = repository.NewFacetCounts(toMap(result.Techniques), 0)
+	facets.Seasons = repository.NewFacetCounts(toMap(result.Seasons), 0)
+	flavors := make(map[string]int64, len(entity.FlavorAxes))
+	if len(result.Flavors) > 0 {
+		for _, axis := range entity.FlavorAxes {
+			flavors[string(axis)] = result.Flavors[0][string(axis)]
+		}
+	}
+	facets.Flavors = repository.NewFacetCounts(flavors, 0)
+	return facets, nil
+}
+
+// classificationFilter builds the query selecting the recipes that pass
+// filter
+func classificationFilter(filter repository.ClassificationFilter) bson.M {
+	query := bson.M{}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.BaseSpirit != "" {
+		query["base_spirit"] = filter.BaseSpirit
+	}
+	if filter.Technique != "" {
+		query["technique"] = filter.Technique
+	}
+	if filter.Season != "" {
+		query["season"] = filter.Season
+	}
+	if len(filter.Tags) > 0 {
+		query["tags"] = bson.M{"$all": filter.Tags}
+	}
+	for _, axis := range filter.Flavors {
+		query["flavor."+string(axis)] = bson.M{"$gte": entity.FlavorPronounced}
+	}
+	return query
+}
+
+// FindForks implements RecipeRepository.FindForks
+func (r *RecipeRepository) FindForks(ctx context.Context, parentID primitive.ObjectID, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(ctx, bson.M{"forked_from": parentID}, opts)
 }
 
 // Update implements RecipeRepository.Update
-func (r *RecipeRepository) Update(ctx context.Context, recipe *entity.Recipe) error {
-	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": recipe.ID}, recipe)
+func (r *RecipeRepository) Update(ctx context.Context, recipe *entity.Recipe, expectedVersion int64) error {
+	filter := versionFilter(recipe.ID, expectedVersion)
+
+	// Replace the document but carry over the stored rating, which reviews
+	// may have changed since the recipe was read. $literal keeps strings
+	// such as "$5 gin" from being read as field paths.
+	update := mongo.Pipeline{{{Key: "$replaceWith", Value: bson.M{"$mergeObjects": bson.A{
+		bson.M{"$literal": newRecipeDocument(recipe)},
+		bson.M{
+			"rating":       bson.M{"$ifNull": bson.A{"$rating", 0}},
+			"rating_count": bson.M{"$ifNull": bson.A{"$rating_count", 0}},
+		},
+	}}}}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return repository.ErrVersionConflict
+	}
+	return nil
+}
+
+// UpdateRating implements RecipeRepository.UpdateRating
+func (r *RecipeRepository) UpdateRating(ctx context.Context, id primitive.ObjectID, rating float64, count int) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id},
+		bson.M{
+			"$set": bson.M{"rating": rating, "rating_count": count},
+			"$inc": bson.M{"version": 1},
+		})
 	return err
 }
 
 // Delete implements RecipeRepository.Delete
-func (r *RecipeRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
-	return err
+func (r *RecipeRepository) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	result, err := r.collection.DeleteOne(ctx, versionFilter(id, expectedVersion))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repository.ErrVersionConflict
+	}
+	return nil
+}
+
+// versionFilter selects a recipe only while it is at expectedVersion
+func versionFilter(id primitive.ObjectID, expectedVersion int64) bson.M {
+	filter := bson.M{"_id": id, "version": expectedVersion}
+	if expectedVersion == 0 {
+		// Recipes stored before versioning have no version field at all
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	return filter
+}
+
+// autocompletePaths maps each autocomplete field to the document path of
+// its values and of their lower-cased copies. The ingredient fields sit in
+// the ingredients array, which is unwound before counting.
+var autocompletePaths = map[repository.AutocompleteField]struct{ value, folded string }{
+	repository.AutocompleteIngredient: {"ingredients.name", "folded.ingredients"},
+	repository.AutocompleteUnit:       {"ingredients.unit", "folded.units"},
+	repository.AutocompleteGlass:      {"glass", "folded.glass"},
+	repository.AutocompleteGarnish:    {"garnish", "folded.garnish"},
+	repository.AutocompleteName:       {"name", "folded.name"},
+}
+
+// CountValues implements RecipeRepository.CountValues. The prefix is matched
+// case-sensitively against the lower-cased copy of the field, which bounds
+// the scan of its index, so only recipes using a matching value are read.
+func (r *RecipeRepository) CountValues(ctx context.Context, field repository.AutocompleteField, prefix string) (map[string]int64, error) {
+	paths, ok := autocompletePaths[field]
+	if !ok {
+		return map[string]int64{}, nil
+	}
+	pattern := "^" + regexp.QuoteMeta(strings.ToLower(prefix))
+	match := bson.M{paths.folded: bson.M{"$regex": primitive.Regex{Pattern: pattern}, "$ne": ""}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$and": []bson.M{match, moderationFilter(repository.ListOptions{})}}}},
+	}
+	path := paths.value
+	if strings.HasPrefix(path, "ingredients.") {
+		// The recipes found may also use other ingredients; only those
+		// matching the prefix are counted
+		pipeline = append(pipeline,
+			bson.D{{Key: "$unwind", Value: "$ingredients"}},
+			bson.D{{Key: "$match", Value: bson.M{path: bson.M{
+				"$regex": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix), Options: "i"},
+				"$ne":    "",
+			}}}},
+		)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{"_id": "$" + path, "count": bson.M{"$sum": 1}}}})
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Value string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(results))
+	for _, result := range results {
+		counts[result.Value] = result.Count
+	}
+	return counts, nil
 }
 
 // FindByIngredient implements RecipeRepository.FindByIngredient
-func (r *RecipeRepository) FindByIngredient(ctx context.Context, ingredient string) ([]*entity.Recipe, error) {
-	filter := bson.M{"ingredients.name": bson.M{"$regex": primitive.Regex{Pattern: ingredient, Options: "i"}}}
-	
-	cursor, err := r.collection.Find(ctx, filter)
+func (r *RecipeRepository) FindByIngredient(ctx context.Context, match repository.IngredientMatch, opts repository.ListOptions) (*repository.RecipePage, error) {
+	or := []bson.M{}
+	for _, name := range match.Names {
+		or = append(or, bson.M{"ingredients.name": bson.M{
+			"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(name), Options: "i"},
+		}})
+	}
+	if len(match.CatalogIDs) > 0 {
+		or = append(or, bson.M{"ingredients.catalog_id": bson.M{"$in": match.CatalogIDs}})
+	}
+	if len(or) == 0 {
+		return &repository.RecipePage{}, nil
+	}
+	return r.findPage(ctx, bson.M{"$or": or}, opts)
+}
+
+// Search implements RecipeRepository.Search. Candidates are the recipes
+// sharing the most trigrams with the query; they are scored and paged in Go.
+func (r *RecipeRepository) Search(ctx context.Context, query search.Query, filter repository.SearchFilter, opts repository.ListOptions) (*repository.RecipePage, error) {
+	match := bson.M{}
+	if filter.Type != nil {
+		match["type"] = *filter.Type
+	}
+	if filter.MinABV != nil || filter.MaxABV != nil {
+		abv := bson.M{"$ne": nil}
+		if filter.MinABV != nil {
+			abv["$gte"] = *filter.MinABV
+		}
+		if filter.MaxABV != nil {
+			abv["$lte"] = *filter.MaxABV
+		}
+		match["abv"] = abv
+	}
+	if query.Empty() {
+		return r.findPage(ctx, match, opts)
+	}
+
+	trigrams := query.Trigrams()
+	match["search_trigrams"] = bson.M{"$in": trigrams}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$and": []bson.M{match, moderationFilter(opts)}}}},
+		{{Key: "$addFields", Value: bson.M{
+			"shared_trigrams": bson.M{"$size": bson.M{"$setIntersection": bson.A{"$search_trigrams", trigrams}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "shared_trigrams", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: repository.MaxSearchCandidates}},
+		{{Key: "$project", Value: bson.M{"search_trigrams": 0, "shared_trigrams": 0}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(ctx)
 
-	var recipes []*entity.Recipe
-	if err = cursor.All(ctx, &recipes); err != nil {
+	var candidates []*entity.Recipe
+	if err := cursor.All(ctx, &candidates); err != nil {
 		return nil, err
 	}
-	return recipes, nil
+
+	var matches []repository.ScoredRecipe
+	for _, recipe := range candidates {
+		if score := search.Score(recipe, query); score > 0 {
+			matches = append(matches, repository.ScoredRecipe{Recipe: recipe, Score: score})
+		}
+	}
+	return repository.PageOf(matches, opts)
 }
 
-// Search implements RecipeRepository.Search
-func (r *RecipeRepository) Search(ctx context.Context, query string, recipeType *entity.RecipeType) ([]*entity.Recipe, error) {
-	filter := bson.M{
-		"$or": []bson.M{
-			{"$text": bson.M{"$search": query}},
-			{"ingredients.name": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
-		},
+// moderationFilter selects the recipes opts lets the caller see
+func moderationFilter(opts repository.ListOptions) bson.M {
+	moderation := bson.M{}
+	if !opts.IncludeHidden {
+		moderation["hidden"] = bson.M{"$ne": true}
+	}
+	if opts.FeaturedOnly {
+		moderation["featured"] = true
 	}
+	return moderation
+}
 
-	if recipeType != nil {
-		filter["type"] = *recipeType
+// findPage returns one page of the recipes matching filter, using keyset
+// pagination on (field, _id). Search ranks and pages relevance sorts
+// itself.
+func (r *RecipeRepository) findPage(ctx context.Context, filter bson.M, opts repository.ListOptions) (*repository.RecipePage, error) {
+	after, err := opts.After()
+	if err != nil {
+		return nil, err
 	}
 
-	opts := options.Find().
-		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}})
+	if moderation := moderationFilter(opts); len(moderation) > 0 {
+		filter = bson.M{"$and": []bson.M{filter, moderation}}
+	}
 
-	cursor, err := r.collection.Find(ctx, filter, opts)
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := 1
+	cmp := "$gt"
+	if opts.Descending {
+		dir = -1
+		cmp = "$lt"
+	}
+
+	field := string(opts.Sort)
+	findOpts := options.Find().
+		SetLimit(int64(opts.Limit) + 1).
+		SetSort(bson.D{{Key: field, Value: dir}, {Key: "_id", Value: dir}})
+	if after != nil {
+		var key interface{} = after.Time
+		switch opts.Sort {
+		case repository.SortByName:
+			key = after.Name
+		case repository.SortByRating:
+			key = after.Rating
+		}
+		filter = bson.M{"$and": []bson.M{filter, {
+			"$or": []bson.M{
+				{field: bson.M{cmp: key}},
+				{field: key, "_id": bson.M{cmp: after.ID}},
+			},
+		}}}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, findOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -159,5 +549,77 @@ func (r *RecipeRepository) Search(ctx context.Context, query string, recipeType
 	if err = cursor.All(ctx, &recipes); err != nil {
 		return nil, err
 	}
-	return recipes, nil
-} 
\ No newline at end of file
+
+	page := &repository.RecipePage{Recipes: recipes, Total: total}
+	if len(recipes) > opts.Limit {
+		page.Recipes = recipes[:opts.Limit]
+		page.NextCursor = opts.NextCursor(page.Recipes[opts.Limit-1], opts.Limit-1)
+	}
+	return page, nil
+}
+
+// FindMakeable implements RecipeRepository.FindMakeable. The coverage check
+// runs entirely inside an aggregation pipeline so only the matching recipes
+// ever leave the database.
+func (r *RecipeRepository) FindMakeable(ctx context.Context, available repository.AvailableIngredients, maxMissing, limit int) ([]*repository.MakeableRecipe, error) {
+	names := available.Names
+	if names == nil {
+		names = []string{}
+	}
+	ids := available.CatalogIDs
+	if ids == nil {
+		ids = []primitive.ObjectID{}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"hidden": bson.M{"$ne": true}}}},
+		{{Key: "$addFields", Value: bson.M{
+			"missing": bson.M{"$map": bson.M{
+				"input": bson.M{"$filter": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$required_ingredients", bson.A{}}},
+					"as":    "ing",
+					"cond": bson.M{"$and": bson.A{
+						bson.M{"$not": bson.A{bson.M{"$in": bson.A{"$$ing.normalized", names}}}},
+						bson.M{"$not": bson.A{bson.M{"$in": bson.A{
+							bson.M{"$ifNull": bson.A{"$$ing.catalog_id", nil}},
+							ids,
+						}}}},
+					}},
+				}},
+				"as": "ing",
+				"in": "$$ing.name",
+			}},
+		}}},
+		{{Key: "$addFields", Value: bson.M{"missing_count": bson.M{"$size": "$missing"}}}},
+		{{Key: "$match", Value: bson.M{"missing_count": bson.M{"$lte": maxMissing}}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "missing_count", Value: 1},
+			{Key: "name", Value: 1},
+			{Key: "_id", Value: 1},
+		}}},
+		{{Key: "$limit", Value: limit}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		entity.Recipe `bson:",inline"`
+		Missing       []string `bson:"missing"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	matches := make([]*repository.MakeableRecipe, 0, len(docs))
+	for i := range docs {
+		matches = append(matches, &repository.MakeableRecipe{
+			Recipe:  &docs[i].Recipe,
+			Missing: docs[i].Missing,
+		})
+	}
+	return matches, nil
+}
@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReviewRepository implements the domain.ReviewRepository interface
+type ReviewRepository struct {
+	collection *mongo.Collection
+}
+
+// NewReviewRepository creates a new ReviewRepository
+func NewReviewRepository(db *mongo.Database) *ReviewRepository {
+	return &ReviewRepository{
+		collection: db.Collection("recipe_reviews"),
+	}
+}
+
+// Create implements ReviewRepository.Create
+func (r *ReviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	result, err := r.collection.InsertOne(ctx, review)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrDuplicateReview
+		}
+		return err
+	}
+	review.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID implements ReviewRepository.FindByID
+func (r *ReviewRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Review, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindByRecipeAndUser implements ReviewRepository.FindByRecipeAndUser
+func (r *ReviewRepository) FindByRecipeAndUser(ctx context.Context, recipeID, userID primitive.ObjectID) (*entity.Review, error) {
+	return r.findOne(ctx, bson.M{"recipe_id": recipeID, "user_id": userID})
+}
+
+// FindByRecipe implements ReviewRepository.FindByRecipe
+func (r *ReviewRepository) FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Review, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"recipe_id": recipeID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []*entity.Review{}
+	if err = cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// Update implements ReviewRepository.Update
+func (r *ReviewRepository) Update(ctx context.Context, review *entity.Review) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": review.ID}, review)
+	return err
+}
+
+// Delete implements ReviewRepository.Delete
+func (r *ReviewRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+// DeleteByRecipe implements ReviewRepository.DeleteByRecipe
+func (r *ReviewRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"recipe_id": recipeID})
+	return err
+}
+
+// Summarize implements ReviewRepository.Summarize
+func (r *ReviewRepository) Summarize(ctx context.Context, recipeID primitive.ObjectID) (float64, int, error) {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"recipe_id": recipeID}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$rating"},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Average float64 `bson:"average"`
+		Count   int     `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, 0, err
+	}
+	if len(results) == 0 {
+		return 0, 0, nil
+	}
+	return results[0].Average, results[0].Count, nil
+}
+
+func (r *ReviewRepository) findOne(ctx context.Context, filter bson.M) (*entity.Review, error) {
+	var review entity.Review
+	err := r.collection.FindOne(ctx, filter).Decode(&review)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &review, nil
+}
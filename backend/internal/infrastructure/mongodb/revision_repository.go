@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RevisionRepository implements the domain.RevisionRepository interface
+type RevisionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRevisionRepository creates a new RevisionRepository
+func NewRevisionRepository(db *mongo.Database) *RevisionRepository {
+	return &RevisionRepository{
+		collection: db.Collection("recipe_revisions"),
+	}
+}
+
+// Create implements RevisionRepository.Create
+func (r *RevisionRepository) Create(ctx context.Context, revision *entity.Revision) error {
+	result, err := r.collection.InsertOne(ctx, revision)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicateRevision
+	}
+	if err != nil {
+		return err
+	}
+	revision.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByRecipe implements RevisionRepository.FindByRecipe
+func (r *RevisionRepository) FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Revision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "number", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"recipe_id": recipeID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var revisions []*entity.Revision
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// FindByNumber implements RevisionRepository.FindByNumber
+func (r *RevisionRepository) FindByNumber(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error) {
+	return r.findOne(ctx, bson.M{"recipe_id": recipeID, "number": number})
+}
+
+// FindBefore implements RevisionRepository.FindBefore
+func (r *RevisionRepository) FindBefore(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}})
+	return r.findOne(ctx, bson.M{"recipe_id": recipeID, "number": bson.M{"$lt": number}}, opts)
+}
+
+// FindLatest implements RevisionRepository.FindLatest
+func (r *RevisionRepository) FindLatest(ctx context.Context, recipeID primitive.ObjectID) (*entity.Revision, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "number", Value: -1}})
+	return r.findOne(ctx, bson.M{"recipe_id": recipeID}, opts)
+}
+
+// DeleteByRecipe implements RevisionRepository.DeleteByRecipe
+func (r *RevisionRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"recipe_id": recipeID})
+	return err
+}
+
+func (r *RevisionRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*entity.Revision, error) {
+	var revision entity.Revision
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&revision)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
@@ -0,0 +1,67 @@
+package mongodb
+
+import (
+	"context"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UserRepository implements the domain.UserRepository interface
+type UserRepository struct {
+	collection *mongo.Collection
+}
+
+// NewUserRepository creates a new UserRepository
+func NewUserRepository(db *mongo.Database) *UserRepository {
+	return &UserRepository{
+		collection: db.Collection("users"),
+	}
+}
+
+// Create implements UserRepository.Create
+func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	result, err := r.collection.InsertOne(ctx, user)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return repository.ErrDuplicateEmail
+		}
+		return err
+	}
+	user.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// FindByID implements UserRepository.FindByID
+func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.User, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindByEmail implements UserRepository.FindByEmail
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.findOne(ctx, bson.M{"email": entity.NormalizeEmail(email)})
+}
+
+// Update implements UserRepository.Update
+func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": user.ID}, user)
+	if mongo.IsDuplicateKeyError(err) {
+		return repository.ErrDuplicateEmail
+	}
+	return err
+}
+
+func (r *UserRepository) findOne(ctx context.Context, filter bson.M) (*entity.User, error) {
+	var user entity.User
+	err := r.collection.FindOne(ctx, filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
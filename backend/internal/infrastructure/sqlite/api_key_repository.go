@@ -0,0 +1,118 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const apiKeyColumns = `id, user_id, name, prefix, hash, scope, expires_at, last_used_at, created_at`
+
+// APIKeyRepository implements the domain.APIKeyRepository interface
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new APIKeyRepository
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+// Create implements APIKeyRepository.Create
+func (r *APIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.db.ExecContext(ctx, `INSERT INTO api_keys (`+apiKeyColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		key.ID.Hex(), key.UserID.Hex(), key.Name, key.Prefix, key.Hash, string(key.Scope),
+		nullableTime(key.ExpiresAt), nullableTime(key.LastUsedAt), toUnix(key.CreatedAt))
+	return err
+}
+
+// FindByID implements APIKeyRepository.FindByID
+func (r *APIKeyRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.APIKey, error) {
+	return r.findOne(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = ?`, id.Hex())
+}
+
+// FindByHash implements APIKeyRepository.FindByHash
+func (r *APIKeyRepository) FindByHash(ctx context.Context, hash string) (*entity.APIKey, error) {
+	return r.findOne(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE hash = ?`, hash)
+}
+
+// FindByUser implements APIKeyRepository.FindByUser
+func (r *APIKeyRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE user_id = ? ORDER BY created_at DESC, id DESC`, userID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*entity.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// TouchLastUsed implements APIKeyRepository.TouchLastUsed
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = ? WHERE id = ?`, toUnix(at), id.Hex())
+	return err
+}
+
+// Delete implements APIKeyRepository.Delete
+func (r *APIKeyRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM api_keys WHERE id = ?`, id.Hex())
+	return err
+}
+
+func (r *APIKeyRepository) findOne(ctx context.Context, query string, args ...interface{}) (*entity.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanAPIKey(rows)
+}
+
+func scanAPIKey(rows *sql.Rows) (*entity.APIKey, error) {
+	var (
+		key                   entity.APIKey
+		id, userID, scope     string
+		expiresAt, lastUsedAt sql.NullInt64
+		createdAt             int64
+	)
+	err := rows.Scan(&id, &userID, &key.Name, &key.Prefix, &key.Hash, &scope,
+		&expiresAt, &lastUsedAt, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if key.UserID, err = primitive.ObjectIDFromHex(userID); err != nil {
+		return nil, err
+	}
+	key.Scope = entity.APIKeyScope(scope)
+	key.ExpiresAt = parseNullableTime(expiresAt)
+	key.LastUsedAt = parseNullableTime(lastUsedAt)
+	key.CreatedAt = fromUnix(createdAt)
+	return &key, nil
+}
@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const collectionColumns = `id, owner_id, name, description, public, share_token, version, created_at, updated_at`
+
+// CollectionRepository implements the domain.CollectionRepository interface.
+// Entries live in collection_entries, ordered by position.
+type CollectionRepository struct {
+	db *sql.DB
+}
+
+// NewCollectionRepository creates a new CollectionRepository
+func NewCollectionRepository(db *sql.DB) *CollectionRepository {
+	return &CollectionRepository{
+		db: db,
+	}
+}
+
+// Create implements CollectionRepository.Create
+func (r *CollectionRepository) Create(ctx context.Context, collection *entity.Collection) error {
+	if collection.ID.IsZero() {
+		collection.ID = primitive.NewObjectID()
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO collections (`+collectionColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			collection.ID.Hex(), collection.OwnerID.Hex(), collection.Name, collection.Description,
+			collection.Public, nullableToken(collection.ShareToken), collection.Version,
+			toUnix(collection.CreatedAt), toUnix(collection.UpdatedAt))
+		if err != nil {
+			return err
+		}
+		return writeCollectionEntries(ctx, tx, collection)
+	})
+}
+
+// FindByID implements CollectionRepository.FindByID
+func (r *CollectionRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Collection, error) {
+	return r.queryOne(ctx, `SELECT `+collectionColumns+` FROM collections WHERE id = ?`, id.Hex())
+}
+
+// FindByShareToken implements CollectionRepository.FindByShareToken
+func (r *CollectionRepository) FindByShareToken(ctx context.Context, token string) (*entity.Collection, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return r.queryOne(ctx, `SELECT `+collectionColumns+` FROM collections WHERE share_token = ?`, token)
+}
+
+// FindByOwner implements CollectionRepository.FindByOwner
+func (r *CollectionRepository) FindByOwner(ctx context.Context, ownerID primitive.ObjectID) ([]*entity.Collection, error) {
+	return r.query(ctx, `SELECT `+collectionColumns+` FROM collections
+		WHERE owner_id = ? ORDER BY updated_at DESC, id DESC`, ownerID.Hex())
+}
+
+// CountByOwner implements CollectionRepository.CountByOwner
+func (r *CollectionRepository) CountByOwner(ctx context.Context, ownerID primitive.ObjectID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM collections WHERE owner_id = ?`,
+		ownerID.Hex()).Scan(&count)
+	return count, err
+}
+
+// Update implements CollectionRepository.Update
+func (r *CollectionRepository) Update(ctx context.Context, collection *entity.Collection, expectedVersion int64) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE collections SET name = ?, description = ?, public = ?,
+			share_token = ?, version = ?, updated_at = ? WHERE id = ? AND version = ?`,
+			collection.Name, collection.Description, collection.Public, nullableToken(collection.ShareToken),
+			collection.Version, toUnix(collection.UpdatedAt), collection.ID.Hex(), expectedVersion)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return repository.ErrCollectionConflict
+		}
+
+		_, err = tx.ExecContext(ctx, `DELETE FROM collection_entries WHERE collection_id = ?`, collection.ID.Hex())
+		if err != nil {
+			return err
+		}
+		return writeCollectionEntries(ctx, tx, collection)
+	})
+}
+
+// Delete implements CollectionRepository.Delete. Its entries go with it
+// through the foreign key.
+func (r *CollectionRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM collections WHERE id = ?`, id.Hex())
+	return err
+}
+
+// RemoveRecipe implements CollectionRepository.RemoveRecipe. Positions are
+// left with gaps, which only order entries and never address them.
+func (r *CollectionRepository) RemoveRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := bumpCollectionsHolding(ctx, tx, recipeID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM collection_entries WHERE recipe_id = ?`, recipeID.Hex())
+		return err
+	})
+}
+
+// bumpCollectionsHolding bumps the version of every collection a recipe is
+// in, so saves that read them before the recipe was taken out fail
+func bumpCollectionsHolding(ctx context.Context, tx *sql.Tx, recipeID primitive.ObjectID) error {
+	_, err := tx.ExecContext(ctx, `UPDATE collections SET version = version + 1
+		WHERE id IN (SELECT collection_id FROM collection_entries WHERE recipe_id = ?)`, recipeID.Hex())
+	return err
+}
+
+func (r *CollectionRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*entity.Collection, error) {
+	collections, err := r.query(ctx, query, args...)
+	if err != nil || len(collections) == 0 {
+		return nil, err
+	}
+	return collections[0], nil
+}
+
+// query runs a SELECT over collectionColumns and loads the entries of every
+// returned collection
+func (r *CollectionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Collection, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []*entity.Collection{}
+	byID := make(map[string]*entity.Collection)
+	for rows.Next() {
+		var (
+			collection           entity.Collection
+			id, ownerID          string
+			shareToken           sql.NullString
+			createdAt, updatedAt int64
+		)
+		err := rows.Scan(&id, &ownerID, &collection.Name, &collection.Description, &collection.Public,
+			&shareToken, &collection.Version, &createdAt, &updatedAt)
+		if err == nil {
+			collection.ID, err = primitive.ObjectIDFromHex(id)
+		}
+		if err == nil {
+			collection.OwnerID, err = primitive.ObjectIDFromHex(ownerID)
+		}
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		collection.ShareToken = shareToken.String
+		collection.CreatedAt = fromUnix(createdAt)
+		collection.UpdatedAt = fromUnix(updatedAt)
+		collection.Entries = []entity.CollectionEntry{}
+		collections = append(collections, &collection)
+		byID[id] = &collection
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	if len(collections) == 0 {
+		return collections, nil
+	}
+	if err := r.loadEntries(ctx, byID); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
+
+// loadEntries fills in the entries of the given collections
+func (r *CollectionRepository) loadEntries(ctx context.Context, byID map[string]*entity.Collection) error {
+	ids := make([]interface{}, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT collection_id, recipe_id, note, added_at
+		FROM collection_entries WHERE collection_id IN (`+placeholders(len(ids))+`)
+		ORDER BY collection_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			collectionID, recipeID string
+			entry                  entity.CollectionEntry
+			addedAt                int64
+		)
+		if err := rows.Scan(&collectionID, &recipeID, &entry.Note, &addedAt); err != nil {
+			return err
+		}
+		if entry.RecipeID, err = primitive.ObjectIDFromHex(recipeID); err != nil {
+			return err
+		}
+		entry.AddedAt = fromUnix(addedAt)
+		collection := byID[collectionID]
+		collection.Entries = append(collection.Entries, entry)
+	}
+	return rows.Err()
+}
+
+func writeCollectionEntries(ctx context.Context, tx *sql.Tx, collection *entity.Collection) error {
+	for i, entry := range collection.Entries {
+		_, err := tx.ExecContext(ctx, `INSERT INTO collection_entries
+			(collection_id, position, recipe_id, note, added_at) VALUES (?, ?, ?, ?, ?)`,
+			collection.ID.Hex(), i, entry.RecipeID.Hex(), entry.Note, toUnix(entry.AddedAt))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullableToken stores an unshared collection's empty token as NULL so the
+// unique index only applies to real tokens
+func nullableToken(token string) interface{} {
+	if token == "" {
+		return nil
+	}
+	return token
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FavoriteRepository implements the domain.FavoriteRepository interface
+type FavoriteRepository struct {
+	db *sql.DB
+}
+
+// NewFavoriteRepository creates a new FavoriteRepository
+func NewFavoriteRepository(db *sql.DB) *FavoriteRepository {
+	return &FavoriteRepository{
+		db: db,
+	}
+}
+
+// Add implements FavoriteRepository.Add
+func (r *FavoriteRepository) Add(ctx context.Context, favorite *entity.Favorite) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO favorites (user_id, recipe_id, created_at)
+		VALUES (?, ?, ?) ON CONFLICT (user_id, recipe_id) DO NOTHING`,
+		favorite.UserID.Hex(), favorite.RecipeID.Hex(), toUnix(favorite.CreatedAt))
+	return err
+}
+
+// Remove implements FavoriteRepository.Remove
+func (r *FavoriteRepository) Remove(ctx context.Context, userID, recipeID primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM favorites WHERE user_id = ? AND recipe_id = ?`,
+		userID.Hex(), recipeID.Hex())
+	return err
+}
+
+// FindByUser implements FavoriteRepository.FindByUser
+func (r *FavoriteRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*entity.Favorite, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT recipe_id, created_at FROM favorites
+		WHERE user_id = ? ORDER BY created_at DESC, recipe_id DESC`, userID.Hex())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	favorites := []*entity.Favorite{}
+	for rows.Next() {
+		var (
+			recipeID  string
+			createdAt int64
+		)
+		if err := rows.Scan(&recipeID, &createdAt); err != nil {
+			return nil, err
+		}
+		favorite := &entity.Favorite{UserID: userID, CreatedAt: fromUnix(createdAt)}
+		if favorite.RecipeID, err = primitive.ObjectIDFromHex(recipeID); err != nil {
+			return nil, err
+		}
+		favorites = append(favorites, favorite)
+	}
+	return favorites, rows.Err()
+}
+
+// Exists implements FavoriteRepository.Exists
+func (r *FavoriteRepository) Exists(ctx context.Context, userID, recipeID primitive.ObjectID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM favorites WHERE user_id = ? AND recipe_id = ?)`,
+		userID.Hex(), recipeID.Hex()).Scan(&exists)
+	return exists, err
+}
+
+// CountByUser implements FavoriteRepository.CountByUser
+func (r *FavoriteRepository) CountByUser(ctx context.Context, userID primitive.ObjectID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM favorites WHERE user_id = ?`,
+		userID.Hex()).Scan(&count)
+	return count, err
+}
+
+// DeleteByRecipe implements FavoriteRepository.DeleteByRecipe
+func (r *FavoriteRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM favorites WHERE recipe_id = ?`, recipeID.Hex())
+	return err
+}
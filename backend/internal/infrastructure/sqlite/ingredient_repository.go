@@ -0,0 +1,198 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const ingredientColumns = `g.id, g.name, g.aliases, g.category, g.abv, g.base_spirit, g.created_at, g.updated_at`
+
+// IngredientRepository implements the domain.IngredientRepository interface.
+// Aliases are stored as a JSON array; the ingredient_keys table indexes the
+// normalized name and aliases and keeps them unique across the catalog.
+type IngredientRepository struct {
+	db *sql.DB
+}
+
+// NewIngredientRepository creates a new IngredientRepository
+func NewIngredientRepository(db *sql.DB) *IngredientRepository {
+	return &IngredientRepository{
+		db: db,
+	}
+}
+
+// Create implements IngredientRepository.Create
+func (r *IngredientRepository) Create(ctx context.Context, ingredient *entity.CatalogIngredient) error {
+	if ingredient.ID.IsZero() {
+		ingredient.ID = primitive.NewObjectID()
+	}
+	aliases, err := json.Marshal(ingredient.Aliases)
+	if err != nil {
+		return err
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkIngredientKeys(ctx, tx, ingredient); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO ingredients
+			(id, name, aliases, category, abv, base_spirit, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			ingredient.ID.Hex(), ingredient.Name, string(aliases), string(ingredient.Category),
+			ingredient.ABV, ingredient.BaseSpirit, toUnix(ingredient.CreatedAt), toUnix(ingredient.UpdatedAt))
+		if err != nil {
+			return err
+		}
+		return writeIngredientKeys(ctx, tx, ingredient)
+	})
+}
+
+// FindByID implements IngredientRepository.FindByID
+func (r *IngredientRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.CatalogIngredient, error) {
+	ingredients, err := r.query(ctx, `SELECT `+ingredientColumns+` FROM ingredients g WHERE g.id = ?`, id.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if len(ingredients) == 0 {
+		return nil, nil
+	}
+	return ingredients[0], nil
+}
+
+// FindByNames implements IngredientRepository.FindByNames
+func (r *IngredientRepository) FindByNames(ctx context.Context, names []string) ([]*entity.CatalogIngredient, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(names))
+	for i, name := range names {
+		args[i] = entity.NormalizeIngredientName(name)
+	}
+	return r.query(ctx, `SELECT `+ingredientColumns+` FROM ingredients g
+		WHERE g.id IN (SELECT ingredient_id FROM ingredient_keys WHERE key IN (`+placeholders(len(args))+`))
+		ORDER BY g.name, g.id`, args...)
+}
+
+// List implements IngredientRepository.List
+func (r *IngredientRepository) List(ctx context.Context, category entity.IngredientCategory) ([]*entity.CatalogIngredient, error) {
+	if category == "" {
+		return r.query(ctx, `SELECT `+ingredientColumns+` FROM ingredients g ORDER BY g.name, g.id`)
+	}
+	return r.query(ctx, `SELECT `+ingredientColumns+` FROM ingredients g
+		WHERE g.category = ? ORDER BY g.name, g.id`, string(category))
+}
+
+// Update implements IngredientRepository.Update
+func (r *IngredientRepository) Update(ctx context.Context, ingredient *entity.CatalogIngredient) error {
+	aliases, err := json.Marshal(ingredient.Aliases)
+	if err != nil {
+		return err
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkIngredientKeys(ctx, tx, ingredient); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE ingredients SET
+			name = ?, aliases = ?, category = ?, abv = ?, base_spirit = ?, created_at = ?, updated_at = ?
+			WHERE id = ?`,
+			ingredient.Name, string(aliases), string(ingredient.Category), ingredient.ABV,
+			ingredient.BaseSpirit, toUnix(ingredient.CreatedAt), toUnix(ingredient.UpdatedAt),
+			ingredient.ID.Hex())
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM ingredient_keys WHERE ingredient_id = ?`,
+			ingredient.ID.Hex()); err != nil {
+			return err
+		}
+		return writeIngredientKeys(ctx, tx, ingredient)
+	})
+}
+
+// Delete implements IngredientRepository.Delete
+func (r *IngredientRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM ingredient_keys WHERE ingredient_id = ?`, id.Hex()); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM ingredients WHERE id = ?`, id.Hex())
+		return err
+	})
+}
+
+func (r *IngredientRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.CatalogIngredient, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ingredients := []*entity.CatalogIngredient{}
+	for rows.Next() {
+		var (
+			ingredient           entity.CatalogIngredient
+			id, aliases          string
+			category             string
+			abv                  sql.NullFloat64
+			createdAt, updatedAt int64
+		)
+		err := rows.Scan(&id, &ingredient.Name, &aliases, &category, &abv,
+			&ingredient.BaseSpirit, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if ingredient.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(aliases), &ingredient.Aliases); err != nil {
+			return nil, err
+		}
+		ingredient.Category = entity.IngredientCategory(category)
+		if abv.Valid {
+			ingredient.ABV = &abv.Float64
+		}
+		ingredient.CreatedAt = fromUnix(createdAt)
+		ingredient.UpdatedAt = fromUnix(updatedAt)
+		ingredients = append(ingredients, &ingredient)
+	}
+	return ingredients, rows.Err()
+}
+
+// checkIngredientKeys fails with ErrDuplicateIngredient when another
+// ingredient already uses one of the ingredient's keys
+func checkIngredientKeys(ctx context.Context, tx *sql.Tx, ingredient *entity.CatalogIngredient) error {
+	keys := ingredient.Keys()
+	args := make([]interface{}, 0, len(keys)+1)
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	args = append(args, ingredient.ID.Hex())
+
+	var n int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM ingredient_keys
+		WHERE key IN (`+placeholders(len(keys))+`) AND ingredient_id != ?`, args...).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrDuplicateIngredient
+	}
+	return nil
+}
+
+func writeIngredientKeys(ctx context.Context, tx *sql.Tx, ingredient *entity.CatalogIngredient) error {
+	for _, key := range ingredient.Keys() {
+		_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO ingredient_keys (key, ingredient_id) VALUES (?, ?)`,
+			key, ingredient.ID.Hex())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,551 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// migration is a single, append-only schema change. Once a migration has
+// shipped it must never be edited; add a new one instead.
+type migration struct {
+	version     int
+	description string
+	statements  []string
+	// backfill runs after the statements, in the same transaction, for data
+	// changes SQL alone cannot make
+	backfill func(ctx context.Context, tx *sql.Tx) error
+}
+
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create recipes, ingredients, instructions and search index",
+		statements: []string{
+			`CREATE TABLE recipes (
+				id          TEXT PRIMARY KEY,
+				name        TEXT NOT NULL,
+				type        TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				glass       TEXT NOT NULL DEFAULT '',
+				garnish     TEXT NOT NULL DEFAULT '',
+				created_at  INTEGER NOT NULL,
+				updated_at  INTEGER NOT NULL
+			)`,
+			`CREATE INDEX recipe_type ON recipes (type)`,
+			`CREATE TABLE recipe_ingredients (
+				recipe_id   TEXT NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+				position    INTEGER NOT NULL,
+				name        TEXT NOT NULL,
+				amount      REAL NOT NULL DEFAULT 0,
+				unit        TEXT NOT NULL DEFAULT '',
+				notes       TEXT NOT NULL DEFAULT '',
+				is_optional INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (recipe_id, position)
+			)`,
+			`CREATE INDEX recipe_ingredients_name ON recipe_ingredients (name COLLATE NOCASE)`,
+			`CREATE TABLE recipe_instructions (
+				recipe_id TEXT NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+				position  INTEGER NOT NULL,
+				text      TEXT NOT NULL,
+				PRIMARY KEY (recipe_id, position)
+			)`,
+			`CREATE VIRTUAL TABLE recipe_search USING fts5 (
+				recipe_id UNINDEXED,
+				name,
+				description,
+				ingredients,
+				tokenize = 'unicode61'
+			)`,
+		},
+	},
+	{
+		version:     2,
+		description: "track the recipe a recipe was forked from",
+		statements: []string{
+			`ALTER TABLE recipes ADD COLUMN forked_from TEXT`,
+			`CREATE INDEX recipe_forked_from ON recipes (forked_from)`,
+		},
+	},
+	{
+		version:     3,
+		description: "create recipe revisions",
+		statements: []string{
+			`CREATE TABLE recipe_revisions (
+				id            TEXT PRIMARY KEY,
+				recipe_id     TEXT NOT NULL,
+				number        INTEGER NOT NULL,
+				snapshot      TEXT NOT NULL,
+				edited_by     TEXT NOT NULL DEFAULT '',
+				restored_from INTEGER NOT NULL DEFAULT 0,
+				created_at    INTEGER NOT NULL,
+				UNIQUE (recipe_id, number)
+			)`,
+		},
+	},
+	{
+		version:     4,
+		description: "add optimistic concurrency version to recipes",
+		statements: []string{
+			`ALTER TABLE recipes ADD COLUMN version INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		version:     5,
+		description: "add technique and estimated ABV to recipes",
+		statements: []string{
+			`ALTER TABLE recipes ADD COLUMN technique TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE recipes ADD COLUMN abv REAL`,
+			`CREATE INDEX recipe_abv ON recipes (abv)`,
+		},
+	},
+	{
+		version:     6,
+		description: "create ingredient catalog and link recipe ingredients to it",
+		statements: []string{
+			`CREATE TABLE ingredients (
+				id          TEXT PRIMARY KEY,
+				name        TEXT NOT NULL,
+				aliases     TEXT NOT NULL DEFAULT '[]',
+				category    TEXT NOT NULL,
+				abv         REAL,
+				base_spirit TEXT NOT NULL DEFAULT '',
+				created_at  INTEGER NOT NULL,
+				updated_at  INTEGER NOT NULL
+			)`,
+			`CREATE INDEX ingredient_category_name ON ingredients (category, name)`,
+			// Every normalized name and alias, unique across the catalog
+			`CREATE TABLE ingredient_keys (
+				key           TEXT PRIMARY KEY,
+				ingredient_id TEXT NOT NULL REFERENCES ingredients (id) ON DELETE CASCADE
+			)`,
+			`CREATE INDEX ingredient_keys_ingredient ON ingredient_keys (ingredient_id)`,
+			`ALTER TABLE recipe_ingredients ADD COLUMN catalog_id TEXT`,
+			`CREATE INDEX recipe_ingredient_catalog ON recipe_ingredients (catalog_id)`,
+		},
+	},
+	{
+		version:     7,
+		description: "add servings, timings, yield, equipment and oven temperature to recipes",
+		statements: []string{
+			`ALTER TABLE recipes ADD COLUMN servings INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE recipes ADD COLUMN prep_minutes INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE recipes ADD COLUMN cook_minutes INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE recipes ADD COLUMN yield TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE recipes ADD COLUMN equipment TEXT NOT NULL DEFAULT '[]'`,
+			`ALTER TABLE recipes ADD COLUMN oven_degrees REAL`,
+			`ALTER TABLE recipes ADD COLUMN oven_scale TEXT`,
+		},
+	},
+	{
+		version:     8,
+		description: "create users and record the creator of each recipe",
+		statements: []string{
+			`CREATE TABLE users (
+				id            TEXT PRIMARY KEY,
+				email         TEXT NOT NULL UNIQUE,
+				display_name  TEXT NOT NULL,
+				password_hash TEXT NOT NULL,
+				role          TEXT NOT NULL,
+				created_at    INTEGER NOT NULL,
+				updated_at    INTEGER NOT NULL
+			)`,
+			`ALTER TABLE recipes ADD COLUMN creator_id TEXT`,
+			`CREATE INDEX recipe_creator ON recipes (creator_id)`,
+		},
+	},
+	{
+		version:     9,
+		description: "add moderation flags to recipes and let accounts be disabled",
+		statements: []string{
+			`ALTER TABLE recipes ADD COLUMN featured INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE recipes ADD COLUMN hidden INTEGER NOT NULL DEFAULT 0`,
+			`CREATE INDEX recipe_featured ON recipes (featured)`,
+			`ALTER TABLE users ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+	{
+		version:     10,
+		description: "create API keys",
+		statements: []string{
+			`CREATE TABLE api_keys (
+				id           TEXT PRIMARY KEY,
+				user_id      TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+				name         TEXT NOT NULL,
+				prefix       TEXT NOT NULL,
+				hash         TEXT NOT NULL UNIQUE,
+				scope        TEXT NOT NULL,
+				expires_at   INTEGER,
+				last_used_at INTEGER,
+				created_at   INTEGER NOT NULL
+			)`,
+			`CREATE INDEX api_key_user ON api_keys (user_id, created_at)`,
+		},
+	},
+	{
+		version:     11,
+		description: "create recipe reviews and keep an aggregate rating on recipes",
+		statements: []string{
+			`CREATE TABLE recipe_reviews (
+				id         TEXT PRIMARY KEY,
+				recipe_id  TEXT NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+				user_id    TEXT NOT NULL,
+				rating     INTEGER NOT NULL,
+				text       TEXT NOT NULL DEFAULT '',
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL,
+				UNIQUE (recipe_id, user_id)
+			)`,
+			`CREATE INDEX review_recipe ON recipe_reviews (recipe_id, created_at)`,
+			`ALTER TABLE recipes ADD COLUMN rating REAL NOT NULL DEFAULT 0`,
+			`ALTER TABLE recipes ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0`,
+			`CREATE INDEX recipe_rating ON recipes (rating, id)`,
+		},
+	},
+	{
+		version:     12,
+		description: "create favorites and recipe collections",
+		statements: []string{
+			`CREATE TABLE favorites (
+				user_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+				recipe_id  TEXT NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+				created_at INTEGER NOT NULL,
+				PRIMARY KEY (user_id, recipe_id)
+			)`,
+			`CREATE INDEX favorite_user ON favorites (user_id, created_at)`,
+			`CREATE INDEX favorite_recipe ON favorites (recipe_id)`,
+			`CREATE TABLE collections (
+				id          TEXT PRIMARY KEY,
+				owner_id    TEXT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+				name        TEXT NOT NULL,
+				description TEXT NOT NULL DEFAULT '',
+				public      INTEGER NOT NULL DEFAULT 0,
+				share_token TEXT UNIQUE,
+				created_at  INTEGER NOT NULL,
+				updated_at  INTEGER NOT NULL
+			)`,
+			`CREATE INDEX collection_owner ON collections (owner_id, updated_at)`,
+			`CREATE TABLE collection_entries (
+				collection_id TEXT NOT NULL REFERENCES collections (id) ON DELETE CASCADE,
+				position      INTEGER NOT NULL,
+				recipe_id     TEXT NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+				note          TEXT NOT NULL DEFAULT '',
+				added_at      INTEGER NOT NULL,
+				PRIMARY KEY (collection_id, position)
+			)`,
+			`CREATE INDEX collection_entry_recipe ON collection_entries (recipe_id)`,
+		},
+	},
+	{
+		version:     13,
+		description: "add tags, flavor profiles, base spirit and season to recipes",
+		statements: []string{
+			`ALTER TABLE recipes ADD COLUMN base_spirit TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE recipes ADD COLUMN season TEXT NOT NULL DEFAULT ''`,
+			`ALTER TABLE recipes ADD COLUMN flavor_sweet INTEGER`,
+			`ALTER TABLE recipes ADD COLUMN flavor_sour INTEGER`,
+			`ALTER TABLE recipes ADD COLUMN flavor_bitter INTEGER`,
+			`ALTER TABLE recipes ADD COLUMN flavor_boozy INTEGER`,
+			`ALTER TABLE recipes ADD COLUMN flavor_smoky INTEGER`,
+			`ALTER TABLE recipes ADD COLUMN flavor_fruity INTEGER`,
+			`CREATE INDEX recipe_base_spirit ON recipes (base_spirit)`,
+			`CREATE INDEX recipe_technique ON recipes (technique)`,
+			`CREATE INDEX recipe_season ON recipes (season)`,
+			`CREATE TABLE recipe_tags (
+				recipe_id TEXT NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+				position  INTEGER NOT NULL,
+				tag       TEXT NOT NULL,
+				PRIMARY KEY (recipe_id, position)
+			)`,
+			`CREATE INDEX recipe_tag ON recipe_tags (tag)`,
+		},
+	},
+	{
+		version:     14,
+		description: "replace the full-text index with search trigrams",
+		statements: []string{
+			`DROP TABLE recipe_search`,
+			`CREATE TABLE recipe_trigrams (
+				trigram   TEXT NOT NULL,
+				recipe_id TEXT NOT NULL REFERENCES recipes (id) ON DELETE CASCADE,
+				PRIMARY KEY (trigram, recipe_id)
+			) WITHOUT ROWID`,
+			`CREATE INDEX recipe_trigram_recipe ON recipe_trigrams (recipe_id)`,
+		},
+		backfill: indexAllRecipes,
+	},
+	{
+		version:     15,
+		description: "index the autocompleted recipe fields",
+		statements: []string{
+			`CREATE INDEX recipe_name_nocase ON recipes (name COLLATE NOCASE)`,
+			`CREATE INDEX recipe_glass ON recipes (glass COLLATE NOCASE)`,
+			`CREATE INDEX recipe_garnish ON recipes (garnish COLLATE NOCASE)`,
+			`CREATE INDEX recipe_ingredients_unit ON recipe_ingredients (unit COLLATE NOCASE)`,
+		},
+	},
+	{
+		version:     16,
+		description: "estimate the ABV of cocktails stored before it was",
+		backfill:    estimateAllABV,
+	},
+	{
+		version:     17,
+		description: "store normalized ingredient names for makeable lookups",
+		statements: []string{
+			`ALTER TABLE recipe_ingredients ADD COLUMN normalized_name TEXT NOT NULL DEFAULT ''`,
+			`CREATE INDEX recipe_ingredients_normalized_name ON recipe_ingredients (normalized_name)`,
+		},
+		backfill: normalizeAllIngredientNames,
+	},
+	{
+		version:     18,
+		description: "raise recipe versions to their latest revision number",
+		statements: []string{
+			`UPDATE recipes SET version = (
+				SELECT MAX(number) FROM recipe_revisions WHERE recipe_id = recipes.id
+			) WHERE version < (
+				SELECT MAX(number) FROM recipe_revisions WHERE recipe_id = recipes.id
+			)`,
+		},
+	},
+	{
+		version:     19,
+		description: "add optimistic concurrency version to collections",
+		statements: []string{
+			`ALTER TABLE collections ADD COLUMN version INTEGER NOT NULL DEFAULT 0`,
+		},
+	},
+}
+
+// migrate brings the schema up to the latest version, applying each pending
+// migration in its own transaction
+func migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version     INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at  INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return err
+	}
+
+	var current int
+	err = db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err := applyMigration(ctx, db, m); err != nil {
+			return fmt.Errorf("migration %d (%s): %v", m.version, m.description, err)
+		}
+		log.Printf("Applied SQLite migration %d: %s\n", m.version, m.description)
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, m migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range m.statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if m.backfill != nil {
+		if err := m.backfill(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, ?, ?)`,
+		m.version, m.description, time.Now().UnixNano())
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// indexAllRecipes writes the search trigrams of every stored recipe
+func indexAllRecipes(ctx context.Context, tx *sql.Tx) error {
+	recipes := make(map[string]*entity.Recipe)
+	rows, err := tx.QueryContext(ctx, `SELECT id, name, description FROM recipes`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id string
+		recipe := &entity.Recipe{}
+		if err := rows.Scan(&id, &recipe.Name, &recipe.Description); err != nil {
+			rows.Close()
+			return err
+		}
+		recipes[id] = recipe
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	rows, err = tx.QueryContext(ctx, `SELECT recipe_id, name FROM recipe_ingredients ORDER BY recipe_id, position`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return err
+		}
+		if recipe := recipes[id]; recipe != nil {
+			recipe.Ingredients = append(recipe.Ingredients, entity.Ingredient{Name: name})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for id, recipe := range recipes {
+		if err := writeSearchTrigrams(ctx, tx, id, recipe); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// estimateAllABV stores the estimated strength of every cocktail saved
+// without one, looking linked ingredients up in the catalog
+func estimateAllABV(ctx context.Context, tx *sql.Tx) error {
+	recipes := make(map[string]*entity.Recipe)
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, technique FROM recipes WHERE type = ? AND abv IS NULL`, entity.RecipeTypeCocktail)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id string
+		recipe := &entity.Recipe{Type: entity.RecipeTypeCocktail}
+		if err := rows.Scan(&id, &recipe.Technique); err != nil {
+			rows.Close()
+			return err
+		}
+		recipes[id] = recipe
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	rows, err = tx.QueryContext(ctx, `SELECT recipe_id, name, amount, unit, is_optional, catalog_id
+		FROM recipe_ingredients ORDER BY recipe_id, position`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id string
+		var ing entity.Ingredient
+		var catalogID sql.NullString
+		if err := rows.Scan(&id, &ing.Name, &ing.Amount, &ing.Unit, &ing.IsOptional, &catalogID); err != nil {
+			rows.Close()
+			return err
+		}
+		if ing.CatalogID, err = parseNullableID(catalogID); err != nil {
+			rows.Close()
+			return err
+		}
+		if recipe := recipes[id]; recipe != nil {
+			recipe.Ingredients = append(recipe.Ingredients, ing)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	entries := make(map[primitive.ObjectID]*entity.CatalogIngredient)
+	rows, err = tx.QueryContext(ctx, `SELECT id, abv FROM ingredients WHERE abv IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var id string
+		var abv float64
+		if err := rows.Scan(&id, &abv); err != nil {
+			rows.Close()
+			return err
+		}
+		entryID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		entries[entryID] = &entity.CatalogIngredient{ID: entryID, ABV: &abv}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for id, recipe := range recipes {
+		recipe.RefreshABV(entity.CatalogABV(entries))
+		if recipe.ABV == nil {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE recipes SET abv = ? WHERE id = ?`, *recipe.ABV, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeAllIngredientNames fills in the normalized name of every stored
+// recipe ingredient
+func normalizeAllIngredientNames(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT name FROM recipe_ingredients`)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for _, name := range names {
+		_, err := tx.ExecContext(ctx, `UPDATE recipe_ingredients SET normalized_name = ? WHERE name = ?`,
+			entity.NormalizeIngredientName(name), name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
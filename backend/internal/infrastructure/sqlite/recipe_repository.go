@@ -0,0 +1,891 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/domain/search"
+	"fork-and-shaker/internal/domain/units"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recipeColumns is the column list every recipe query selects, in the order
+// scanRecipe expects them
+const recipeColumns = `r.id, r.name, r.type, r.description, r.glass, r.garnish, r.technique,
+	r.base_spirit, r.season, r.abv, r.servings, r.prep_minutes, r.cook_minutes, r.yield, r.equipment, r.oven_degrees,
+	r.oven_scale, r.creator_id, r.forked_from, r.featured, r.hidden, r.rating, r.rating_count,
+	r.version, r.created_at, r.updated_at, ` + flavorColumnList
+
+// flavorColumnList holds the flavor profile, one column per axis in the
+// order of entity.FlavorAxes. A recipe without a profile has them all NULL.
+const flavorColumnList = `r.flavor_sweet, r.flavor_sour, r.flavor_bitter, r.flavor_boozy,
+	r.flavor_smoky, r.flavor_fruity`
+
+// RecipeRepository implements the domain.RecipeRepository interface
+type RecipeRepository struct {
+	db *sql.DB
+}
+
+// NewRecipeRepository creates a new RecipeRepository
+func NewRecipeRepository(db *sql.DB) *RecipeRepository {
+	return &RecipeRepository{
+		db: db,
+	}
+}
+
+// Create implements RecipeRepository.Create
+func (r *RecipeRepository) Create(ctx context.Context, recipe *entity.Recipe) error {
+	if recipe.ID.IsZero() {
+		recipe.ID = primitive.NewObjectID()
+	}
+
+	equipment, err := json.Marshal(nonNilStrings(recipe.Equipment))
+	if err != nil {
+		return err
+	}
+	ovenDegrees, ovenScale := ovenColumns(recipe.OvenTemperature)
+
+	args := []interface{}{
+		recipe.ID.Hex(), recipe.Name, string(recipe.Type), recipe.Description,
+		recipe.Glass, recipe.Garnish, string(recipe.Technique), string(recipe.BaseSpirit),
+		string(recipe.Season), recipe.ABV, recipe.Servings, recipe.PrepMinutes,
+		recipe.CookMinutes, recipe.Yield, string(equipment), ovenDegrees, ovenScale,
+		nullableID(recipe.CreatorID), nullableID(recipe.ForkedFrom), recipe.Featured,
+		recipe.Hidden, recipe.Version, toUnix(recipe.CreatedAt), toUnix(recipe.UpdatedAt),
+	}
+	args = append(args, flavorValues(recipe.Flavor)...)
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO recipes
+			(id, name, type, description, glass, garnish, technique, base_spirit, season,
+			abv, servings, prep_minutes, cook_minutes, yield, equipment, oven_degrees,
+			oven_scale, creator_id, forked_from, featured, hidden, version, created_at,
+			updated_at, flavor_sweet, flavor_sour, flavor_bitter, flavor_boozy,
+			flavor_smoky, flavor_fruity)
+			VALUES (`+placeholders(len(args))+`)`, args...)
+		if err != nil {
+			return err
+		}
+		return writeRecipeChildren(ctx, tx, recipe)
+	})
+}
+
+// FindByID implements RecipeRepository.FindByID
+func (r *RecipeRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Recipe, error) {
+	recipes, err := r.query(ctx, `SELECT `+recipeColumns+` FROM recipes r WHERE r.id = ?`, id.Hex())
+	if err != nil {
+		return nil, err
+	}
+	if len(recipes) == 0 {
+		return nil, nil
+	}
+	return recipes[0], nil
+}
+
+// FindByIDs implements RecipeRepository.FindByIDs
+func (r *RecipeRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*entity.Recipe, error) {
+	if len(ids) == 0 {
+		return []*entity.Recipe{}, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id.Hex()
+	}
+	return r.query(ctx, `SELECT `+recipeColumns+` FROM recipes r WHERE r.id IN (`+placeholders(len(ids))+`)`, args...)
+}
+
+// FindByClassification implements RecipeRepository.FindByClassification
+func (r *RecipeRepository) FindByClassification(ctx context.Context, filter repository.ClassificationFilter, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(ctx, classificationQuery(filter), opts)
+}
+
+// Facets implements RecipeRepository.Facets with one grouped count per
+// dimension
+func (r *RecipeRepository) Facets(ctx context.Context, filter repository.ClassificationFilter, opts repository.ListOptions) (*repository.RecipeFacets, error) {
+	facets := &repository.RecipeFacets{}
+
+	q := classificationQuery(filter)
+	q.moderate(opts)
+	tags, err := r.countBy(ctx, `SELECT t.tag, COUNT(*) AS n
+		FROM recipe_tags t JOIN recipes r ON r.id = t.recipe_id`+whereClause(q.where)+`
+		GROUP BY t.tag
+		ORDER BY n DESC, t.tag
+		LIMIT ?`, append(q.args, repository.MaxTagFacets)...)
+	if err != nil {
+		return nil, err
+	}
+	facets.Tags = repository.NewFacetCounts(tags, repository.MaxTagFacets)
+
+	for _, dim := range []struct {
+		column string
+		filter repository.ClassificationFilter
+		facets *[]repository.FacetCount
+	}{
+		{`r.base_spirit`, filter.WithoutBaseSpirit(), &facets.BaseSpirits},
+		{`r.technique`, filter.WithoutTechnique(), &facets.Techniques},
+		{`r.season`, filter.WithoutSeason(), &facets.Seasons},
+	} {
+		q := classificationQuery(dim.filter)
+		q.moderate(opts)
+		q.where = append(q.where, dim.column+` != ''`)
+		counts, err := r.countBy(ctx, `SELECT `+dim.column+`, COUNT(*)
+			FROM recipes r`+whereClause(q.where)+`
+			GROUP BY `+dim.column, q.args...)
+		if err != nil {
+			return nil, err
+		}
+		*dim.facets = repository.NewFacetCounts(counts, 0)
+	}
+
+	sums := make([]string, len(entity.FlavorAxes))
+	for i, axis := range entity.FlavorAxes {
+		sums[i] = fmt.Sprintf(`COALESCE(SUM(r.flavor_%s >= %d), 0)`, axis, entity.FlavorPronounced)
+	}
+	levels := make([]int64, len(entity.FlavorAxes))
+	dest := make([]interface{}, len(levels))
+	for i := range levels {
+		dest[i] = &levels[i]
+	}
+	err = r.db.QueryRowContext(ctx, `SELECT `+strings.Join(sums, ", ")+`
+		FROM recipes r`+whereClause(q.where), q.args...).Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+	flavors := make(map[string]int64, len(levels))
+	for i, axis := range entity.FlavorAxes {
+		flavors[string(axis)] = levels[i]
+	}
+	facets.Flavors = repository.NewFacetCounts(flavors, 0)
+
+	return facets, nil
+}
+
+// autocompleteColumns maps each autocomplete field to the column holding
+// it. Each column has a NOCASE index, so a prefix LIKE is a range scan.
+var autocompleteColumns = map[repository.AutocompleteField]struct{ from, column string }{
+	repository.AutocompleteIngredient: {`recipe_ingredients i JOIN recipes r ON r.id = i.recipe_id`, `i.name`},
+	repository.AutocompleteUnit:       {`recipe_ingredients i JOIN recipes r ON r.id = i.recipe_id`, `i.unit`},
+	repository.AutocompleteGlass:      {`recipes r`, `r.glass`},
+	repository.AutocompleteGarnish:    {`recipes r`, `r.garnish`},
+	repository.AutocompleteName:       {`recipes r`, `r.name`},
+}
+
+// CountValues implements RecipeRepository.CountValues
+func (r *RecipeRepository) CountValues(ctx context.Context, field repository.AutocompleteField, prefix string) (map[string]int64, error) {
+	source, ok := autocompleteColumns[field]
+	if !ok {
+		return map[string]int64{}, nil
+	}
+	q := recipeQuery{
+		where: []string{source.column + ` LIKE ? ESCAPE '\'`, source.column + ` != ''`},
+		args:  []interface{}{escapeLike(prefix) + "%"},
+	}
+	q.moderate(repository.ListOptions{})
+	return r.countBy(ctx, `SELECT `+source.column+`, COUNT(*)
+		FROM `+source.from+whereClause(q.where)+`
+		GROUP BY `+source.column, q.args...)
+}
+
+// countBy runs a query selecting a value and a count and collects the rows
+func (r *RecipeRepository) countBy(ctx context.Context, query string, args ...interface{}) (map[string]int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var (
+			value string
+			count int64
+		)
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		counts[value] = count
+	}
+	return counts, rows.Err()
+}
+
+// classificationQuery selects the recipes that pass filter
+func classificationQuery(filter repository.ClassificationFilter) recipeQuery {
+	q := recipeQuery{from: `recipes r`}
+	for _, c := range []struct {
+		column, value string
+	}{
+		{`r.type`, string(filter.Type)},
+		{`r.base_spirit`, string(filter.BaseSpirit)},
+		{`r.technique`, string(filter.Technique)},
+		{`r.season`, string(filter.Season)},
+	} {
+		if c.value != "" {
+			q.where = append(q.where, c.column+` = ?`)
+			q.args = append(q.args, c.value)
+		}
+	}
+	for _, tag := range filter.Tags {
+		q.where = append(q.where, `r.id IN (SELECT recipe_id FROM recipe_tags WHERE tag = ?)`)
+		q.args = append(q.args, tag)
+	}
+	for _, axis := range filter.Flavors {
+		if axis.Valid() {
+			q.where = append(q.where, fmt.Sprintf(`r.flavor_%s >= %d`, axis, entity.FlavorPronounced))
+		}
+	}
+	return q
+}
+
+// FindByIngredient implements RecipeRepository.FindByIngredient
+func (r *RecipeRepository) FindByIngredient(ctx context.Context, match repository.IngredientMatch, opts repository.ListOptions) (*repository.RecipePage, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	for _, name := range match.Names {
+		conditions = append(conditions, `i.name LIKE ? ESCAPE '\'`)
+		args = append(args, likePattern(name))
+	}
+	if len(match.CatalogIDs) > 0 {
+		conditions = append(conditions, `i.catalog_id IN (`+placeholders(len(match.CatalogIDs))+`)`)
+		for _, id := range match.CatalogIDs {
+			args = append(args, id.Hex())
+		}
+	}
+	if len(conditions) == 0 {
+		return &repository.RecipePage{}, nil
+	}
+
+	return r.findPage(ctx, recipeQuery{
+		from: `recipes r`,
+		where: []string{`EXISTS (
+			SELECT 1 FROM recipe_ingredients i
+			WHERE i.recipe_id = r.id AND (` + strings.Join(conditions, " OR ") + `)
+		)`},
+		args: args,
+	}, opts)
+}
+
+// FindForks implements RecipeRepository.FindForks
+func (r *RecipeRepository) FindForks(ctx context.Context, parentID primitive.ObjectID, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(ctx, recipeQuery{
+		from:  `recipes r`,
+		where: []string{`r.forked_from = ?`},
+		args:  []interface{}{parentID.Hex()},
+	}, opts)
+}
+
+// FindByCreator implements RecipeRepository.FindByCreator
+func (r *RecipeRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID, opts repository.ListOptions) (*repository.RecipePage, error) {
+	return r.findPage(ctx, recipeQuery{
+		from:  `recipes r`,
+		where: []string{`r.creator_id = ?`},
+		args:  []interface{}{creatorID.Hex()},
+	}, opts)
+}
+
+// Update implements RecipeRepository.Update
+func (r *RecipeRepository) Update(ctx context.Context, recipe *entity.Recipe, expectedVersion int64) error {
+	equipment, err := json.Marshal(nonNilStrings(recipe.Equipment))
+	if err != nil {
+		return err
+	}
+	ovenDegrees, ovenScale := ovenColumns(recipe.OvenTemperature)
+
+	args := []interface{}{
+		recipe.Name, string(recipe.Type), recipe.Description, recipe.Glass, recipe.Garnish,
+		string(recipe.Technique), string(recipe.BaseSpirit), string(recipe.Season), recipe.ABV,
+		recipe.Servings, recipe.PrepMinutes, recipe.CookMinutes, recipe.Yield, string(equipment),
+		ovenDegrees, ovenScale, nullableID(recipe.CreatorID), nullableID(recipe.ForkedFrom),
+		recipe.Featured, recipe.Hidden, recipe.Version, toUnix(recipe.CreatedAt),
+		toUnix(recipe.UpdatedAt),
+	}
+	args = append(args, flavorValues(recipe.Flavor)...)
+	args = append(args, recipe.ID.Hex(), expectedVersion)
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `UPDATE recipes SET
+			name = ?, type = ?, description = ?, glass = ?, garnish = ?, technique = ?,
+			base_spirit = ?, season = ?, abv = ?, servings = ?, prep_minutes = ?,
+			cook_minutes = ?, yield = ?, equipment = ?, oven_degrees = ?, oven_scale = ?,
+			creator_id = ?, forked_from = ?, featured = ?, hidden = ?, version = ?,
+			created_at = ?, updated_at = ?, flavor_sweet = ?, flavor_sour = ?,
+			flavor_bitter = ?, flavor_boozy = ?, flavor_smoky = ?, flavor_fruity = ?
+			WHERE id = ? AND version = ?`, args...)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return repository.ErrVersionConflict
+		}
+
+		if err := deleteRecipeChildren(ctx, tx, recipe.ID); err != nil {
+			return err
+		}
+		return writeRecipeChildren(ctx, tx, recipe)
+	})
+}
+
+// UpdateRating implements RecipeRepository.UpdateRating
+func (r *RecipeRepository) UpdateRating(ctx context.Context, id primitive.ObjectID, rating float64, count int) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE recipes SET rating = ?, rating_count = ?, version = version + 1 WHERE id = ?`,
+		rating, count, id.Hex())
+	return err
+}
+
+// Delete implements RecipeRepository.Delete
+func (r *RecipeRepository) Delete(ctx context.Context, id primitive.ObjectID, expectedVersion int64) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := deleteRecipeChildren(ctx, tx, id); err != nil {
+			return err
+		}
+		// Collection entries go with the recipe through the foreign key
+		if err := bumpCollectionsHolding(ctx, tx, id); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, `DELETE FROM recipes WHERE id = ? AND version = ?`,
+			id.Hex(), expectedVersion)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return repository.ErrVersionConflict
+		}
+		return nil
+	})
+}
+
+// Search implements RecipeRepository.Search. Candidates are the recipes
+// sharing the most trigrams with the query; they are scored and paged in Go.
+func (r *RecipeRepository) Search(ctx context.Context, query search.Query, filter repository.SearchFilter, opts repository.ListOptions) (*repository.RecipePage, error) {
+	q := recipeQuery{from: `recipes r`}
+	if filter.Type != nil {
+		q.where = append(q.where, `r.type = ?`)
+		q.args = append(q.args, string(*filter.Type))
+	}
+	if filter.MinABV != nil {
+		q.where = append(q.where, `r.abv >= ?`)
+		q.args = append(q.args, *filter.MinABV)
+	}
+	if filter.MaxABV != nil {
+		q.where = append(q.where, `r.abv <= ?`)
+		q.args = append(q.args, *filter.MaxABV)
+	}
+	if query.Empty() {
+		return r.findPage(ctx, q, opts)
+	}
+
+	trigrams := query.Trigrams()
+	args := make([]interface{}, 0, len(trigrams)+len(q.args)+1)
+	for _, trigram := range trigrams {
+		args = append(args, trigram)
+	}
+	q.from = `recipes r JOIN (
+			SELECT recipe_id, COUNT(*) AS shared FROM recipe_trigrams
+			WHERE trigram IN (` + placeholders(len(trigrams)) + `)
+			GROUP BY recipe_id
+		) t ON t.recipe_id = r.id`
+	q.args = append(args, q.args...)
+	q.moderate(opts)
+
+	candidates, err := r.query(ctx, `SELECT `+recipeColumns+` FROM `+q.from+
+		whereClause(q.where)+`
+		ORDER BY t.shared DESC, r.id
+		LIMIT ?`, append(q.args, repository.MaxSearchCandidates)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []repository.ScoredRecipe
+	for _, recipe := range candidates {
+		if score := search.Score(recipe, query); score > 0 {
+			matches = append(matches, repository.ScoredRecipe{Recipe: recipe, Score: score})
+		}
+	}
+	return repository.PageOf(matches, opts)
+}
+
+// FindMakeable implements RecipeRepository.FindMakeable. Missing ingredients
+// are counted in SQL so only qualifying recipes are loaded. Names are
+// compared by the normalized_name stored with each ingredient, since
+// SQLite's lower() only folds ASCII.
+func (r *RecipeRepository) FindMakeable(ctx context.Context, available repository.AvailableIngredients, maxMissing, limit int) ([]*repository.MakeableRecipe, error) {
+	args := make([]interface{}, 0, len(available.Names)+len(available.CatalogIDs)+2)
+	for _, name := range available.Names {
+		args = append(args, name)
+	}
+	for _, id := range available.CatalogIDs {
+		args = append(args, id.Hex())
+	}
+	args = append(args, maxMissing, limit)
+
+	recipes, err := r.query(ctx, `SELECT `+recipeColumns+` FROM (
+			SELECT r.*, (
+				SELECT COUNT(*) FROM recipe_ingredients i
+				WHERE i.recipe_id = r.id AND i.is_optional = 0
+				AND i.normalized_name NOT IN (`+placeholders(len(available.Names))+`)
+				AND (i.catalog_id IS NULL OR i.catalog_id NOT IN (`+placeholders(len(available.CatalogIDs))+`))
+			) AS missing_count
+			FROM recipes r
+			WHERE r.hidden = 0
+		) r
+		WHERE r.missing_count <= ?
+		ORDER BY r.missing_count, r.name, r.id
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	have := make(map[string]bool, len(available.Names))
+	for _, name := range available.Names {
+		have[name] = true
+	}
+	haveIDs := make(map[primitive.ObjectID]bool, len(available.CatalogIDs))
+	for _, id := range available.CatalogIDs {
+		haveIDs[id] = true
+	}
+
+	matches := make([]*repository.MakeableRecipe, 0, len(recipes))
+	for _, recipe := range recipes {
+		matches = append(matches, &repository.MakeableRecipe{
+			Recipe:  recipe,
+			Missing: recipe.MissingIngredients(have, haveIDs),
+		})
+	}
+	return matches, nil
+}
+
+// recipeQuery is the FROM and WHERE part of a recipe listing
+type recipeQuery struct {
+	from  string
+	where []string
+	args  []interface{}
+}
+
+// moderate restricts q to the recipes opts lets the caller see
+func (q *recipeQuery) moderate(opts repository.ListOptions) {
+	if !opts.IncludeHidden {
+		q.where = append(q.where, `r.hidden = 0`)
+	}
+	if opts.FeaturedOnly {
+		q.where = append(q.where, `r.featured = 1`)
+	}
+}
+
+// findPage counts the recipes matching q and returns one page of them, using
+// keyset pagination on (column, id). Search ranks and pages relevance sorts
+// itself.
+func (r *RecipeRepository) findPage(ctx context.Context, q recipeQuery, opts repository.ListOptions) (*repository.RecipePage, error) {
+	after, err := opts.After()
+	if err != nil {
+		return nil, err
+	}
+
+	q.moderate(opts)
+
+	page := &repository.RecipePage{}
+	err = r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+q.from+
+		whereClause(q.where), q.args...).Scan(&page.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	where := append([]string(nil), q.where...)
+	args := append([]interface{}(nil), q.args...)
+	dir, cmp := "ASC", ">"
+	if opts.Descending {
+		dir, cmp = "DESC", "<"
+	}
+
+	column := `r.` + string(opts.Sort)
+	if after != nil {
+		var key interface{} = toUnix(after.Time)
+		switch opts.Sort {
+		case repository.SortByName:
+			key = after.Name
+		case repository.SortByRating:
+			key = after.Rating
+		}
+		where = append(where, `(`+column+` `+cmp+` ? OR (`+column+` = ? AND r.id `+cmp+` ?))`)
+		args = append(args, key, key, after.ID.Hex())
+	}
+	args = append(args, opts.Limit+1)
+
+	recipes, err := r.query(ctx, `SELECT `+recipeColumns+` FROM `+q.from+
+		whereClause(where)+`
+		ORDER BY `+column+` `+dir+`, r.id `+dir+`
+		LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page.Recipes = recipes
+	if len(recipes) > opts.Limit {
+		page.Recipes = recipes[:opts.Limit]
+		page.NextCursor = opts.NextCursor(page.Recipes[opts.Limit-1], opts.Limit-1)
+	}
+	return page, nil
+}
+
+// whereClause joins conditions into a WHERE clause, or nothing when there
+// are none
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return `
+		WHERE ` + strings.Join(conditions, " AND ")
+}
+
+// query runs a SELECT over recipeColumns and hydrates the ingredients and
+// instructions of every returned recipe
+func (r *RecipeRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Recipe, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipes []*entity.Recipe
+	byID := make(map[string]*entity.Recipe)
+	for rows.Next() {
+		recipe, err := scanRecipe(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+		byID[recipe.ID.Hex()] = recipe
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	if len(recipes) == 0 {
+		return recipes, nil
+	}
+	if err := r.loadChildren(ctx, byID); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
+
+// loadChildren fills in ingredients, instructions and tags for the given
+// recipes
+func (r *RecipeRepository) loadChildren(ctx context.Context, byID map[string]*entity.Recipe) error {
+	ids := make([]interface{}, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	in := placeholders(len(ids))
+
+	rows, err := r.db.QueryContext(ctx, `SELECT recipe_id, name, amount, unit, notes, is_optional, catalog_id
+		FROM recipe_ingredients WHERE recipe_id IN (`+in+`) ORDER BY recipe_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var (
+			recipeID  string
+			ing       entity.Ingredient
+			catalogID sql.NullString
+		)
+		err := rows.Scan(&recipeID, &ing.Name, &ing.Amount, &ing.Unit, &ing.Notes, &ing.IsOptional, &catalogID)
+		if err == nil {
+			ing.CatalogID, err = parseNullableID(catalogID)
+		}
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		recipe := byID[recipeID]
+		recipe.Ingredients = append(recipe.Ingredients, ing)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	rows, err = r.db.QueryContext(ctx, `SELECT recipe_id, text
+		FROM recipe_instructions WHERE recipe_id IN (`+in+`) ORDER BY recipe_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var recipeID, text string
+		if err := rows.Scan(&recipeID, &text); err != nil {
+			rows.Close()
+			return err
+		}
+		recipe := byID[recipeID]
+		recipe.Instructions = append(recipe.Instructions, text)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	rows, err = r.db.QueryContext(ctx, `SELECT recipe_id, tag
+		FROM recipe_tags WHERE recipe_id IN (`+in+`) ORDER BY recipe_id, position`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var recipeID, tag string
+		if err := rows.Scan(&recipeID, &tag); err != nil {
+			return err
+		}
+		recipe := byID[recipeID]
+		recipe.Tags = append(recipe.Tags, tag)
+	}
+	return rows.Err()
+}
+
+// withTx runs fn in a transaction, committing only if it succeeds
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// writeRecipeChildren inserts the ingredients, instructions, tags and
+// search trigrams of a recipe
+func writeRecipeChildren(ctx context.Context, tx *sql.Tx, recipe *entity.Recipe) error {
+	id := recipe.ID.Hex()
+
+	for i, ing := range recipe.Ingredients {
+		_, err := tx.ExecContext(ctx, `INSERT INTO recipe_ingredients
+			(recipe_id, position, name, normalized_name, amount, unit, notes, is_optional, catalog_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, i, ing.Name, entity.NormalizeIngredientName(ing.Name), ing.Amount, ing.Unit, ing.Notes,
+			ing.IsOptional, nullableID(ing.CatalogID))
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, text := range recipe.Instructions {
+		_, err := tx.ExecContext(ctx, `INSERT INTO recipe_instructions
+			(recipe_id, position, text) VALUES (?, ?, ?)`, id, i, text)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, tag := range recipe.Tags {
+		_, err := tx.ExecContext(ctx, `INSERT INTO recipe_tags
+			(recipe_id, position, tag) VALUES (?, ?, ?)`, id, i, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writeSearchTrigrams(ctx, tx, id, recipe)
+}
+
+// writeSearchTrigrams indexes a recipe by the trigrams of its searchable
+// words
+func writeSearchTrigrams(ctx context.Context, tx *sql.Tx, id string, recipe *entity.Recipe) error {
+	for _, trigram := range search.IndexTrigrams(recipe) {
+		_, err := tx.ExecContext(ctx, `INSERT INTO recipe_trigrams (trigram, recipe_id) VALUES (?, ?)`,
+			trigram, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteRecipeChildren(ctx context.Context, tx *sql.Tx, id primitive.ObjectID) error {
+	for _, stmt := range []string{
+		`DELETE FROM recipe_ingredients WHERE recipe_id = ?`,
+		`DELETE FROM recipe_instructions WHERE recipe_id = ?`,
+		`DELETE FROM recipe_trigrams WHERE recipe_id = ?`,
+		`DELETE FROM recipe_tags WHERE recipe_id = ?`,
+	} {
+		if _, err := tx.ExecContext(ctx, stmt, id.Hex()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanRecipe(rows *sql.Rows) (*entity.Recipe, error) {
+	var (
+		recipe               entity.Recipe
+		id, recipeType       string
+		technique            string
+		baseSpirit, season   string
+		abv                  sql.NullFloat64
+		equipment            string
+		ovenDegrees          sql.NullFloat64
+		ovenScale            sql.NullString
+		creatorID            sql.NullString
+		forkedFrom           sql.NullString
+		createdAt, updatedAt int64
+		flavor               [6]sql.NullInt64
+	)
+	err := rows.Scan(&id, &recipe.Name, &recipeType, &recipe.Description, &recipe.Glass,
+		&recipe.Garnish, &technique, &baseSpirit, &season, &abv, &recipe.Servings,
+		&recipe.PrepMinutes, &recipe.CookMinutes, &recipe.Yield, &equipment, &ovenDegrees,
+		&ovenScale, &creatorID, &forkedFrom, &recipe.Featured, &recipe.Hidden, &recipe.Rating,
+		&recipe.RatingCount, &recipe.Version, &createdAt, &updatedAt, &flavor[0], &flavor[1],
+		&flavor[2], &flavor[3], &flavor[4], &flavor[5])
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(equipment), &recipe.Equipment); err != nil {
+		return nil, err
+	}
+	if len(recipe.Equipment) == 0 {
+		recipe.Equipment = nil
+	}
+	if ovenDegrees.Valid {
+		recipe.OvenTemperature = &units.Temperature{
+			Degrees: ovenDegrees.Float64,
+			Scale:   units.TemperatureScale(ovenScale.String),
+		}
+	}
+
+	recipe.ID, err = primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.CreatorID, err = parseNullableID(creatorID); err != nil {
+		return nil, err
+	}
+	if recipe.ForkedFrom, err = parseNullableID(forkedFrom); err != nil {
+		return nil, err
+	}
+	recipe.Type = entity.RecipeType(recipeType)
+	recipe.Technique = entity.Technique(technique)
+	recipe.BaseSpirit = entity.BaseSpirit(baseSpirit)
+	recipe.Season = entity.Season(season)
+	if flavor[0].Valid {
+		recipe.Flavor = &entity.FlavorProfile{
+			Sweet:  int(flavor[0].Int64),
+			Sour:   int(flavor[1].Int64),
+			Bitter: int(flavor[2].Int64),
+			Boozy:  int(flavor[3].Int64),
+			Smoky:  int(flavor[4].Int64),
+			Fruity: int(flavor[5].Int64),
+		}
+	}
+	if abv.Valid {
+		recipe.ABV = &abv.Float64
+	}
+	recipe.CreatedAt = fromUnix(createdAt)
+	recipe.UpdatedAt = fromUnix(updatedAt)
+	return &recipe, nil
+}
+
+// likePattern builds a case-insensitive substring LIKE pattern, escaping
+// the LIKE wildcards in s
+func likePattern(s string) string {
+	return "%" + escapeLike(s) + "%"
+}
+
+// escapeLike escapes the LIKE wildcards in s with a backslash
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ovenColumns splits an optional oven temperature into its nullable degrees
+// and scale columns
+func ovenColumns(oven *units.Temperature) (interface{}, interface{}) {
+	if oven == nil {
+		return nil, nil
+	}
+	return oven.Degrees, string(oven.Scale)
+}
+
+// flavorValues returns the flavor columns of a profile in the order of
+// flavorColumnList
+func flavorValues(flavor *entity.FlavorProfile) []interface{} {
+	values := make([]interface{}, len(entity.FlavorAxes))
+	if flavor != nil {
+		for i, axis := range entity.FlavorAxes {
+			values[i] = flavor.Level(axis)
+		}
+	}
+	return values
+}
+
+// nonNilStrings turns a nil slice into an empty one so it encodes as []
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// nullableID stores an optional ObjectID as its hex string or NULL
+func nullableID(id *primitive.ObjectID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.Hex()
+}
+
+func parseNullableID(s sql.NullString) (*primitive.ObjectID, error) {
+	if !s.Valid {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(s.String)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func toUnix(t time.Time) int64 {
+	return t.UnixNano()
+}
+
+func fromUnix(n int64) time.Time {
+	return time.Unix(0, n)
+}
+
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return toUnix(*t)
+}
+
+func parseNullableTime(n sql.NullInt64) *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := fromUnix(n.Int64)
+	return &t
+}
@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const reviewColumns = `id, recipe_id, user_id, rating, text, created_at, updated_at`
+
+// ReviewRepository implements the domain.ReviewRepository interface
+type ReviewRepository struct {
+	db *sql.DB
+}
+
+// NewReviewRepository creates a new ReviewRepository
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{
+		db: db,
+	}
+}
+
+// Create implements ReviewRepository.Create
+func (r *ReviewRepository) Create(ctx context.Context, review *entity.Review) error {
+	if review.ID.IsZero() {
+		review.ID = primitive.NewObjectID()
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		var n int
+		err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM recipe_reviews WHERE recipe_id = ? AND user_id = ?`,
+			review.RecipeID.Hex(), review.UserID.Hex()).Scan(&n)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return repository.ErrDuplicateReview
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO recipe_reviews (`+reviewColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			review.ID.Hex(), review.RecipeID.Hex(), review.UserID.Hex(), review.Rating, review.Text,
+			toUnix(review.CreatedAt), toUnix(review.UpdatedAt))
+		return err
+	})
+}
+
+// FindByID implements ReviewRepository.FindByID
+func (r *ReviewRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.Review, error) {
+	return r.queryOne(ctx, `SELECT `+reviewColumns+` FROM recipe_reviews WHERE id = ?`, id.Hex())
+}
+
+// FindByRecipeAndUser implements ReviewRepository.FindByRecipeAndUser
+func (r *ReviewRepository) FindByRecipeAndUser(ctx context.Context, recipeID, userID primitive.ObjectID) (*entity.Review, error) {
+	return r.queryOne(ctx, `SELECT `+reviewColumns+` FROM recipe_reviews
+		WHERE recipe_id = ? AND user_id = ?`, recipeID.Hex(), userID.Hex())
+}
+
+// FindByRecipe implements ReviewRepository.FindByRecipe
+func (r *ReviewRepository) FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Review, error) {
+	return r.query(ctx, `SELECT `+reviewColumns+` FROM recipe_reviews
+		WHERE recipe_id = ? ORDER BY created_at DESC, id DESC`, recipeID.Hex())
+}
+
+// Update implements ReviewRepository.Update
+func (r *ReviewRepository) Update(ctx context.Context, review *entity.Review) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE recipe_reviews SET rating = ?, text = ?, updated_at = ?
+		WHERE id = ?`,
+		review.Rating, review.Text, toUnix(review.UpdatedAt), review.ID.Hex())
+	return err
+}
+
+// Delete implements ReviewRepository.Delete
+func (r *ReviewRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM recipe_reviews WHERE id = ?`, id.Hex())
+	return err
+}
+
+// DeleteByRecipe implements ReviewRepository.DeleteByRecipe
+func (r *ReviewRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM recipe_reviews WHERE recipe_id = ?`, recipeID.Hex())
+	return err
+}
+
+// Summarize implements ReviewRepository.Summarize
+func (r *ReviewRepository) Summarize(ctx context.Context, recipeID primitive.ObjectID) (float64, int, error) {
+	var (
+		average sql.NullFloat64
+		count   int
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT AVG(rating), COUNT(*) FROM recipe_reviews WHERE recipe_id = ?`,
+		recipeID.Hex()).Scan(&average, &count)
+	if err != nil {
+		return 0, 0, err
+	}
+	return average.Float64, count, nil
+}
+
+func (r *ReviewRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*entity.Review, error) {
+	reviews, err := r.query(ctx, query, args...)
+	if err != nil || len(reviews) == 0 {
+		return nil, err
+	}
+	return reviews[0], nil
+}
+
+func (r *ReviewRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Review, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*entity.Review{}
+	for rows.Next() {
+		var (
+			review               entity.Review
+			id, recipeID, userID string
+			createdAt, updatedAt int64
+		)
+		err := rows.Scan(&id, &recipeID, &userID, &review.Rating, &review.Text, &createdAt, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if review.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, err
+		}
+		if review.RecipeID, err = primitive.ObjectIDFromHex(recipeID); err != nil {
+			return nil, err
+		}
+		if review.UserID, err = primitive.ObjectIDFromHex(userID); err != nil {
+			return nil, err
+		}
+		review.CreatedAt = fromUnix(createdAt)
+		review.UpdatedAt = fromUnix(updatedAt)
+		reviews = append(reviews, &review)
+	}
+	return reviews, rows.Err()
+}
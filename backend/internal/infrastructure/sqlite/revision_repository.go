@@ -0,0 +1,128 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const revisionColumns = `id, recipe_id, number, snapshot, edited_by, restored_from, created_at`
+
+// RevisionRepository implements the domain.RevisionRepository interface.
+// Snapshots are stored as JSON documents since they are never queried into.
+type RevisionRepository struct {
+	db *sql.DB
+}
+
+// NewRevisionRepository creates a new RevisionRepository
+func NewRevisionRepository(db *sql.DB) *RevisionRepository {
+	return &RevisionRepository{
+		db: db,
+	}
+}
+
+// Create implements RevisionRepository.Create
+func (r *RevisionRepository) Create(ctx context.Context, revision *entity.Revision) error {
+	snapshot, err := json.Marshal(revision.Recipe)
+	if err != nil {
+		return err
+	}
+
+	if revision.ID.IsZero() {
+		revision.ID = primitive.NewObjectID()
+	}
+
+	// OR IGNORE skips the insert when the (recipe_id, number) key is taken
+	result, err := r.db.ExecContext(ctx, `INSERT OR IGNORE INTO recipe_revisions (`+revisionColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		revision.ID.Hex(), revision.RecipeID.Hex(), revision.Number, string(snapshot),
+		revision.EditedBy, revision.RestoredFrom, toUnix(revision.CreatedAt))
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrDuplicateRevision
+	}
+	return nil
+}
+
+// FindByRecipe implements RevisionRepository.FindByRecipe
+func (r *RevisionRepository) FindByRecipe(ctx context.Context, recipeID primitive.ObjectID) ([]*entity.Revision, error) {
+	return r.query(ctx, `SELECT `+revisionColumns+` FROM recipe_revisions
+		WHERE recipe_id = ? ORDER BY number DESC`, recipeID.Hex())
+}
+
+// FindByNumber implements RevisionRepository.FindByNumber
+func (r *RevisionRepository) FindByNumber(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error) {
+	return r.queryOne(ctx, `SELECT `+revisionColumns+` FROM recipe_revisions
+		WHERE recipe_id = ? AND number = ?`, recipeID.Hex(), number)
+}
+
+// FindBefore implements RevisionRepository.FindBefore
+func (r *RevisionRepository) FindBefore(ctx context.Context, recipeID primitive.ObjectID, number int) (*entity.Revision, error) {
+	return r.queryOne(ctx, `SELECT `+revisionColumns+` FROM recipe_revisions
+		WHERE recipe_id = ? AND number < ? ORDER BY number DESC LIMIT 1`, recipeID.Hex(), number)
+}
+
+// FindLatest implements RevisionRepository.FindLatest
+func (r *RevisionRepository) FindLatest(ctx context.Context, recipeID primitive.ObjectID) (*entity.Revision, error) {
+	return r.queryOne(ctx, `SELECT `+revisionColumns+` FROM recipe_revisions
+		WHERE recipe_id = ? ORDER BY number DESC LIMIT 1`, recipeID.Hex())
+}
+
+// DeleteByRecipe implements RevisionRepository.DeleteByRecipe
+func (r *RevisionRepository) DeleteByRecipe(ctx context.Context, recipeID primitive.ObjectID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM recipe_revisions WHERE recipe_id = ?`, recipeID.Hex())
+	return err
+}
+
+func (r *RevisionRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*entity.Revision, error) {
+	revisions, err := r.query(ctx, query, args...)
+	if err != nil || len(revisions) == 0 {
+		return nil, err
+	}
+	return revisions[0], nil
+}
+
+func (r *RevisionRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Revision, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*entity.Revision
+	for rows.Next() {
+		var (
+			revision     entity.Revision
+			id, recipeID string
+			snapshot     string
+			createdAt    int64
+		)
+		err := rows.Scan(&id, &recipeID, &revision.Number, &snapshot,
+			&revision.EditedBy, &revision.RestoredFrom, &createdAt)
+		if err != nil {
+			return nil, err
+		}
+		if revision.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, err
+		}
+		if revision.RecipeID, err = primitive.ObjectIDFromHex(recipeID); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(snapshot), &revision.Recipe); err != nil {
+			return nil, err
+		}
+		revision.CreatedAt = fromUnix(createdAt)
+		revisions = append(revisions, &revision)
+	}
+	return revisions, rows.Err()
+}
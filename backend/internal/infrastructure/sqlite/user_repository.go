@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const userColumns = `id, email, display_name, password_hash, role, disabled, created_at, updated_at`
+
+// UserRepository implements the domain.UserRepository interface
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new UserRepository
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{
+		db: db,
+	}
+}
+
+// Create implements UserRepository.Create
+func (r *UserRepository) Create(ctx context.Context, user *entity.User) error {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkEmail(ctx, tx, user); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO users (`+userColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			user.ID.Hex(), user.Email, user.DisplayName, user.PasswordHash, string(user.Role),
+			user.Disabled, toUnix(user.CreatedAt), toUnix(user.UpdatedAt))
+		return err
+	})
+}
+
+// FindByID implements UserRepository.FindByID
+func (r *UserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*entity.User, error) {
+	return r.findOne(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id.Hex())
+}
+
+// FindByEmail implements UserRepository.FindByEmail
+func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.findOne(ctx, `SELECT `+userColumns+` FROM users WHERE email = ?`, entity.NormalizeEmail(email))
+}
+
+// Update implements UserRepository.Update
+func (r *UserRepository) Update(ctx context.Context, user *entity.User) error {
+	return withTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkEmail(ctx, tx, user); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE users SET
+			email = ?, display_name = ?, password_hash = ?, role = ?, disabled = ?, created_at = ?,
+			updated_at = ?
+			WHERE id = ?`,
+			user.Email, user.DisplayName, user.PasswordHash, string(user.Role), user.Disabled,
+			toUnix(user.CreatedAt), toUnix(user.UpdatedAt), user.ID.Hex())
+		return err
+	})
+}
+
+func (r *UserRepository) findOne(ctx context.Context, query string, args ...interface{}) (*entity.User, error) {
+	var (
+		user                 entity.User
+		id, role             string
+		createdAt, updatedAt int64
+	)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&id, &user.Email, &user.DisplayName,
+		&user.PasswordHash, &role, &user.Disabled, &createdAt, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.ID, err = primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	user.Role = entity.Role(role)
+	user.CreatedAt = fromUnix(createdAt)
+	user.UpdatedAt = fromUnix(updatedAt)
+	return &user, nil
+}
+
+// checkEmail fails with ErrDuplicateEmail when another account already uses
+// the user's email
+func checkEmail(ctx context.Context, tx *sql.Tx, user *entity.User) error {
+	var n int
+	err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE email = ? AND id != ?`,
+		user.Email, user.ID.Hex()).Scan(&n)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return repository.ErrDuplicateEmail
+	}
+	return nil
+}
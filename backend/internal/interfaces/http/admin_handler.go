@@ -0,0 +1,139 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type setRoleRequest struct {
+	Role entity.Role `json:"role"`
+}
+
+type setDisabledRequest struct {
+	Disabled *bool `json:"disabled"`
+}
+
+// FindUser handles an admin looking up an account by the email query
+// parameter
+func (h *UserHandler) FindUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	email := r.URL.Query().Get("email")
+	if email == "" {
+		writeProblem(w, "email query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	found, err := h.userService.FindUserByEmail(r.Context(), email, user)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeUser(w, found)
+}
+
+// GetUser handles an admin getting an account by ID
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	found, err := h.userService.GetUser(r.Context(), id, user)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeUser(w, found)
+}
+
+// SetRole handles an admin changing another user's role
+func (h *UserHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req setRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.userService.SetRole(r.Context(), id, req.Role, user)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeUser(w, updated)
+}
+
+// SetDisabled handles an admin disabling or re-enabling another user's
+// account
+func (h *UserHandler) SetDisabled(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req setDisabledRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Disabled == nil {
+		writeProblem(w, `Request body must be {"disabled": true|false}`, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.userService.SetDisabled(r.Context(), id, *req.Disabled, user)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeUser(w, updated)
+}
+
+// writeAdminError maps user administration errors to HTTP responses
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch err {
+	case application.ErrUserNotFound:
+		writeProblem(w, err.Error(), http.StatusNotFound)
+	case application.ErrInvalidRole, application.ErrChangeSelf:
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+	case application.ErrForbidden:
+		writeProblem(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("Error administering users: %v", err)
+		writeProblem(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func writeUser(w http.ResponseWriter, user *entity.User) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
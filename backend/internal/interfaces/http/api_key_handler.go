@@ -0,0 +1,120 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type createAPIKeyRequest struct {
+	Name  string             `json:"name"`
+	Scope entity.APIKeyScope `json:"scope"`
+	// ExpiresAt is optional; keys without it never expire
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// createAPIKeyResponse is a new key together with its secret, which is
+// never shown again
+type createAPIKeyResponse struct {
+	*entity.APIKey
+	Key string `json:"key"`
+}
+
+// CreateAPIKey handles issuing an API key to the signed-in user
+func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key, secret, err := h.authService.CreateAPIKey(r.Context(), user, req.Name, req.Scope, req.ExpiresAt)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(createAPIKeyResponse{APIKey: key, Key: secret})
+}
+
+// ListAPIKeys handles listing the signed-in user's API keys
+func (h *AuthHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.authService.ListAPIKeys(r.Context(), user)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keys)
+}
+
+// RevokeAPIKey handles revoking one of the signed-in user's API keys
+func (h *AuthHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(r.Context(), user, id); err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireSessionUser is like requireUser but refuses requests authenticated
+// with an API key, so a leaked key cannot be used to mint or revoke others
+func requireSessionUser(w http.ResponseWriter, r *http.Request) (*entity.User, bool) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return nil, false
+	}
+	if currentAPIKey(r) != nil {
+		writeProblem(w, "API keys cannot be managed with an API key", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+// writeAPIKeyError maps API key errors to HTTP responses
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	if writeIfValidationError(w, err) {
+		return
+	}
+	switch err {
+	case application.ErrAPIKeyNotFound:
+		writeProblem(w, err.Error(), http.StatusNotFound)
+	case application.ErrTooManyAPIKeys:
+		writeProblem(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("Error managing API keys: %v", err)
+		writeProblem(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
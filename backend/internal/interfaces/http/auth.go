@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+)
+
+type contextKey int
+
+const (
+	userContextKey contextKey = iota
+	apiKeyContextKey
+)
+
+// AuthMiddleware authenticates requests that carry an
+// "Authorization: Bearer <access token or API key>" header and makes the
+// user available to handlers. Requests without the header pass through
+// anonymously; a header with an invalid or expired token is rejected with
+// 401, and one for a disabled account with 403. Read-only API keys are
+// refused for anything but safe methods.
+func AuthMiddleware(authService *application.AuthService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+				writeUnauthorized(w, "Authorization header must be Bearer <token>")
+				return
+			}
+
+			token = strings.TrimSpace(token)
+			var (
+				user   *entity.User
+				apiKey *entity.APIKey
+				err    error
+			)
+			if strings.HasPrefix(token, entity.APIKeyPrefix) {
+				user, apiKey, err = authService.AuthenticateAPIKey(r.Context(), token)
+			} else {
+				user, err = authService.Authenticate(r.Context(), token)
+			}
+			if err != nil {
+				switch err {
+				case application.ErrInvalidToken:
+					writeUnauthorized(w, err.Error())
+				case application.ErrAccountDisabled:
+					writeProblem(w, err.Error(), http.StatusForbidden)
+				default:
+					writeProblem(w, "Internal server error", http.StatusInternalServerError)
+				}
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userContextKey, user)
+			if apiKey != nil {
+				if !apiKey.Allows(r.Method) {
+					writeProblem(w, "API key is read-only", http.StatusForbidden)
+					return
+				}
+				ctx = context.WithValue(ctx, apiKeyContextKey, apiKey)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// currentUser returns the authenticated user of the request, or nil
+func currentUser(r *http.Request) *entity.User {
+	user, _ := r.Context().Value(userContextKey).(*entity.User)
+	return user
+}
+
+// currentAPIKey returns the API key that authenticated the request, or nil
+// when the request used a session token or none at all
+func currentAPIKey(r *http.Request) *entity.APIKey {
+	key, _ := r.Context().Value(apiKeyContextKey).(*entity.APIKey)
+	return key
+}
+
+// requireUser returns the authenticated user of the request, answering 401
+// and returning false when there is none
+func requireUser(w http.ResponseWriter, r *http.Request) (*entity.User, bool) {
+	user := currentUser(r)
+	if user == nil {
+		writeUnauthorized(w, application.ErrUnauthorized.Error())
+		return nil, false
+	}
+	return user, true
+}
+
+func writeUnauthorized(w http.ResponseWriter, detail string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="fork-and-shaker"`)
+	writeProblem(w, detail, http.StatusUnauthorized)
+}
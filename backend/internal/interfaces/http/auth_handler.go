@@ -0,0 +1,152 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+)
+
+// AuthHandler handles HTTP requests for user accounts and tokens
+type AuthHandler struct {
+	authService *application.AuthService
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(authService *application.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// RegisterRoutes registers the authentication routes
+func (h *AuthHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/auth/register", h.Register).Methods("POST")
+	r.HandleFunc("/api/auth/login", h.Login).Methods("POST")
+	r.HandleFunc("/api/auth/refresh", h.Refresh).Methods("POST")
+	r.HandleFunc("/api/auth/me", h.Me).Methods("GET")
+	r.HandleFunc("/api/auth/keys", h.CreateAPIKey).Methods("POST")
+	r.HandleFunc("/api/auth/keys", h.ListAPIKeys).Methods("GET")
+	r.HandleFunc("/api/auth/keys/{id}", h.RevokeAPIKey).Methods("DELETE")
+}
+
+type registerRequest struct {
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	DisplayName string `json:"display_name"`
+}
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// tokenResponse follows the shape of an OAuth 2.0 token response, with the
+// signed-in user alongside
+type tokenResponse struct {
+	User         *entity.User `json:"user"`
+	AccessToken  string       `json:"access_token"`
+	RefreshToken string       `json:"refresh_token"`
+	TokenType    string       `json:"token_type"`
+	// ExpiresIn is the lifetime of the access token in seconds
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// Register handles creating an account
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, tokens, err := h.authService.Register(r.Context(), req.Email, req.Password, req.DisplayName)
+	if err != nil {
+		h.writeAuthError(w, err, "registering")
+		return
+	}
+	writeTokens(w, http.StatusCreated, user, tokens)
+}
+
+// Login handles signing in with an email and password
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, tokens, err := h.authService.Login(r.Context(), req.Email, req.Password)
+	if err != nil {
+		h.writeAuthError(w, err, "logging in")
+		return
+	}
+	writeTokens(w, http.StatusOK, user, tokens)
+}
+
+// Refresh handles exchanging a refresh token for new tokens
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, tokens, err := h.authService.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		h.writeAuthError(w, err, "refreshing tokens")
+		return
+	}
+	writeTokens(w, http.StatusOK, user, tokens)
+}
+
+// Me handles getting the signed-in user
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// writeAuthError maps auth service errors to HTTP responses
+func (h *AuthHandler) writeAuthError(w http.ResponseWriter, err error, action string) {
+	if writeIfValidationError(w, err) {
+		return
+	}
+	switch err {
+	case application.ErrInvalidCredentials, application.ErrInvalidToken:
+		writeUnauthorized(w, err.Error())
+	case application.ErrAccountDisabled:
+		writeProblem(w, err.Error(), http.StatusForbidden)
+	case application.ErrEmailTaken:
+		writeProblem(w, err.Error(), http.StatusConflict)
+	default:
+		log.Printf("Error %s: %v", action, err)
+		writeProblem(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
+func writeTokens(w http.ResponseWriter, status int, user *entity.User, tokens *application.TokenPair) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(tokenResponse{
+		User:         user,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(time.Until(tokens.ExpiresAt).Round(time.Second) / time.Second),
+	})
+}
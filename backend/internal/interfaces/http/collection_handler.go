@@ -0,0 +1,448 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/units"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// sharedCollectionPath is where a shared collection can be viewed, followed
+// by its share token
+const sharedCollectionPath = "/api/shared/collections/"
+
+// CollectionHandler handles HTTP requests for favorites and recipe
+// collections
+type CollectionHandler struct {
+	collectionService *application.CollectionService
+}
+
+// NewCollectionHandler creates a new CollectionHandler
+func NewCollectionHandler(collectionService *application.CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// RegisterRoutes registers the favorites and collection routes
+func (h *CollectionHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/me/favorites", h.ListFavorites).Methods("GET")
+	r.HandleFunc("/api/me/favorites/{recipe_id}", h.AddFavorite).Methods("PUT")
+	r.HandleFunc("/api/me/favorites/{recipe_id}", h.RemoveFavorite).Methods("DELETE")
+	r.HandleFunc("/api/collections", h.ListCollections).Methods("GET")
+	r.HandleFunc("/api/collections", h.CreateCollection).Methods("POST")
+	r.HandleFunc("/api/collections/{id}", h.GetCollection).Methods("GET")
+	r.HandleFunc("/api/collections/{id}", h.UpdateCollection).Methods("PUT")
+	r.HandleFunc("/api/collections/{id}", h.DeleteCollection).Methods("DELETE")
+	r.HandleFunc("/api/collections/{id}/recipes", h.AddToCollection).Methods("POST")
+	r.HandleFunc("/api/collections/{id}/recipes/{recipe_id}", h.UpdateCollectionEntry).Methods("PUT")
+	r.HandleFunc("/api/collections/{id}/recipes/{recipe_id}", h.RemoveFromCollection).Methods("DELETE")
+	r.HandleFunc("/api/collections/{id}/order", h.ReorderCollection).Methods("PUT")
+	r.HandleFunc("/api/collections/{id}/sharing", h.SetCollectionSharing).Methods("PUT")
+	r.HandleFunc(sharedCollectionPath+"{token}", h.GetSharedCollection).Methods("GET")
+}
+
+type collectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type addToCollectionRequest struct {
+	RecipeID string `json:"recipe_id"`
+	Note     string `json:"note"`
+	// Position is optional; recipes without one are appended
+	Position *int `json:"position"`
+}
+
+type updateCollectionEntryRequest struct {
+	Note     *string `json:"note"`
+	Position *int    `json:"position"`
+}
+
+type reorderCollectionRequest struct {
+	RecipeIDs []string `json:"recipe_ids"`
+}
+
+type collectionSharingRequest struct {
+	Public *bool `json:"public"`
+}
+
+// collectionSummaryResponse describes a collection without its recipes
+type collectionSummaryResponse struct {
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Public      bool               `json:"public"`
+	ShareURL    string             `json:"share_url,omitempty"`
+	RecipeCount int                `json:"recipe_count"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// collectionEntryResponse is a recipe in a collection. Position is its
+// place in the collection and is what entry updates refer to.
+type collectionEntryResponse struct {
+	Position int            `json:"position"`
+	Note     string         `json:"note,omitempty"`
+	AddedAt  time.Time      `json:"added_at"`
+	Recipe   *entity.Recipe `json:"recipe"`
+}
+
+type collectionResponse struct {
+	collectionSummaryResponse
+	OwnerID primitive.ObjectID        `json:"owner_id"`
+	Entries []collectionEntryResponse `json:"entries"`
+}
+
+// newCollectionSummary describes collection. The share link is only
+// included for its owner.
+func newCollectionSummary(collection *entity.Collection, owner bool) collectionSummaryResponse {
+	summary := collectionSummaryResponse{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		Public:      collection.Public,
+		RecipeCount: len(collection.Entries),
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+	}
+	if owner && collection.Public {
+		summary.ShareURL = sharedCollectionPath + collection.ShareToken
+	}
+	return summary
+}
+
+func newCollectionResponse(view *application.CollectionView, owner bool) collectionResponse {
+	resp := collectionResponse{
+		collectionSummaryResponse: newCollectionSummary(view.Collection, owner),
+		OwnerID:                   view.Collection.OwnerID,
+		Entries:                   make([]collectionEntryResponse, len(view.Items)),
+	}
+	resp.RecipeCount = len(view.Items)
+	for i, item := range view.Items {
+		resp.Entries[i] = collectionEntryResponse{
+			Position: view.Collection.IndexOf(item.RecipeID),
+			Note:     item.Note,
+			AddedAt:  item.AddedAt,
+			Recipe:   item.Recipe,
+		}
+	}
+	return resp
+}
+
+// ListFavorites handles listing the recipes the signed-in user bookmarked
+func (h *CollectionHandler) ListFavorites(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recipes, err := h.collectionService.ListFavorites(r.Context(), user)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	presentRecipes(system, recipes...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recipes)
+}
+
+// AddFavorite handles the signed-in user bookmarking a recipe
+func (h *CollectionHandler) AddFavorite(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := primitive.ObjectIDFromHex(mux.Vars(r)["recipe_id"])
+	if err != nil {
+		writeProblem(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionService.AddFavorite(r.Context(), recipeID, user); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveFavorite handles the signed-in user removing a bookmark
+func (h *CollectionHandler) RemoveFavorite(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	recipeID, err := primitive.ObjectIDFromHex(mux.Vars(r)["recipe_id"])
+	if err != nil {
+		writeProblem(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionService.RemoveFavorite(r.Context(), recipeID, user); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListCollections handles listing the signed-in user's collections
+func (h *CollectionHandler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	collections, err := h.collectionService.ListCollections(r.Context(), user)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	resp := make([]collectionSummaryResponse, len(collections))
+	for i, collection := range collections {
+		resp[i] = newCollectionSummary(collection, true)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateCollection handles the signed-in user creating a collection
+func (h *CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req collectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(r.Context(), req.Name, req.Description, user)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newCollectionResponse(&application.CollectionView{
+		Collection: collection,
+		Items:      []application.CollectionItem{},
+	}, true))
+}
+
+// GetCollection handles getting one of the signed-in user's collections
+func (h *CollectionHandler) GetCollection(w http.ResponseWriter, r *http.Request) {
+	h.handleCollection(w, r, func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error) {
+		return h.collectionService.GetCollection(r.Context(), id, user)
+	})
+}
+
+// UpdateCollection handles renaming a collection and replacing its
+// description
+func (h *CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	var req collectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.handleCollection(w, r, func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error) {
+		return h.collectionService.UpdateCollection(r.Context(), id, req.Name, req.Description, user)
+	})
+}
+
+// DeleteCollection handles deleting one of the signed-in user's collections
+func (h *CollectionHandler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(r.Context(), id, user); err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddToCollection handles adding a recipe to a collection
+func (h *CollectionHandler) AddToCollection(w http.ResponseWriter, r *http.Request) {
+	var req addToCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	recipeID, err := primitive.ObjectIDFromHex(req.RecipeID)
+	if err != nil {
+		writeProblem(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+	h.handleCollection(w, r, func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error) {
+		return h.collectionService.AddToCollection(r.Context(), id, recipeID, req.Note, req.Position, user)
+	})
+}
+
+// UpdateCollectionEntry handles changing the note on a recipe in a
+// collection or moving it
+func (h *CollectionHandler) UpdateCollectionEntry(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := primitive.ObjectIDFromHex(mux.Vars(r)["recipe_id"])
+	if err != nil {
+		writeProblem(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+	var req updateCollectionEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.handleCollection(w, r, func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error) {
+		return h.collectionService.UpdateCollectionEntry(r.Context(), id, recipeID, req.Note, req.Position, user)
+	})
+}
+
+// RemoveFromCollection handles taking a recipe out of a collection
+func (h *CollectionHandler) RemoveFromCollection(w http.ResponseWriter, r *http.Request) {
+	recipeID, err := primitive.ObjectIDFromHex(mux.Vars(r)["recipe_id"])
+	if err != nil {
+		writeProblem(w, "Invalid recipe ID", http.StatusBadRequest)
+		return
+	}
+	h.handleCollection(w, r, func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error) {
+		return h.collectionService.RemoveFromCollection(r.Context(), id, recipeID, user)
+	})
+}
+
+// ReorderCollection handles putting the recipes in a collection in a new
+// order
+func (h *CollectionHandler) ReorderCollection(w http.ResponseWriter, r *http.Request) {
+	var req reorderCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	recipeIDs := make([]primitive.ObjectID, len(req.RecipeIDs))
+	for i, hex := range req.RecipeIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			writeProblem(w, "Invalid recipe ID: "+hex, http.StatusBadRequest)
+			return
+		}
+		recipeIDs[i] = id
+	}
+	h.handleCollection(w, r, func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error) {
+		return h.collectionService.ReorderCollection(r.Context(), id, recipeIDs, user)
+	})
+}
+
+// SetCollectionSharing handles sharing a collection by link or making it
+// private again
+func (h *CollectionHandler) SetCollectionSharing(w http.ResponseWriter, r *http.Request) {
+	var req collectionSharingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Public == nil {
+		writeProblem(w, `Request body must be {"public": true|false}`, http.StatusBadRequest)
+		return
+	}
+	h.handleCollection(w, r, func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error) {
+		return h.collectionService.SetCollectionSharing(r.Context(), id, *req.Public, user)
+	})
+}
+
+// GetSharedCollection handles viewing a collection through its share link.
+// No account is needed.
+func (h *CollectionHandler) GetSharedCollection(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	viewer := currentUser(r)
+	view, err := h.collectionService.GetSharedCollection(r.Context(), mux.Vars(r)["token"], viewer)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	owner := viewer != nil && viewer.ID == view.Collection.OwnerID
+	writeCollectionView(w, system, view, owner)
+}
+
+// collectionFunc performs one operation on the signed-in user's collection
+// with the given ID
+type collectionFunc func(user *entity.User, id primitive.ObjectID) (*application.CollectionView, error)
+
+// handleCollection runs the parts of a request on one of the signed-in
+// user's collections that do not depend on the operation: authentication,
+// the ID, the unit system and the response
+func (h *CollectionHandler) handleCollection(w http.ResponseWriter, r *http.Request, fn collectionFunc) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	view, err := fn(user, id)
+	if err != nil {
+		writeCollectionError(w, err)
+		return
+	}
+	writeCollectionView(w, system, view, true)
+}
+
+func writeCollectionView(w http.ResponseWriter, system units.System, view *application.CollectionView, owner bool) {
+	for _, item := range view.Items {
+		presentRecipes(system, item.Recipe)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newCollectionResponse(view, owner))
+}
+
+// writeCollectionError maps favorite and collection errors to HTTP
+// responses
+func writeCollectionError(w http.ResponseWriter, err error) {
+	if writeIfValidationError(w, err) {
+		return
+	}
+	switch err {
+	case application.ErrCollectionNotFound, application.ErrRecipeNotFound, application.ErrNotInCollection:
+		writeProblem(w, err.Error(), http.StatusNotFound)
+	case application.ErrAlreadyInCollection, application.ErrTooManyCollections, application.ErrTooManyFavorites,
+		application.ErrCollectionConflict:
+		writeProblem(w, err.Error(), http.StatusConflict)
+	case application.ErrInvalidOrder:
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+	case application.ErrUnauthorized:
+		writeProblem(w, err.Error(), http.StatusUnauthorized)
+	default:
+		log.Printf("Error handling collection: %v", err)
+		writeProblem(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
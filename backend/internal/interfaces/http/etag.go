@@ -0,0 +1,115 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/units"
+)
+
+var (
+	errIfMatchRequired = errors.New("If-Match header is required")
+	errInvalidIfMatch  = errors.New("If-Match must be * or a list of ETags")
+)
+
+// recipeETag is the strong entity tag for a recipe's current version as
+// presented in system. Amounts converted to another system make a different
+// body, so the system is part of the tag, as in "3-metric".
+func recipeETag(recipe *entity.Recipe, system units.System) string {
+	tag := strconv.FormatInt(recipe.Version, 10)
+	if system != "" {
+		tag += "-" + string(system)
+	}
+	return `"` + tag + `"`
+}
+
+// requireIfMatch parses the If-Match header of a state-changing request into
+// the versions the client expects. Nil versions mean "*", i.e. any version.
+func requireIfMatch(r *http.Request) ([]int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return nil, errIfMatchRequired
+	}
+	return parseIfMatch(header)
+}
+
+// optionalIfMatch is like requireIfMatch but treats a missing header as "*"
+func optionalIfMatch(r *http.Request) ([]int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return nil, nil
+	}
+	return parseIfMatch(header)
+}
+
+// parseIfMatch reads "*" or a comma-separated list of entity tags. Weak
+// tags never satisfy If-Match, which requires strong comparison, so they
+// are skipped; a list of only weak tags matches no version.
+func parseIfMatch(header string) ([]int64, error) {
+	if header == "*" {
+		return nil, nil
+	}
+	versions := []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+		version, ok := parseRecipeETag(tag)
+		if !ok {
+			return nil, errInvalidIfMatch
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// parseRecipeETag reads the version out of a tag made by recipeETag. Any
+// presentation of a version matches it.
+func parseRecipeETag(tag string) (int64, bool) {
+	if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		return 0, false
+	}
+	value, system, converted := strings.Cut(tag[1:len(tag)-1], "-")
+	if _, ok := units.ParseSystem(system); converted && !ok {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// writeIfMatchError answers a request whose If-Match header is missing
+// (428) or malformed (400)
+func writeIfMatchError(w http.ResponseWriter, err error) {
+	if err == errIfMatchRequired {
+		writeProblem(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+	writeProblem(w, err.Error(), http.StatusBadRequest)
+}
+
+// ifNoneMatch reports whether the If-None-Match header matches etag, in
+// which case a GET should answer 304 Not Modified
+func ifNoneMatch(r *http.Request, etag string) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		// If-None-Match uses weak comparison
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/infrastructure/memory"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    []int64
+		wantErr bool
+	}{
+		{header: "*", want: nil},
+		{header: `"3"`, want: []int64{3}},
+		{header: `"3-metric"`, want: []int64{3}},
+		{header: `"3-imperial", "4"`, want: []int64{3, 4}},
+		{header: ` "3" ,"4" `, want: []int64{3, 4}},
+		{header: `W/"3"`, want: []int64{}},
+		{header: `W/"3", "4"`, want: []int64{4}},
+		{header: `3`, wantErr: true},
+		{header: `"3", junk`, wantErr: true},
+		{header: `"3-kelvin"`, wantErr: true},
+		{header: `"abc"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := parseIfMatch(strings.TrimSpace(tt.header))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseIfMatch(%q) error = %v, want error %v", tt.header, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseIfMatch(%q) = %#v, want %#v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+// recipeServer serves the recipe routes on top of in-memory repositories
+type recipeServer struct {
+	router  *mux.Router
+	service *application.RecipeService
+}
+
+func newRecipeServer() *recipeServer {
+	service := application.NewRecipeService(memory.NewRecipeRepository(), memory.NewRevisionRepository(),
+		memory.NewIngredientRepository(), memory.NewReviewRepository(), memory.NewFavoriteRepository(),
+		memory.NewCollectionRepository(), application.NewRecommender())
+	router := mux.NewRouter()
+	NewRecipeHandler(service).RegisterRoutes(router)
+	return &recipeServer{router: router, service: service}
+}
+
+// do serves a request as user, who may be nil, with the given headers
+func (s *recipeServer) do(method, target, body string, user *entity.User, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	if user != nil {
+		r = r.WithContext(context.WithValue(r.Context(), userContextKey, user))
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+func TestRecipeETags(t *testing.T) {
+	s := newRecipeServer()
+	user := &entity.User{ID: primitive.NewObjectID(), Role: entity.RoleUser}
+	recipe, err := s.service.CreateRecipe(context.Background(), entity.RecipeDetails{
+		Name:         "Gimlet",
+		Ingredients:  []entity.Ingredient{{Name: "Gin", Amount: 2, Unit: "oz"}},
+		Instructions: []string{"Shake"},
+	}, user)
+	if err != nil {
+		t.Fatalf("CreateRecipe: %v", err)
+	}
+	target := "/api/recipes/" + recipe.ID.Hex()
+
+	w := s.do("GET", target, "", nil)
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"1"` {
+		t.Fatalf("GET = %d with ETag %s, want 200 with \"1\"", w.Code, w.Header().Get("ETag"))
+	}
+	if w := s.do("GET", target+"?units=metric", "", nil); w.Header().Get("ETag") != `"1-metric"` {
+		t.Errorf("metric GET ETag = %s, want \"1-metric\"", w.Header().Get("ETag"))
+	}
+	if w := s.do("GET", target, "", nil, "If-None-Match", `"1"`); w.Code != http.StatusNotModified {
+		t.Errorf("GET with a matching If-None-Match = %d, want 304", w.Code)
+	}
+	if w := s.do("GET", target+"?units=metric", "", nil, "If-None-Match", `"1"`); w.Code != http.StatusOK {
+		t.Errorf("metric GET with the unconverted ETag = %d, want 200", w.Code)
+	}
+
+	body := `{"name":"Gin Gimlet","ingredients":[{"name":"Gin","amount":2,"unit":"oz"}],"instructions":["Shake"]}`
+	for _, tt := range []struct {
+		ifMatch string
+		want    int
+	}{
+		{"", http.StatusPreconditionRequired},
+		{`"9"`, http.StatusPreconditionFailed},
+		{`W/"1"`, http.StatusPreconditionFailed},
+		{`"1", junk`, http.StatusBadRequest},
+		{`"9", "1-metric"`, http.StatusOK},
+		{`"1"`, http.StatusPreconditionFailed},
+	} {
+		var headers []string
+		if tt.ifMatch != "" {
+			headers = []string{"If-Match", tt.ifMatch}
+		}
+		if w := s.do("PUT", target, body, user, headers...); w.Code != tt.want {
+			t.Errorf("PUT with If-Match %s = %d, want %d", tt.ifMatch, w.Code, tt.want)
+		}
+	}
+
+	if w := s.do("DELETE", target, "", user, "If-Match", `"1"`); w.Code != http.StatusPreconditionFailed {
+		t.Errorf("DELETE with a stale If-Match = %d, want 412", w.Code)
+	}
+	if w := s.do("DELETE", target, "", user, "If-Match", `"2"`); w.Code != http.StatusNoContent {
+		t.Errorf("DELETE with the current If-Match = %d, want 204", w.Code)
+	}
+}
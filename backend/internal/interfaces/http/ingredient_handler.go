@@ -0,0 +1,178 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IngredientHandler handles HTTP requests for the ingredient catalog
+type IngredientHandler struct {
+	ingredientService *application.IngredientService
+}
+
+// NewIngredientHandler creates a new IngredientHandler
+func NewIngredientHandler(ingredientService *application.IngredientService) *IngredientHandler {
+	return &IngredientHandler{
+		ingredientService: ingredientService,
+	}
+}
+
+// RegisterRoutes registers the ingredient catalog routes
+func (h *IngredientHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/ingredients", h.CreateIngredient).Methods("POST")
+	r.HandleFunc("/api/ingredients", h.ListIngredients).Methods("GET")
+	r.HandleFunc("/api/ingredients/{id}", h.GetIngredient).Methods("GET")
+	r.HandleFunc("/api/ingredients/{id}", h.UpdateIngredient).Methods("PUT")
+	r.HandleFunc("/api/ingredients/{id}", h.DeleteIngredient).Methods("DELETE")
+}
+
+type ingredientRequest struct {
+	Name       string                    `json:"name"`
+	Aliases    []string                  `json:"aliases"`
+	Category   entity.IngredientCategory `json:"category"`
+	ABV        *float64                  `json:"abv"`
+	BaseSpirit string                    `json:"base_spirit"`
+}
+
+type ingredientListResponse struct {
+	Items []*entity.CatalogIngredient `json:"items"`
+	Total int64                       `json:"total"`
+}
+
+// CreateIngredient handles adding an ingredient to the catalog
+func (h *IngredientHandler) CreateIngredient(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req ingredientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ingredient, err := h.ingredientService.CreateIngredient(r.Context(), req.Name, req.Aliases,
+		req.Category, req.ABV, req.BaseSpirit, user)
+	if err != nil {
+		writeIngredientError(w, err, "creating")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ingredient)
+}
+
+// ListIngredients handles listing the catalog, optionally by category
+func (h *IngredientHandler) ListIngredients(w http.ResponseWriter, r *http.Request) {
+	category := entity.IngredientCategory(r.URL.Query().Get("category"))
+
+	ingredients, err := h.ingredientService.ListIngredients(r.Context(), category)
+	if err != nil {
+		writeIngredientError(w, err, "listing")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ingredientListResponse{
+		Items: ingredients,
+		Total: int64(len(ingredients)),
+	})
+}
+
+// GetIngredient handles getting a catalog ingredient by ID
+func (h *IngredientHandler) GetIngredient(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	ingredient, err := h.ingredientService.GetIngredientByID(r.Context(), id)
+	if err != nil {
+		writeIngredientError(w, err, "getting")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ingredient)
+}
+
+// UpdateIngredient handles updating a catalog ingredient
+func (h *IngredientHandler) UpdateIngredient(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req ingredientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ingredient, err := h.ingredientService.UpdateIngredient(r.Context(), id, req.Name, req.Aliases,
+		req.Category, req.ABV, req.BaseSpirit, user)
+	if err != nil {
+		writeIngredientError(w, err, "updating")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ingredient)
+}
+
+// DeleteIngredient handles removing an ingredient from the catalog
+func (h *IngredientHandler) DeleteIngredient(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ingredientService.DeleteIngredient(r.Context(), id, user); err != nil {
+		writeIngredientError(w, err, "deleting")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeIngredientError maps ingredient service errors to HTTP responses
+func writeIngredientError(w http.ResponseWriter, err error, action string) {
+	if writeIfValidationError(w, err) {
+		return
+	}
+	switch err {
+	case application.ErrIngredientNotFound:
+		writeProblem(w, err.Error(), http.StatusNotFound)
+	case application.ErrInvalidIngredient:
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+	case application.ErrDuplicateIngredient:
+		writeProblem(w, err.Error(), http.StatusConflict)
+	case application.ErrForbidden:
+		writeProblem(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("Error %s ingredient: %v", action, err)
+		writeProblem(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// problemTypeValidation identifies problems caused by invalid fields in the
+// request; the invalid fields are listed under "errors"
+const problemTypeValidation = "/problems/validation"
+
+// problem is an RFC 7807 problem details body. Every error the API returns
+// uses it.
+type problem struct {
+	Type   string              `json:"type"`
+	Title  string              `json:"title"`
+	Status int                 `json:"status"`
+	Detail string              `json:"detail,omitempty"`
+	Errors []entity.FieldError `json:"errors,omitempty"`
+}
+
+// writeProblem replies with a problem+json body. It takes the same arguments
+// as http.Error so it can be used in its place.
+func writeProblem(w http.ResponseWriter, detail string, status int) {
+	writeProblemBody(w, problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	})
+}
+
+// writeValidationProblem replies 400 with the field errors of verr
+func writeValidationProblem(w http.ResponseWriter, verr *entity.ValidationError) {
+	writeProblemBody(w, problem{
+		Type:   problemTypeValidation,
+		Title:  "Request has invalid fields",
+		Status: http.StatusBadRequest,
+		Detail: verr.Error(),
+		Errors: verr.Errors,
+	})
+}
+
+// writeIfValidationError replies with a validation problem and reports true
+// when err is a *entity.ValidationError
+func writeIfValidationError(w http.ResponseWriter, err error) bool {
+	var verr *entity.ValidationError
+	if !errors.As(err, &verr) {
+		return false
+	}
+	writeValidationProblem(w, verr)
+	return true
+}
+
+func writeProblemBody(w http.ResponseWriter, p problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Printf("Error encoding problem response: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/repository"
+)
+
+type autocompleteResponse struct {
+	Field       repository.AutocompleteField `json:"field"`
+	Prefix      string                       `json:"prefix"`
+	Suggestions []repository.Suggestion      `json:"suggestions"`
+}
+
+// Autocomplete handles suggesting values already used for a free-text
+// recipe field as the user types into it
+func (h *RecipeHandler) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	field := repository.AutocompleteField(q.Get("field"))
+	prefix := q.Get("prefix")
+
+	limit := 0
+	if param := q.Get("limit"); param != "" {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 1 {
+			writeProblem(w, application.ErrInvalidSuggestionLimit.Error(), http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	suggestions, err := h.recipeService.Autocomplete(r.Context(), field, prefix, limit)
+	if err != nil {
+		switch err {
+		case application.ErrInvalidAutocompleteField, application.ErrInvalidSuggestionLimit:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error autocompleting %s: %v", field, err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(autocompleteResponse{
+		Field:       field,
+		Prefix:      prefix,
+		Suggestions: suggestions,
+	})
+}
@@ -2,11 +2,18 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"fork-and-shaker/internal/application"
 	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/domain/search"
+	"fork-and-shaker/internal/domain/units"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -27,37 +34,282 @@ func NewRecipeHandler(recipeService *application.RecipeService) *RecipeHandler {
 // RegisterRoutes registers the recipe routes
 func (h *RecipeHandler) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/api/recipes", h.CreateRecipe).Methods("POST")
-	r.HandleFunc("/api/recipes", h.GetCocktailRecipes).Methods("GET")
+	r.HandleFunc("/api/recipes", h.ListRecipes).Methods("GET")
+	r.HandleFunc("/api/recipes/import", h.ImportRecipes).Methods("POST")
 	r.HandleFunc("/api/recipes/search", h.SearchRecipes).Methods("GET")
 	r.HandleFunc("/api/recipes/by-ingredient", h.FindByIngredient).Methods("GET")
+	r.HandleFunc("/api/recipes/makeable", h.FindMakeable).Methods("POST")
+	r.HandleFunc("/api/taxonomy", h.GetTaxonomy).Methods("GET")
+	r.HandleFunc("/api/autocomplete", h.Autocomplete).Methods("GET")
 	r.HandleFunc("/api/recipes/{id}", h.GetRecipe).Methods("GET")
 	r.HandleFunc("/api/recipes/{id}", h.UpdateRecipe).Methods("PUT")
 	r.HandleFunc("/api/recipes/{id}", h.DeleteRecipe).Methods("DELETE")
+	r.HandleFunc("/api/recipes/{id}/featured", h.SetFeatured).Methods("PUT")
+	r.HandleFunc("/api/recipes/{id}/hidden", h.SetHidden).Methods("PUT")
+	r.HandleFunc("/api/recipes/{id}/fork", h.ForkRecipe).Methods("POST")
+	r.HandleFunc("/api/recipes/{id}/forks", h.GetForks).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/ancestry", h.GetAncestry).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/diff", h.DiffWithParent).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/revisions", h.ListRevisions).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/revisions/diff", h.DiffRevisions).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/revisions/{number:[0-9]+}", h.GetRevision).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/revisions/{number:[0-9]+}/restore", h.RestoreRevision).Methods("POST")
+	r.HandleFunc("/api/recipes/{id}/reviews", h.ListReviews).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/review", h.SaveReview).Methods("PUT")
+	r.HandleFunc("/api/recipes/{id}/review", h.DeleteOwnReview).Methods("DELETE")
+	r.HandleFunc("/api/recipes/{id}/reviews/{review_id}", h.DeleteReview).Methods("DELETE")
+	r.HandleFunc("/api/recipes/{id}/scale", h.ScaleRecipe).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/substitutions", h.GetSubstitutions).Methods("GET")
+	r.HandleFunc("/api/recipes/{id}/similar", h.GetSimilarRecipes).Methods("GET")
 }
 
 type createRecipeRequest struct {
-	Name         string              `json:"name"`
-	Description  string              `json:"description"`
-	Ingredients  []entity.Ingredient `json:"ingredients"`
-	Instructions []string            `json:"instructions"`
-	Glass        string              `json:"glass"`
-	Garnish      string              `json:"garnish"`
+	Name            string                `json:"name"`
+	Type            entity.RecipeType     `json:"type"`
+	Description     string                `json:"description"`
+	Ingredients     []entity.Ingredient   `json:"ingredients"`
+	Instructions    []string              `json:"instructions"`
+	Glass           string                `json:"glass"`
+	Garnish         string                `json:"garnish"`
+	Technique       entity.Technique      `json:"technique"`
+	Tags            []string              `json:"tags"`
+	Flavor          *entity.FlavorProfile `json:"flavor"`
+	BaseSpirit      entity.BaseSpirit     `json:"base_spirit"`
+	Season          entity.Season         `json:"season"`
+	Servings        int                   `json:"servings"`
+	PrepMinutes     int                   `json:"prep_minutes"`
+	CookMinutes     int                   `json:"cook_minutes"`
+	Yield           string                `json:"yield"`
+	Equipment       []string              `json:"equipment"`
+	OvenTemperature *units.Temperature    `json:"oven_temperature"`
 }
 
-type updateRecipeRequest struct {
-	Name         string              `json:"name"`
-	Description  string              `json:"description"`
-	Ingredients  []entity.Ingredient `json:"ingredients"`
-	Instructions []string            `json:"instructions"`
-	Glass        string              `json:"glass"`
-	Garnish      string              `json:"garnish"`
+func (req createRecipeRequest) details() entity.RecipeDetails {
+	return entity.RecipeDetails{
+		Name:            req.Name,
+		Type:            req.Type,
+		Description:     req.Description,
+		Ingredients:     req.Ingredients,
+		Instructions:    req.Instructions,
+		Glass:           req.Glass,
+		Garnish:         req.Garnish,
+		Technique:       req.Technique,
+		Tags:            req.Tags,
+		Flavor:          req.Flavor,
+		BaseSpirit:      req.BaseSpirit,
+		Season:          req.Season,
+		Servings:        req.Servings,
+		PrepMinutes:     req.PrepMinutes,
+		CookMinutes:     req.CookMinutes,
+		Yield:           req.Yield,
+		Equipment:       req.Equipment,
+		OvenTemperature: req.OvenTemperature,
+	}
+}
+
+// updateRecipeRequest replaces every authored field of a recipe
+type updateRecipeRequest = createRecipeRequest
+
+// recipeDetailResponse is a single recipe together with the values the
+// server computes from it
+type recipeDetailResponse struct {
+	*entity.Recipe
+	Strength *entity.Strength `json:"strength,omitempty"`
+}
+
+type makeableRequest struct {
+	Ingredients []string `json:"ingredients"`
+	// MaxMissing defaults to application.DefaultMaxMissing when omitted
+	MaxMissing *int `json:"max_missing"`
+	Limit      int  `json:"limit"`
+}
+
+type nearMissResponse struct {
+	Recipe       *entity.Recipe `json:"recipe"`
+	MissingCount int            `json:"missing_count"`
+	Missing      []string       `json:"missing"`
+}
+
+type makeableResponse struct {
+	Makeable   []*entity.Recipe   `json:"makeable"`
+	NearMisses []nearMissResponse `json:"near_misses"`
+}
+
+// recipePageResponse is the envelope every paginated recipe listing returns
+type recipePageResponse struct {
+	Items      []*entity.Recipe `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	Total      int64            `json:"total"`
+}
+
+// searchHitResponse is a recipe found by a text search with the fields that
+// matched
+type searchHitResponse struct {
+	*entity.Recipe
+	Highlights []search.Highlight `json:"highlights,omitempty"`
+}
+
+// searchResponse is the recipe page envelope for text search, whose items
+// carry highlights
+type searchResponse struct {
+	Items      []searchHitResponse `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	Total      int64               `json:"total"`
+}
+
+// ingredientSearchResponse is a recipe page that also lists the
+// substitutions used to widen an ingredient search
+type ingredientSearchResponse struct {
+	recipePageResponse
+	Substitutions []entity.Substitution `json:"substitutions,omitempty"`
+}
+
+// recipeListResponse is a recipe page together with facet counts for each
+// classification dimension of the listing
+type recipeListResponse struct {
+	recipePageResponse
+	Facets *repository.RecipeFacets `json:"facets"`
+}
+
+func newRecipePageResponse(page *repository.RecipePage) recipePageResponse {
+	items := page.Recipes
+	if items == nil {
+		items = []*entity.Recipe{}
+	}
+	return recipePageResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+}
+
+// parseListOptions reads the limit, cursor and sort query parameters. A
+// leading "-" on the sort field requests descending order.
+func parseListOptions(r *http.Request) (repository.ListOptions, error) {
+	q := r.URL.Query()
+	opts := repository.ListOptions{Cursor: q.Get("cursor")}
+
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return opts, errors.New("limit must be a positive integer")
+		}
+		opts.Limit = n
+	}
+
+	sort := q.Get("sort")
+	if strings.HasPrefix(sort, "-") {
+		opts.Descending = true
+		sort = sort[1:]
+	}
+	opts.Sort = repository.SortField(sort)
+
+	return opts, nil
+}
+
+// parseUnitSystem reads the optional units query parameter that asks for
+// ingredient amounts in metric or imperial units. An empty system means
+// amounts are returned as stored.
+func parseUnitSystem(r *http.Request) (units.System, error) {
+	param := r.URL.Query().Get("units")
+	if param == "" {
+		return "", nil
+	}
+	system, ok := units.ParseSystem(param)
+	if !ok {
+		return "", errors.New("units must be metric or imperial")
+	}
+	return system, nil
+}
+
+// parseRecipeType reads the optional type query parameter. An empty type
+// means recipes of every type.
+func parseRecipeType(r *http.Request) (entity.RecipeType, error) {
+	recipeType := entity.RecipeType(strings.ToLower(r.URL.Query().Get("type")))
+	if recipeType != "" && !recipeType.Valid() {
+		return "", application.ErrInvalidRecipeType
+	}
+	return recipeType, nil
+}
+
+// parseClassificationFilter reads the type, tag, base_spirit, technique,
+// season and flavor query parameters. tag and flavor may be repeated or
+// given as comma-separated lists.
+func parseClassificationFilter(r *http.Request) (repository.ClassificationFilter, error) {
+	q := r.URL.Query()
+	recipeType, err := parseRecipeType(r)
+	if err != nil {
+		return repository.ClassificationFilter{}, err
+	}
+	filter := repository.ClassificationFilter{
+		Type:       recipeType,
+		Tags:       queryList(q, "tag"),
+		BaseSpirit: entity.BaseSpirit(strings.ToLower(q.Get("base_spirit"))),
+		Technique:  entity.Technique(strings.ToLower(q.Get("technique"))),
+		Season:     entity.Season(q.Get("season")),
+	}
+	for _, axis := range queryList(q, "flavor") {
+		filter.Flavors = append(filter.Flavors, entity.FlavorAxis(strings.ToLower(axis)))
+	}
+	return filter, nil
+}
+
+// queryList collects every value of a query parameter that may be repeated
+// or hold a comma-separated list, dropping blanks
+func queryList(q url.Values, key string) []string {
+	var values []string
+	for _, param := range q[key] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseOptionalFloat parses an optional numeric query parameter, returning
+// nil when it is absent
+func parseOptionalFloat(param string) (*float64, error) {
+	if param == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// presentRecipes converts the ingredient amounts of recipes about to be
+// written to the client into the requested unit system
+func presentRecipes(system units.System, recipes ...*entity.Recipe) {
+	if system == "" {
+		return
+	}
+	for _, recipe := range recipes {
+		if recipe != nil {
+			recipe.ConvertUnits(system)
+		}
+	}
 }
 
 // CreateRecipe handles recipe creation
 func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	var req createRecipeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -65,15 +317,17 @@ func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	requestData, _ := json.Marshal(req)
 	log.Printf("Received recipe creation request: %s", string(requestData))
 
-	recipe, err := h.recipeService.CreateRecipe(r.Context(), req.Name, req.Description,
-		req.Ingredients, req.Instructions, req.Glass, req.Garnish)
+	recipe, err := h.recipeService.CreateRecipe(r.Context(), req.details(), user)
 	if err != nil {
+		if writeIfValidationError(w, err) {
+			return
+		}
 		switch err {
 		case application.ErrInvalidRecipe:
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeProblem(w, err.Error(), http.StatusBadRequest)
 		default:
 			log.Printf("Error creating recipe: %v", err)
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -82,106 +336,215 @@ func (h *RecipeHandler) CreateRecipe(w http.ResponseWriter, r *http.Request) {
 	responseData, _ := json.Marshal(recipe)
 	log.Printf("Created recipe: %s", string(responseData))
 
+	presentRecipes(system, recipe)
+
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", recipeETag(recipe, system))
 	w.WriteHeader(http.StatusCreated)
 	if err := json.NewEncoder(w).Encode(recipe); err != nil {
 		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		writeProblem(w, "Error encoding response", http.StatusInternalServerError)
 		return
 	}
 }
 
 // GetRecipe handles getting a recipe by ID
 func (h *RecipeHandler) GetRecipe(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
 		return
 	}
 
-	recipe, err := h.recipeService.GetRecipeByID(r.Context(), id)
+	recipe, err := h.recipeService.GetRecipe(r.Context(), id, currentUser(r))
 	if err != nil {
 		switch err {
 		case application.ErrRecipeNotFound:
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeProblem(w, err.Error(), http.StatusNotFound)
 		default:
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
 
+	// Hidden recipes are only shown to some users, so a shared cache must
+	// not answer one user with another's response
+	etag := recipeETag(recipe, system)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Authorization")
+	if ifNoneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	strength, err := h.recipeService.EstimateStrength(r.Context(), recipe)
+	if err != nil {
+		log.Printf("Error estimating recipe strength: %v", err)
+		writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	presentRecipes(system, recipe)
+	resp := recipeDetailResponse{Recipe: recipe, Strength: strength}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recipe)
+	json.NewEncoder(w).Encode(resp)
 }
 
-// GetCocktailRecipes handles getting all cocktail recipes
-func (h *RecipeHandler) GetCocktailRecipes(w http.ResponseWriter, r *http.Request) {
-	recipes, err := h.recipeService.GetCocktailRecipes(r.Context())
+// ListRecipes handles getting a page of recipes, optionally only those of
+// the type given by the type query parameter or, with featured=true, only
+// featured ones
+func (h *RecipeHandler) ListRecipes(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseClassificationFilter(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if featured := r.URL.Query().Get("featured"); featured != "" {
+		opts.FeaturedOnly, err = strconv.ParseBool(featured)
+		if err != nil {
+			writeProblem(w, "featured must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, facets, err := h.recipeService.ListRecipes(r.Context(), filter, opts)
 	if err != nil {
-		log.Printf("Error getting cocktail recipes: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		if writeIfValidationError(w, err) {
+			return
+		}
+		switch err {
+		case application.ErrInvalidListOptions, repository.ErrInvalidCursor:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error listing recipes: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
 		return
 	}
 
-	// Debug log the recipes being sent
-	responseData, _ := json.Marshal(recipes)
-	log.Printf("Sending recipes: %s", string(responseData))
+	resp := recipeListResponse{
+		recipePageResponse: newRecipePageResponse(page),
+		Facets:             facets,
+	}
+
+	presentRecipes(system, page.Recipes...)
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(recipes); err != nil {
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		writeProblem(w, "Error encoding response", http.StatusInternalServerError)
 		return
 	}
 }
 
 // UpdateRecipe handles updating a recipe
 func (h *RecipeHandler) UpdateRecipe(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	expectedVersions, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
 		return
 	}
 
 	var req updateRecipeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeProblem(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	recipe, err := h.recipeService.UpdateRecipe(r.Context(), id, req.Name, req.Description,
-		req.Ingredients, req.Instructions, req.Glass, req.Garnish)
+	recipe, err := h.recipeService.UpdateRecipe(r.Context(), id, req.details(), user, expectedVersions)
 	if err != nil {
+		if writeIfValidationError(w, err) {
+			return
+		}
 		switch err {
 		case application.ErrRecipeNotFound:
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeProblem(w, err.Error(), http.StatusNotFound)
 		case application.ErrInvalidRecipe:
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		case application.ErrVersionConflict:
+			writeProblem(w, err.Error(), http.StatusPreconditionFailed)
+		case application.ErrForbidden:
+			writeProblem(w, err.Error(), http.StatusForbidden)
 		default:
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
 
+	presentRecipes(system, recipe)
+
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", recipeETag(recipe, system))
 	json.NewEncoder(w).Encode(recipe)
 }
 
 // DeleteRecipe handles deleting a recipe
 func (h *RecipeHandler) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
 	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	expectedVersions, err := requireIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
 		return
 	}
 
-	err = h.recipeService.DeleteRecipe(r.Context(), id)
+	err = h.recipeService.DeleteRecipe(r.Context(), id, user, expectedVersions)
 	if err != nil {
 		switch err {
 		case application.ErrRecipeNotFound:
-			http.Error(w, err.Error(), http.StatusNotFound)
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrVersionConflict:
+			writeProblem(w, err.Error(), http.StatusPreconditionFailed)
+		case application.ErrForbidden:
+			writeProblem(w, err.Error(), http.StatusForbidden)
 		default:
-			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
 		}
 		return
 	}
@@ -191,38 +554,183 @@ func (h *RecipeHandler) DeleteRecipe(w http.ResponseWriter, r *http.Request) {
 
 // SearchRecipes handles searching for recipes
 func (h *RecipeHandler) SearchRecipes(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		http.Error(w, "Search query is required", http.StatusBadRequest)
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	cocktailsOnly := r.URL.Query().Get("cocktails_only") == "true"
+	q := r.URL.Query()
+	query := q.Get("q")
 
-	recipes, err := h.recipeService.SearchRecipes(r.Context(), query, cocktailsOnly)
+	var filter repository.SearchFilter
+	recipeType, err := parseRecipeType(r)
 	if err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if q.Get("cocktails_only") == "true" {
+		recipeType = entity.RecipeTypeCocktail
+	}
+	if recipeType != "" {
+		filter.Type = &recipeType
+	}
+	if filter.MinABV, err = parseOptionalFloat(q.Get("min_abv")); err != nil {
+		writeProblem(w, "min_abv must be a number", http.StatusBadRequest)
+		return
+	}
+	if filter.MaxABV, err = parseOptionalFloat(q.Get("max_abv")); err != nil {
+		writeProblem(w, "max_abv must be a number", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, highlights, err := h.recipeService.SearchRecipes(r.Context(), query, filter, opts)
+	if err != nil {
+		switch err {
+		case application.ErrInvalidListOptions, repository.ErrInvalidCursor,
+			application.ErrEmptySearch, application.ErrInvalidABVRange:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
 		return
 	}
 
+	presentRecipes(system, page.Recipes...)
+
+	resp := searchResponse{
+		Items:      make([]searchHitResponse, len(page.Recipes)),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+	for i, recipe := range page.Recipes {
+		resp.Items[i] = searchHitResponse{Recipe: recipe, Highlights: highlights[recipe.ID]}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recipes)
+	json.NewEncoder(w).Encode(resp)
 }
 
 // FindByIngredient handles searching for recipes by ingredient
 func (h *RecipeHandler) FindByIngredient(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ingredient := r.URL.Query().Get("q")
 	if ingredient == "" {
-		http.Error(w, "Ingredient query is required", http.StatusBadRequest)
+		writeProblem(w, "Ingredient query is required", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	recipes, err := h.recipeService.FindByIngredient(r.Context(), ingredient)
+	includeSubstitutes := false
+	if param := r.URL.Query().Get("include_substitutes"); param != "" {
+		if includeSubstitutes, err = strconv.ParseBool(param); err != nil {
+			writeProblem(w, "include_substitutes must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+	minConfidence := application.DefaultMinConfidence
+	if param := r.URL.Query().Get("min_confidence"); param != "" {
+		if minConfidence, err = strconv.ParseFloat(param, 64); err != nil {
+			writeProblem(w, "min_confidence must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var (
+		page          *repository.RecipePage
+		substitutions []entity.Substitution
+	)
+	if includeSubstitutes {
+		page, substitutions, err = h.recipeService.FindByIngredientOrSubstitute(r.Context(),
+			ingredient, minConfidence, opts)
+	} else {
+		page, err = h.recipeService.FindByIngredient(r.Context(), ingredient, opts)
+	}
 	if err != nil {
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		switch err {
+		case application.ErrInvalidListOptions, repository.ErrInvalidCursor,
+			application.ErrInvalidConfidence:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
 		return
 	}
 
+	presentRecipes(system, page.Recipes...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ingredientSearchResponse{
+		recipePageResponse: newRecipePageResponse(page),
+		Substitutions:      substitutions,
+	})
+}
+
+// FindMakeable handles finding the recipes that can be made from the
+// ingredients on hand
+func (h *RecipeHandler) FindMakeable(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req makeableRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	maxMissing := application.DefaultMaxMissing
+	if req.MaxMissing != nil {
+		maxMissing = *req.MaxMissing
+	}
+
+	result, err := h.recipeService.FindMakeable(r.Context(), req.Ingredients, maxMissing, req.Limit)
+	if err != nil {
+		switch err {
+		case application.ErrNoIngredients, application.ErrInvalidListOptions:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error finding makeable recipes: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, result.Makeable...)
+	for _, m := range result.NearMisses {
+		presentRecipes(system, m.Recipe)
+	}
+
+	resp := makeableResponse{
+		Makeable:   result.Makeable,
+		NearMisses: make([]nearMissResponse, 0, len(result.NearMisses)),
+	}
+	for _, m := range result.NearMisses {
+		resp.NearMisses = append(resp.NearMisses, nearMissResponse{
+			Recipe:       m.Recipe,
+			MissingCount: len(m.Missing),
+			Missing:      m.Missing,
+		})
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(recipes)
+	json.NewEncoder(w).Encode(resp)
 }
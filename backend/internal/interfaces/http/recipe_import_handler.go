@@ -0,0 +1,130 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/recipeimport"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxImportBytes caps the size of an import upload
+const maxImportBytes = 10 << 20
+
+type importResultResponse struct {
+	Row      int                      `json:"row"`
+	Line     int                      `json:"line,omitempty"`
+	Name     string                   `json:"name"`
+	Status   application.ImportStatus `json:"status"`
+	RecipeID *primitive.ObjectID      `json:"recipe_id,omitempty"`
+	Errors   []entity.FieldError      `json:"errors,omitempty"`
+}
+
+type importReportResponse struct {
+	Format  recipeimport.Format    `json:"format"`
+	DryRun  bool                   `json:"dry_run"`
+	Created int                    `json:"created"`
+	Valid   int                    `json:"valid"`
+	Invalid int                    `json:"invalid"`
+	Failed  int                    `json:"failed"`
+	Results []importResultResponse `json:"results"`
+	// Error explains why an import stopped part way
+	Error string `json:"error,omitempty"`
+}
+
+// ImportRecipes handles importing recipes from a multipart upload. The
+// file part holds the recipes; the optional format field names their
+// format, which is otherwise guessed from the file. With dry_run=true the
+// recipes are only validated. When a storage error stops the import the
+// report is still sent, with status 500, so the client can tell which
+// recipes were created.
+func (h *RecipeHandler) ImportRecipes(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+		writeProblem(w, "Request must be a multipart upload of at most 10 MB", http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeProblem(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeProblem(w, "Could not read the uploaded file", http.StatusBadRequest)
+		return
+	}
+
+	format := recipeimport.DetectFormat(header.Filename, data)
+	if name := r.FormValue("format"); name != "" {
+		if format, err = recipeimport.ParseFormat(name); err != nil {
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	dryRun := false
+	if param := r.FormValue("dry_run"); param != "" {
+		if dryRun, err = strconv.ParseBool(param); err != nil {
+			writeProblem(w, "dry_run must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.recipeService.ImportRecipes(r.Context(), format, data, user, dryRun)
+	if err != nil && report == nil {
+		switch {
+		case errors.Is(err, recipeimport.ErrMalformedFile), errors.Is(err, recipeimport.ErrUnknownFormat),
+			errors.Is(err, application.ErrTooManyImports):
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error importing recipes: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := importReportResponse{
+		Format:  format,
+		DryRun:  report.DryRun,
+		Created: report.Created,
+		Valid:   report.Valid,
+		Invalid: report.Invalid,
+		Failed:  report.Failed,
+		Results: make([]importResultResponse, len(report.Results)),
+	}
+	for i, result := range report.Results {
+		resp.Results[i] = importResultResponse{
+			Row:      result.Row,
+			Line:     result.Line,
+			Name:     result.Name,
+			Status:   result.Status,
+			RecipeID: result.RecipeID,
+			Errors:   result.Errors,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case err != nil:
+		log.Printf("Error importing recipes: %v", err)
+		resp.Error = "Internal server error; the import stopped at the failed recipe"
+		w.WriteHeader(http.StatusInternalServerError)
+	case report.Created > 0:
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
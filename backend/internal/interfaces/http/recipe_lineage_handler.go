@@ -0,0 +1,175 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/repository"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type forkRecipeRequest struct {
+	Name string `json:"name"`
+}
+
+type forkDiffResponse struct {
+	Parent  *entity.Recipe       `json:"parent"`
+	Changes []entity.FieldChange `json:"changes"`
+}
+
+// ForkRecipe handles forking a recipe. The request body is optional and may
+// give the fork a new name.
+func (h *RecipeHandler) ForkRecipe(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req forkRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fork, err := h.recipeService.ForkRecipe(r.Context(), id, req.Name, user)
+	if err != nil {
+		if writeIfValidationError(w, err) {
+			return
+		}
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrInvalidRecipe:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error forking recipe: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, fork)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(fork)
+}
+
+// GetForks handles listing the direct forks of a recipe
+func (h *RecipeHandler) GetForks(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.recipeService.GetForks(r.Context(), id, currentUser(r), opts)
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrInvalidListOptions, repository.ErrInvalidCursor:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, page.Recipes...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newRecipePageResponse(page))
+}
+
+// GetAncestry handles walking a recipe's lineage back to the original
+func (h *RecipeHandler) GetAncestry(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	ancestors, err := h.recipeService.GetAncestry(r.Context(), id, currentUser(r))
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, ancestors...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ancestors)
+}
+
+// DiffWithParent handles comparing a fork against its parent
+func (h *RecipeHandler) DiffWithParent(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.recipeService.DiffWithParent(r.Context(), id, currentUser(r), system)
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound, application.ErrParentNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrNotAFork:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(forkDiffResponse{Parent: diff.Parent, Changes: diff.Changes})
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type featureRecipeRequest struct {
+	Featured *bool `json:"featured"`
+}
+
+type hideRecipeRequest struct {
+	Hidden *bool `json:"hidden"`
+}
+
+// moderationFunc applies one moderation change to the recipe with the given
+// ID on behalf of actor
+type moderationFunc func(ctx context.Context, id primitive.ObjectID, actor *entity.User,
+	expectedVersions []int64) (*entity.Recipe, error)
+
+// SetFeatured handles a moderator featuring a recipe or un-featuring it
+func (h *RecipeHandler) SetFeatured(w http.ResponseWriter, r *http.Request) {
+	var req featureRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Featured == nil {
+		writeProblem(w, `Request body must be {"featured": true|false}`, http.StatusBadRequest)
+		return
+	}
+	h.moderateRecipe(w, r, func(ctx context.Context, id primitive.ObjectID, actor *entity.User,
+		expectedVersions []int64) (*entity.Recipe, error) {
+		return h.recipeService.SetFeatured(ctx, id, *req.Featured, actor, expectedVersions)
+	})
+}
+
+// SetHidden handles a moderator hiding a recipe or making it visible again
+func (h *RecipeHandler) SetHidden(w http.ResponseWriter, r *http.Request) {
+	var req hideRecipeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Hidden == nil {
+		writeProblem(w, `Request body must be {"hidden": true|false}`, http.StatusBadRequest)
+		return
+	}
+	h.moderateRecipe(w, r, func(ctx context.Context, id primitive.ObjectID, actor *entity.User,
+		expectedVersions []int64) (*entity.Recipe, error) {
+		return h.recipeService.SetHidden(ctx, id, *req.Hidden, actor, expectedVersions)
+	})
+}
+
+// moderateRecipe runs the parts of a moderation request that do not depend
+// on the change: authentication, the ID, the optional If-Match header and
+// the response
+func (h *RecipeHandler) moderateRecipe(w http.ResponseWriter, r *http.Request, moderate moderationFunc) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	expectedVersions, err := optionalIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
+	recipe, err := moderate(r.Context(), id, user, expectedVersions)
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrVersionConflict:
+			writeProblem(w, err.Error(), http.StatusPreconditionFailed)
+		case application.ErrForbidden:
+			writeProblem(w, err.Error(), http.StatusForbidden)
+		default:
+			log.Printf("Error moderating recipe: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, recipe)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", recipeETag(recipe, system))
+	json.NewEncoder(w).Encode(recipe)
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/application"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reviewRequest struct {
+	Rating int    `json:"rating"`
+	Text   string `json:"text"`
+}
+
+// ListReviews handles listing a recipe's reviews, newest first
+func (h *RecipeHandler) ListReviews(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	reviews, err := h.recipeService.ListReviews(r.Context(), id, currentUser(r))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reviews)
+}
+
+// SaveReview handles the signed-in user rating and reviewing a recipe, or
+// editing their existing review of it
+func (h *RecipeHandler) SaveReview(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	var req reviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeProblem(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review, created, err := h.recipeService.SaveReview(r.Context(), id, req.Rating, req.Text, user)
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(review)
+}
+
+// DeleteOwnReview handles the signed-in user deleting their review of a
+// recipe
+func (h *RecipeHandler) DeleteOwnReview(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.recipeService.DeleteOwnReview(r.Context(), id, user); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteReview handles deleting any review of a recipe, which its author
+// and moderators may do
+func (h *RecipeHandler) DeleteReview(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := primitive.ObjectIDFromHex(vars["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	reviewID, err := primitive.ObjectIDFromHex(vars["review_id"])
+	if err != nil {
+		writeProblem(w, "Invalid review ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.recipeService.DeleteReview(r.Context(), id, reviewID, user); err != nil {
+		writeReviewError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeReviewError maps review errors to HTTP responses
+func writeReviewError(w http.ResponseWriter, err error) {
+	if writeIfValidationError(w, err) {
+		return
+	}
+	switch err {
+	case application.ErrRecipeNotFound, application.ErrReviewNotFound:
+		writeProblem(w, err.Error(), http.StatusNotFound)
+	case application.ErrReviewOwnRecipe:
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+	case application.ErrDuplicateReview:
+		writeProblem(w, err.Error(), http.StatusConflict)
+	case application.ErrForbidden:
+		writeProblem(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Printf("Error handling review: %v", err)
+		writeProblem(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
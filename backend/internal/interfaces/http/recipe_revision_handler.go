@@ -0,0 +1,195 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type revisionResponse struct {
+	Number       int                  `json:"number"`
+	EditedBy     string               `json:"edited_by,omitempty"`
+	RestoredFrom int                  `json:"restored_from,omitempty"`
+	CreatedAt    time.Time            `json:"created_at"`
+	Changes      []entity.FieldChange `json:"changes"`
+	Recipe       *entity.Recipe       `json:"recipe,omitempty"`
+}
+
+type revisionDiffResponse struct {
+	From    int                  `json:"from"`
+	To      int                  `json:"to"`
+	Changes []entity.FieldChange `json:"changes"`
+}
+
+// newRevisionResponse renders a revision entry, including the full recipe
+// snapshot only when withRecipe is set
+func newRevisionResponse(entry *application.RevisionEntry, withRecipe bool) revisionResponse {
+	resp := revisionResponse{
+		Number:       entry.Revision.Number,
+		EditedBy:     entry.Revision.EditedBy,
+		RestoredFrom: entry.Revision.RestoredFrom,
+		CreatedAt:    entry.Revision.CreatedAt,
+		Changes:      entry.Changes,
+	}
+	if withRecipe {
+		resp.Recipe = &entry.Revision.Recipe
+	}
+	return resp
+}
+
+// ListRevisions handles listing a recipe's revision history
+func (h *RecipeHandler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.recipeService.ListRevisions(r.Context(), id, currentUser(r))
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := make([]revisionResponse, 0, len(entries))
+	for _, entry := range entries {
+		resp = append(resp, newRevisionResponse(entry, false))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetRevision handles getting a single revision of a recipe
+func (h *RecipeHandler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	number, _ := strconv.Atoi(mux.Vars(r)["number"])
+
+	entry, err := h.recipeService.GetRevision(r.Context(), id, number, currentUser(r))
+	if err != nil {
+		switch err {
+		case application.ErrRevisionNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, &entry.Revision.Recipe)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newRevisionResponse(entry, true))
+}
+
+// DiffRevisions handles comparing two revisions given by the from and to
+// query parameters
+func (h *RecipeHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	from, err := strconv.Atoi(r.URL.Query().Get("from"))
+	if err != nil {
+		writeProblem(w, "from must be a revision number", http.StatusBadRequest)
+		return
+	}
+	to, err := strconv.Atoi(r.URL.Query().Get("to"))
+	if err != nil {
+		writeProblem(w, "to must be a revision number", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := h.recipeService.DiffRevisions(r.Context(), id, from, to, currentUser(r))
+	if err != nil {
+		switch err {
+		case application.ErrRevisionNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		default:
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisionDiffResponse{From: from, To: to, Changes: changes})
+}
+
+// RestoreRevision handles making an old revision the current version
+func (h *RecipeHandler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	number, _ := strconv.Atoi(mux.Vars(r)["number"])
+
+	expectedVersions, err := optionalIfMatch(r)
+	if err != nil {
+		writeIfMatchError(w, err)
+		return
+	}
+
+	recipe, err := h.recipeService.RestoreRevision(r.Context(), id, number, user, expectedVersions)
+	if err != nil {
+		if writeIfValidationError(w, err) {
+			return
+		}
+		switch err {
+		case application.ErrRecipeNotFound, application.ErrRevisionNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrInvalidRecipe:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		case application.ErrVersionConflict:
+			writeProblem(w, err.Error(), http.StatusPreconditionFailed)
+		case application.ErrForbidden:
+			writeProblem(w, err.Error(), http.StatusForbidden)
+		default:
+			log.Printf("Error restoring revision: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, recipe)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", recipeETag(recipe, system))
+	json.NewEncoder(w).Encode(recipe)
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+	"fork-and-shaker/internal/domain/units"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type batchPlanResponse struct {
+	DilutionPercent float64        `json:"dilution_percent"`
+	Liquid          units.Quantity `json:"liquid"`
+	Water           units.Quantity `json:"water"`
+	Total           units.Quantity `json:"total"`
+	PerServing      units.Quantity `json:"per_serving"`
+}
+
+type scaledRecipeResponse struct {
+	Recipe   *entity.Recipe     `json:"recipe"`
+	Servings int                `json:"servings"`
+	Factor   float64            `json:"factor"`
+	Batch    *batchPlanResponse `json:"batch,omitempty"`
+}
+
+// ScaleRecipe handles scaling a recipe to a number of servings. With
+// batch=true the response also says how much water to add to a pre-batched
+// cocktail, diluting it by the dilution query parameter (a percentage).
+func (h *RecipeHandler) ScaleRecipe(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	servings, err := strconv.Atoi(q.Get("servings"))
+	if err != nil {
+		writeProblem(w, "servings must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	batch := false
+	if param := q.Get("batch"); param != "" {
+		if batch, err = strconv.ParseBool(param); err != nil {
+			writeProblem(w, "batch must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	dilution := application.DefaultDilutionPercent
+	if param := q.Get("dilution"); param != "" {
+		if dilution, err = strconv.ParseFloat(param, 64); err != nil {
+			writeProblem(w, "dilution must be a number", http.StatusBadRequest)
+			return
+		}
+	}
+
+	scaled, err := h.recipeService.ScaleRecipe(r.Context(), id, servings, batch, dilution, system, currentUser(r))
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrInvalidServings, application.ErrInvalidDilution,
+			application.ErrBatchNotCocktail:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error scaling recipe: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := scaledRecipeResponse{
+		Recipe:   scaled.Recipe,
+		Servings: scaled.Servings,
+		Factor:   scaled.Factor,
+	}
+	if plan := scaled.Batch; plan != nil {
+		resp.Batch = &batchPlanResponse{
+			DilutionPercent: plan.DilutionPercent,
+			Liquid:          plan.Liquid,
+			Water:           plan.Water,
+			Total:           plan.Total,
+			PerServing:      plan.PerServing,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type similarRecipeResponse struct {
+	Recipe *entity.Recipe `json:"recipe"`
+	Score  float64        `json:"score"`
+}
+
+type similarRecipesResponse struct {
+	Items []similarRecipeResponse `json:"items"`
+}
+
+// GetSimilarRecipes handles listing the recipes most like a recipe. The
+// limit query parameter sets how many.
+func (h *RecipeHandler) GetSimilarRecipes(w http.ResponseWriter, r *http.Request) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if param := r.URL.Query().Get("limit"); param != "" {
+		if limit, err = strconv.Atoi(param); err != nil || limit < 1 {
+			writeProblem(w, application.ErrInvalidSimilarLimit.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	similar, err := h.recipeService.SimilarRecipes(r.Context(), id, currentUser(r), limit)
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrInvalidSimilarLimit:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error finding similar recipes: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := similarRecipesResponse{Items: make([]similarRecipeResponse, len(similar))}
+	for i, s := range similar {
+		presentRecipes(system, s.Recipe)
+		resp.Items[i] = similarRecipeResponse{Recipe: s.Recipe, Score: math.Round(s.Score*1000) / 1000}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/entity"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ingredientSubstitutesResponse struct {
+	Ingredient  entity.Ingredient   `json:"ingredient"`
+	Substitutes []entity.Substitute `json:"substitutes"`
+}
+
+type substitutionsResponse struct {
+	Ingredients []ingredientSubstitutesResponse `json:"ingredients"`
+}
+
+// GetSubstitutions handles suggesting substitutes for each ingredient of a
+// recipe
+func (h *RecipeHandler) GetSubstitutions(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.recipeService.GetSubstitutions(r.Context(), id, currentUser(r))
+	if err != nil {
+		switch err {
+		case application.ErrRecipeNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		default:
+			log.Printf("Error getting substitutions: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	resp := substitutionsResponse{
+		Ingredients: make([]ingredientSubstitutesResponse, 0, len(result)),
+	}
+	for _, item := range result {
+		resp.Ingredients = append(resp.Ingredients, ingredientSubstitutesResponse{
+			Ingredient:  item.Ingredient,
+			Substitutes: item.Substitutes,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"fork-and-shaker/internal/domain/entity"
+)
+
+// taxonomyResponse lists the values recipes can be classified and filtered
+// by. Tags outside CuratedTags are allowed too.
+type taxonomyResponse struct {
+	CuratedTags      []string            `json:"curated_tags"`
+	BaseSpirits      []entity.BaseSpirit `json:"base_spirits"`
+	Techniques       []entity.Technique  `json:"techniques"`
+	Seasons          []entity.Season     `json:"seasons"`
+	FlavorAxes       []entity.FlavorAxis `json:"flavor_axes"`
+	MaxFlavorLevel   int                 `json:"max_flavor_level"`
+	FlavorPronounced int                 `json:"flavor_pronounced"`
+}
+
+// GetTaxonomy handles listing the curated tags and the values of every other
+// classification dimension, for building filter menus
+func (h *RecipeHandler) GetTaxonomy(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(taxonomyResponse{
+		CuratedTags:      entity.CuratedTags,
+		BaseSpirits:      entity.BaseSpirits,
+		Techniques:       entity.Techniques,
+		Seasons:          entity.Seasons,
+		FlavorAxes:       entity.FlavorAxes,
+		MaxFlavorLevel:   entity.MaxFlavorLevel,
+		FlavorPronounced: entity.FlavorPronounced,
+	})
+}
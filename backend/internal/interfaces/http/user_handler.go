@@ -0,0 +1,109 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/repository"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserHandler handles HTTP requests for user profiles and account
+// administration
+type UserHandler struct {
+	userService *application.UserService
+}
+
+// NewUserHandler creates a new UserHandler
+func NewUserHandler(userService *application.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// RegisterRoutes registers the user profile and admin routes
+func (h *UserHandler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/api/users/{id}/recipes", h.GetUserRecipes).Methods("GET")
+	r.HandleFunc("/api/me/recipes", h.GetMyRecipes).Methods("GET")
+	r.HandleFunc("/api/admin/users", h.FindUser).Methods("GET")
+	r.HandleFunc("/api/admin/users/{id}", h.GetUser).Methods("GET")
+	r.HandleFunc("/api/admin/users/{id}/role", h.SetRole).Methods("PUT")
+	r.HandleFunc("/api/admin/users/{id}/disabled", h.SetDisabled).Methods("PUT")
+}
+
+type userProfileResponse struct {
+	ID          primitive.ObjectID `json:"id"`
+	DisplayName string             `json:"display_name"`
+	RecipeCount int64              `json:"recipe_count"`
+}
+
+// userRecipesResponse is a recipe page with the profile of the user who
+// created the recipes
+type userRecipesResponse struct {
+	User userProfileResponse `json:"user"`
+	recipePageResponse
+}
+
+// GetUserRecipes handles listing the recipes a user created. Hidden recipes
+// are included for the user themself and for moderators.
+func (h *UserHandler) GetUserRecipes(w http.ResponseWriter, r *http.Request) {
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		writeProblem(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+	h.writeUserRecipes(w, r, id)
+}
+
+// GetMyRecipes handles listing the recipes the signed-in user created
+func (h *UserHandler) GetMyRecipes(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireUser(w, r)
+	if !ok {
+		return
+	}
+	h.writeUserRecipes(w, r, user.ID)
+}
+
+func (h *UserHandler) writeUserRecipes(w http.ResponseWriter, r *http.Request, id primitive.ObjectID) {
+	system, err := parseUnitSystem(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		writeProblem(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	profile, page, err := h.userService.ListUserRecipes(r.Context(), id, currentUser(r), opts)
+	if err != nil {
+		switch err {
+		case application.ErrUserNotFound:
+			writeProblem(w, err.Error(), http.StatusNotFound)
+		case application.ErrInvalidListOptions, repository.ErrInvalidCursor:
+			writeProblem(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error listing user recipes: %v", err)
+			writeProblem(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	presentRecipes(system, page.Recipes...)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(userRecipesResponse{
+		User: userProfileResponse{
+			ID:          profile.ID,
+			DisplayName: profile.DisplayName,
+			RecipeCount: profile.RecipeCount,
+		},
+		recipePageResponse: newRecipePageResponse(page),
+	})
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -10,7 +11,10 @@ import (
 
 	"fork-and-shaker/config"
 	"fork-and-shaker/internal/application"
+	"fork-and-shaker/internal/domain/repository"
+	"fork-and-shaker/internal/infrastructure/memory"
 	"fork-and-shaker/internal/infrastructure/mongodb"
+	"fork-and-shaker/internal/infrastructure/sqlite"
 	handlers "fork-and-shaker/internal/interfaces/http"
 
 	"github.com/gorilla/mux"
@@ -24,20 +28,85 @@ func main() {
 		log.Println("No .env file found")
 	}
 
-	// Connect to MongoDB
-	if err := config.ConnectDB(); err != nil {
-		log.Fatal("Could not connect to MongoDB:", err)
+	// Initialize repositories for the configured storage driver
+	var (
+		recipeRepo     repository.RecipeRepository
+		revisionRepo   repository.RevisionRepository
+		ingredientRepo repository.IngredientRepository
+		userRepo       repository.UserRepository
+		apiKeyRepo     repository.APIKeyRepository
+		reviewRepo     repository.ReviewRepository
+		favoriteRepo   repository.FavoriteRepository
+		collectionRepo repository.CollectionRepository
+	)
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "mongodb":
+		// Connect to MongoDB
+		if err := config.ConnectDB(); err != nil {
+			log.Fatal("Could not connect to MongoDB:", err)
+		}
+		defer config.DisconnectDB()
+
+		recipeRepo = mongodb.NewRecipeRepository(config.MongoDB)
+		revisionRepo = mongodb.NewRevisionRepository(config.MongoDB)
+		ingredientRepo = mongodb.NewIngredientRepository(config.MongoDB)
+		userRepo = mongodb.NewUserRepository(config.MongoDB)
+		apiKeyRepo = mongodb.NewAPIKeyRepository(config.MongoDB)
+		reviewRepo = mongodb.NewReviewRepository(config.MongoDB)
+		favoriteRepo = mongodb.NewFavoriteRepository(config.MongoDB)
+		collectionRepo = mongodb.NewCollectionRepository(config.MongoDB)
+	case "sqlite":
+		if err := config.ConnectSQLite(); err != nil {
+			log.Fatal("Could not open SQLite database:", err)
+		}
+		defer config.DisconnectSQLite()
+
+		recipeRepo = sqlite.NewRecipeRepository(config.SQLiteDB)
+		revisionRepo = sqlite.NewRevisionRepository(config.SQLiteDB)
+		ingredientRepo = sqlite.NewIngredientRepository(config.SQLiteDB)
+		userRepo = sqlite.NewUserRepository(config.SQLiteDB)
+		apiKeyRepo = sqlite.NewAPIKeyRepository(config.SQLiteDB)
+		reviewRepo = sqlite.NewReviewRepository(config.SQLiteDB)
+		favoriteRepo = sqlite.NewFavoriteRepository(config.SQLiteDB)
+		collectionRepo = sqlite.NewCollectionRepository(config.SQLiteDB)
+	case "memory":
+		log.Println("Using in-memory storage, data will not survive a restart")
+		recipeRepo = memory.NewRecipeRepository()
+		revisionRepo = memory.NewRevisionRepository()
+		ingredientRepo = memory.NewIngredientRepository()
+		userRepo = memory.NewUserRepository()
+		apiKeyRepo = memory.NewAPIKeyRepository()
+		reviewRepo = memory.NewReviewRepository()
+		favoriteRepo = memory.NewFavoriteRepository()
+		collectionRepo = memory.NewCollectionRepository()
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q", driver)
 	}
-	defer config.DisconnectDB()
 
-	// Initialize repositories
-	recipeRepo := mongodb.NewRecipeRepository(config.MongoDB)
+	authConfig, err := config.LoadAuthConfig()
+	if err != nil {
+		log.Fatal("Invalid auth configuration:", err)
+	}
+
+	recommender := application.NewRecommender()
+	if err := recommender.Load(context.Background(), recipeRepo); err != nil {
+		log.Fatal("Could not index recipes for recommendations:", err)
+	}
 
 	// Initialize services
-	recipeService := application.NewRecipeService(recipeRepo)
+	authService := application.NewAuthService(userRepo, apiKeyRepo, authConfig)
+	recipeService := application.NewRecipeService(recipeRepo, revisionRepo, ingredientRepo, reviewRepo,
+		favoriteRepo, collectionRepo, recommender)
+	ingredientService := application.NewIngredientService(ingredientRepo, recipeService)
+	userService := application.NewUserService(userRepo, recipeRepo)
+	collectionService := application.NewCollectionService(collectionRepo, favoriteRepo, recipeRepo)
 
 	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(authService)
 	recipeHandler := handlers.NewRecipeHandler(recipeService)
+	ingredientHandler := handlers.NewIngredientHandler(ingredientService)
+	userHandler := handlers.NewUserHandler(userService)
+	collectionHandler := handlers.NewCollectionHandler(collectionService)
 
 	// Get port from environment variable or use default
 	port := os.Getenv("PORT")
@@ -49,11 +118,16 @@ func main() {
 	r := mux.NewRouter()
 
 	// Register routes
+	authHandler.RegisterRoutes(r)
 	recipeHandler.RegisterRoutes(r)
+	ingredientHandler.RegisterRoutes(r)
+	userHandler.RegisterRoutes(r)
+	collectionHandler.RegisterRoutes(r)
 	r.HandleFunc("/api/health", healthCheckHandler).Methods("GET")
 
 	// Add middleware
 	r.Use(loggingMiddleware)
+	r.Use(handlers.AuthMiddleware(authService))
 
 	// Setup CORS
 	c := cors.New(cors.Options{
@@ -64,6 +138,7 @@ func main() {
 		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders: []string{"*"}, // Allow all headers
 		Debug:          true,          // Enable debugging for troubleshooting
+		ExposedHeaders: []string{"ETag"},
 	})
 
 	// Create a handler with CORS middleware
@@ -4,20 +4,25 @@ import Layout from './components/Layout'
 import Home from './pages/Home'
 import CreateRecipe from './pages/CreateRecipe'
 import RecipeDetail from './pages/RecipeDetail'
+import Login from './pages/Login'
 import { ThemeProvider } from './components/ThemeProvider'
+import { AuthProvider } from './components/AuthProvider'
 
 function App() {
   return (
     <ThemeProvider>
-      <Router>
-        <Routes>
-          <Route path="/" element={<Layout />}>
-            <Route index element={<Home />} />
-            <Route path="create" element={<CreateRecipe />} />
-            <Route path="recipe/:id" element={<RecipeDetail />} />
-          </Route>
-        </Routes>
-      </Router>
+      <AuthProvider>
+        <Router>
+          <Routes>
+            <Route path="/" element={<Layout />}>
+              <Route index element={<Home />} />
+              <Route path="create" element={<CreateRecipe />} />
+              <Route path="recipe/:id" element={<RecipeDetail />} />
+              <Route path="login" element={<Login />} />
+            </Route>
+          </Routes>
+        </Router>
+      </AuthProvider>
     </ThemeProvider>
   )
 }
@@ -0,0 +1,121 @@
+import React, { createContext, useContext, useState } from 'react'
+import axios from 'axios'
+
+export const API_URL = 'http://localhost:8080'
+
+export interface User {
+  id: string
+  email: string
+  display_name: string
+  role: string
+}
+
+// TokenResponse is what the API answers a login, registration or refresh with
+interface TokenResponse {
+  user: User
+  access_token: string
+  refresh_token: string
+  token_type: string
+  expires_in: number
+}
+
+interface Session {
+  user: User
+  accessToken: string
+  refreshToken: string
+}
+
+const STORAGE_KEY = 'fork-and-shaker.session'
+
+const loadSession = (): Session | null => {
+  try {
+    const stored = localStorage.getItem(STORAGE_KEY)
+    return stored ? (JSON.parse(stored) as Session) : null
+  } catch {
+    return null
+  }
+}
+
+const AuthContext = createContext<{
+  user: User | null
+  accessToken: string | null
+  login: (email: string, password: string) => Promise<void>
+  register: (email: string, password: string, displayName: string) => Promise<void>
+  logout: () => void
+  refresh: () => Promise<string | null>
+}>({
+  user: null,
+  accessToken: null,
+  login: async () => {},
+  register: async () => {},
+  logout: () => {},
+  refresh: async () => null,
+})
+
+export const useAuth = () => useContext(AuthContext)
+
+export function AuthProvider({ children }: { children: React.ReactNode }) {
+  const [session, setSession] = useState<Session | null>(loadSession)
+
+  const save = (tokens: TokenResponse) => {
+    const next = {
+      user: tokens.user,
+      accessToken: tokens.access_token,
+      refreshToken: tokens.refresh_token,
+    }
+    localStorage.setItem(STORAGE_KEY, JSON.stringify(next))
+    setSession(next)
+    return next
+  }
+
+  const logout = () => {
+    localStorage.removeItem(STORAGE_KEY)
+    setSession(null)
+  }
+
+  const login = async (email: string, password: string) => {
+    const response = await axios.post<TokenResponse>(`${API_URL}/api/auth/login`, { email, password })
+    save(response.data)
+  }
+
+  const register = async (email: string, password: string, displayName: string) => {
+    const response = await axios.post<TokenResponse>(`${API_URL}/api/auth/register`, {
+      email,
+      password,
+      display_name: displayName,
+    })
+    save(response.data)
+  }
+
+  // refresh exchanges the refresh token for a new access token, signing out
+  // when the session can no longer be renewed
+  const refresh = async () => {
+    if (!session) {
+      return null
+    }
+    try {
+      const response = await axios.post<TokenResponse>(`${API_URL}/api/auth/refresh`, {
+        refresh_token: session.refreshToken,
+      })
+      return save(response.data).accessToken
+    } catch {
+      logout()
+      return null
+    }
+  }
+
+  return (
+    <AuthContext.Provider
+      value={{
+        user: session?.user ?? null,
+        accessToken: session?.accessToken ?? null,
+        login,
+        register,
+        logout,
+        refresh,
+      }}
+    >
+      {children}
+    </AuthContext.Provider>
+  )
+}
@@ -4,6 +4,7 @@ import { Bars3Icon, XMarkIcon } from '@heroicons/react/24/outline'
 import { MoonIcon, SunIcon } from '@heroicons/react/24/solid'
 import { Link, Outlet, useLocation } from 'react-router-dom'
 import { useTheme } from './ThemeProvider'
+import { useAuth } from './AuthProvider'
 
 const navigation = [
   { name: 'Recipes', href: '/' },
@@ -13,6 +14,7 @@ const navigation = [
 export default function Layout() {
   const location = useLocation()
   const { theme, toggleTheme } = useTheme()
+  const { user, logout } = useAuth()
 
   return (
     <div className="min-h-screen flex flex-col bg-background text-foreground">
@@ -50,6 +52,27 @@ export default function Layout() {
                   </div>
                 </div>
                 <div className="flex items-center space-x-4">
+                  {user ? (
+                    <>
+                      <span className="hidden sm:inline text-sm text-muted-foreground">
+                        {user.display_name}
+                      </span>
+                      <button
+                        onClick={logout}
+                        className="text-sm font-medium text-muted-foreground hover:text-foreground"
+                      >
+                        Sign out
+                      </button>
+                    </>
+                  ) : (
+                    <Link
+                      to="/login"
+                      state={{ from: location.pathname }}
+                      className="text-sm font-medium text-muted-foreground hover:text-foreground"
+                    >
+                      Sign in
+                    </Link>
+                  )}
                   <button
                     onClick={toggleTheme}
                     className="p-2 rounded-md bg-secondary text-secondary-foreground hover:bg-secondary/80"
@@ -1,6 +1,7 @@
-import React, { useState } from 'react'
+import React, { useEffect, useState } from 'react'
 import { useNavigate } from 'react-router-dom'
 import axios from 'axios'
+import { API_URL, useAuth } from '../components/AuthProvider'
 import { PlusIcon, MinusIcon } from '@heroicons/react/24/outline'
 
 interface Ingredient {
@@ -9,6 +10,14 @@ interface Ingredient {
   unit: string
 }
 
+// ProblemDetails is the RFC 7807 body the API returns for errors
+interface ProblemDetails {
+  title: string
+  status: number
+  detail?: string
+  errors?: Array<{ field: string; message: string }>
+}
+
 interface RecipeForm {
   name: string
   description: string
@@ -20,6 +29,7 @@ interface RecipeForm {
 
 export default function CreateRecipe() {
   const navigate = useNavigate()
+  const { accessToken, refresh } = useAuth()
   const [formData, setFormData] = useState<RecipeForm>({
     name: '',
     description: '',
@@ -29,6 +39,13 @@ export default function CreateRecipe() {
     instructions: [''],
   })
 
+  // Creating a recipe needs an account
+  useEffect(() => {
+    if (!accessToken) {
+      navigate('/login', { replace: true, state: { from: '/create' } })
+    }
+  }, [accessToken, navigate])
+
   const handleIngredientChange = (index: number, field: keyof Ingredient, value: string) => {
     const newIngredients = [...formData.ingredients]
     newIngredients[index] = { ...newIngredients[index], [field]: value }
@@ -88,13 +105,30 @@ export default function CreateRecipe() {
       }
 
       console.log('Submitting recipe:', formattedData)
-      const response = await axios.post('http://localhost:8080/api/recipes', formattedData, {
-        headers: {
-          'Content-Type': 'application/json',
-          'Accept': 'application/json',
-        },
-        withCredentials: false // Since we're using "*" for CORS
-      })
+      const post = (token: string | null) =>
+        axios.post(`${API_URL}/api/recipes`, formattedData, {
+          headers: {
+            'Content-Type': 'application/json',
+            'Accept': 'application/json',
+            'Authorization': `Bearer ${token}`,
+          },
+          withCredentials: false // Since we're using "*" for CORS
+        })
+      let response
+      try {
+        response = await post(accessToken)
+      } catch (error) {
+        // The access token is short-lived; renew it once and retry
+        if (!axios.isAxiosError(error) || error.response?.status !== 401) {
+          throw error
+        }
+        const renewed = await refresh()
+        if (!renewed) {
+          navigate('/login', { state: { from: '/create' } })
+          return
+        }
+        response = await post(renewed)
+      }
       console.log('Response:', response)
       console.log('Recipe created successfully:', response.data)
       navigate('/')
@@ -104,7 +138,11 @@ export default function CreateRecipe() {
         console.error('Response status:', error.response?.status)
         console.error('Response data:', error.response?.data)
         console.error('Response headers:', error.response?.headers)
-        alert(`Failed to create recipe: ${error.response?.data || error.message}`)
+        const problem = error.response?.data as ProblemDetails | undefined
+        const details = problem?.errors?.length
+          ? problem.errors.map(e => `${e.field}: ${e.message}`).join('\n')
+          : problem?.detail
+        alert(`Failed to create recipe: ${details || error.message}`)
       } else {
         alert('Failed to create recipe. Please check the console for details.')
       }
@@ -19,6 +19,12 @@ interface Recipe {
   instructions: string[]
 }
 
+interface RecipePage {
+  items: Recipe[]
+  next_cursor?: string
+  total: number
+}
+
 export default function Home() {
   const [recipes, setRecipes] = useState<Recipe[]>([])
   const [loading, setLoading] = useState(true)
@@ -27,9 +33,11 @@ export default function Home() {
   useEffect(() => {
     const fetchRecipes = async () => {
       try {
-        const response = await axios.get<Recipe[]>('http://localhost:8080/api/recipes')
+        const response = await axios.get<RecipePage>('http://localhost:8080/api/recipes', {
+          params: { type: 'cocktail', limit: 100 },
+        })
         console.log('Fetched recipes:', response.data)
-        setRecipes(response.data)
+        setRecipes(response.data.items)
         setLoading(false)
       } catch (err) {
         console.error('Error fetching recipes:', err)
@@ -0,0 +1,142 @@
+import React, { useState } from 'react'
+import { useLocation, useNavigate } from 'react-router-dom'
+import axios from 'axios'
+import { useAuth } from '../components/AuthProvider'
+
+// ProblemDetails is the RFC 7807 body the API returns for errors
+interface ProblemDetails {
+  title: string
+  status: number
+  detail?: string
+  errors?: Array<{ field: string; message: string }>
+}
+
+const inputClassName =
+  'block w-full rounded-md border-2 border-primary/50 bg-background shadow-sm focus:border-primary focus:ring-primary sm:text-sm text-foreground placeholder:text-muted-foreground'
+
+export default function Login() {
+  const navigate = useNavigate()
+  const location = useLocation()
+  const { login, register } = useAuth()
+  const [creatingAccount, setCreatingAccount] = useState(false)
+  const [email, setEmail] = useState('')
+  const [password, setPassword] = useState('')
+  const [displayName, setDisplayName] = useState('')
+  const [error, setError] = useState<string | null>(null)
+
+  // Pages that need an account send the reader here and are returned to
+  // once signed in
+  const from = (location.state as { from?: string } | null)?.from ?? '/'
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault()
+    setError(null)
+    try {
+      if (creatingAccount) {
+        await register(email, password, displayName)
+      } else {
+        await login(email, password)
+      }
+      navigate(from, { replace: true })
+    } catch (err) {
+      if (axios.isAxiosError(err)) {
+        const problem = err.response?.data as ProblemDetails | undefined
+        const details = problem?.errors?.length
+          ? problem.errors.map(e => `${e.field}: ${e.message}`).join('\n')
+          : problem?.detail
+        setError(details || err.message)
+      } else {
+        setError('Something went wrong. Please try again.')
+      }
+    }
+  }
+
+  return (
+    <div className="flex-1 flex items-start justify-center px-4 py-12">
+      <div className="w-full max-w-md bg-card shadow-sm rounded-lg p-8">
+        <h3 className="text-2xl font-bold leading-6 text-foreground">
+          {creatingAccount ? 'Create an account' : 'Sign in'}
+        </h3>
+        <p className="mt-2 text-sm text-muted-foreground">
+          {creatingAccount
+            ? 'Create an account to share your own recipes.'
+            : 'Sign in to create and edit recipes.'}
+        </p>
+
+        <form onSubmit={handleSubmit} className="mt-6 space-y-6">
+          {creatingAccount && (
+            <div>
+              <label htmlFor="display_name" className="block text-sm font-medium text-gray-700">
+                Display Name
+              </label>
+              <div className="mt-1">
+                <input
+                  type="text"
+                  id="display_name"
+                  required
+                  value={displayName}
+                  onChange={(e) => setDisplayName(e.target.value)}
+                  className={inputClassName}
+                />
+              </div>
+            </div>
+          )}
+
+          <div>
+            <label htmlFor="email" className="block text-sm font-medium text-gray-700">
+              Email
+            </label>
+            <div className="mt-1">
+              <input
+                type="email"
+                id="email"
+                autoComplete="email"
+                required
+                value={email}
+                onChange={(e) => setEmail(e.target.value)}
+                className={inputClassName}
+              />
+            </div>
+          </div>
+
+          <div>
+            <label htmlFor="password" className="block text-sm font-medium text-gray-700">
+              Password
+            </label>
+            <div className="mt-1">
+              <input
+                type="password"
+                id="password"
+                autoComplete={creatingAccount ? 'new-password' : 'current-password'}
+                required
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
+                className={inputClassName}
+              />
+            </div>
+          </div>
+
+          {error && <p className="whitespace-pre-line text-sm text-red-600">{error}</p>}
+
+          <button
+            type="submit"
+            className="w-full rounded-md bg-primary px-3 py-2 text-sm font-semibold text-primary-foreground shadow-sm hover:bg-primary/90"
+          >
+            {creatingAccount ? 'Create account' : 'Sign in'}
+          </button>
+        </form>
+
+        <button
+          type="button"
+          onClick={() => {
+            setCreatingAccount(!creatingAccount)
+            setError(null)
+          }}
+          className="mt-4 text-sm text-primary hover:underline"
+        >
+          {creatingAccount ? 'Already have an account? Sign in' : 'New here? Create an account'}
+        </button>
+      </div>
+    </div>
+  )
+}